@@ -0,0 +1,26 @@
+// Package logging carries a request-scoped logger through a context so that
+// code deep in a call, such as the Calendar API transport, logs to whoever
+// is interested in that request.
+package logging
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type loggerKey struct{}
+
+// WithLogger returns a context whose log records go to entry
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, entry)
+}
+
+// FromContext returns the logger for ctx, falling back to the standard
+// logrus logger
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.WithContext(ctx)
+}
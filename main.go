@@ -9,49 +9,125 @@ import (
 	"os/signal"
 	"syscall"
 
-	"github.com/phildougherty/mcp-google-calendar-go/internal/config"
-	"github.com/phildougherty/mcp-google-calendar-go/internal/calendar"
-	"github.com/phildougherty/mcp-google-calendar-go/internal/mcp"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/mcp"
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
 	var (
-		port    = flag.Int("port", 8080, "Server port")
-		authCmd = flag.Bool("auth", false, "Run OAuth authentication flow")
-		debug   = flag.Bool("debug", false, "Enable debug logging")
+		port        = flag.Int("port", 8080, "Server port")
+		authCmd     = flag.Bool("auth", false, "Run OAuth authentication flow")
+		debug       = flag.Bool("debug", false, "Enable debug logging")
+		transport   = flag.String("transport", "http", "Transport to serve MCP over (http or stdio)")
+		webhookURL  = flag.String("webhook-url", "", "Public HTTPS URL of the /webhooks/calendar route; enables resource subscriptions")
+		readOnly    = flag.Bool("read-only", false, "Only expose tools that do not modify calendars, and request read-only OAuth scopes with a token of their own")
+		backendName = flag.String("backend", "google", "Calendar backend (google, or memory for an in-memory demo calendar)")
+		toolTimeout = flag.Duration("tool-timeout", 0, "Maximum duration of a tool call, 0 for the configured default (1m), negative for no limit")
+		cacheTTL    = flag.Duration("cache-ttl", 0, "Cache events and serve them this long before checking Google for changes; off by default, negative to turn off a configured cache")
+		listLimit   = flag.Int("list-limit", 0, "Maximum events or calendars per list_events, list_instances or list_calendars call, 0 for the configured default (100)")
+		store       = flag.Bool("store", false, "Save the calendars synced by the event cache on disk, for restarts and -offline; needs -cache-ttl")
+		offline     = flag.Bool("offline", false, "Answer reads from the calendars saved on disk with -store, without contacting Google")
 	)
 	flag.Parse()
 
-	// Configure logging
+	if *transport != "http" && *transport != "stdio" {
+		log.Fatalf("Unknown transport %q: must be http or stdio", *transport)
+	}
+	if *backendName != "google" && *backendName != "memory" {
+		log.Fatalf("Unknown backend %q: must be google or memory", *backendName)
+	}
+	if *authCmd && *backendName != "google" {
+		log.Fatalf("-auth is only needed for the google backend")
+	}
+	if *offline && (*authCmd || *backendName != "google") {
+		log.Fatalf("-offline reads calendars synced from Google and cannot be combined with -auth or another backend")
+	}
+
+	// Configure logging. Logs always go to stderr so that stdout stays
+	// reserved for protocol messages in stdio mode.
 	if *debug {
 		logrus.SetLevel(logrus.DebugLevel)
 	}
 	logrus.SetFormatter(&logrus.JSONFormatter{})
+	logrus.SetOutput(os.Stderr)
 
-	// Load configuration
-	cfg, err := config.Load()
+	// Load configuration. Only the Google backend needs OAuth keys, and
+	// only when it is online.
+	loadConfig := config.Load
+	if *backendName == "memory" || *offline {
+		loadConfig = config.LoadSettings
+	}
+	cfg, err := loadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
-
-	// Initialize Calendar client
-	calendarClient, err := calendar.NewClient(cfg)
-	if err != nil {
-		log.Fatalf("Failed to initialize Calendar client: %v", err)
+	if *webhookURL != "" {
+		cfg.WebhookURL = *webhookURL
+	}
+	if *readOnly {
+		cfg.ReadOnly = true
+	}
+	if *toolTimeout != 0 {
+		cfg.ToolTimeout = *toolTimeout
+	}
+	if *listLimit > 0 {
+		cfg.ListLimit = *listLimit
 	}
+	if *cacheTTL != 0 {
+		cfg.CacheTTL = *cacheTTL
+	}
+	if *store {
+		cfg.Store = true
+	}
+	if cfg.Store && cfg.CacheTTL <= 0 && !*offline && !*authCmd && *backendName == "google" {
+		logrus.Warn("-store saves the event cache, which is off without -cache-ttl; nothing will be saved")
+	}
+
+	var backend calendar.Backend
+	if *backendName == "memory" {
+		backend = calendar.NewMemoryBackend("")
+	} else if *offline {
+		disk, err := calendar.NewDiskStore(cfg.StoreDir())
+		if err != nil {
+			log.Fatalf("Failed to open event store: %v", err)
+		}
+		if backend, err = calendar.NewOfflineBackend(disk); err != nil {
+			log.Fatalf("Failed to load event store: %v", err)
+		}
+		cfg.ReadOnly = true
+	} else {
+		// Initialize Calendar client
+		calendarClient, err := calendar.NewClient(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize Calendar client: %v", err)
+		}
 
-	// Handle auth command
-	if *authCmd {
-		if err := calendarClient.Authenticate(); err != nil {
-			log.Fatalf("Authentication failed: %v", err)
+		// Handle auth command
+		if *authCmd {
+			if err := calendarClient.Authenticate(); err != nil {
+				log.Fatalf("Authentication failed: %v", err)
+			}
+			fmt.Println("Authentication successful!")
+			return
+		}
+		backend = calendarClient
+		if cfg.CacheTTL > 0 {
+			// Serve repeated reads from synced copies of the calendars, and
+			// save them for restarts and -offline when asked to
+			var disk *calendar.DiskStore
+			if cfg.Store {
+				if disk, err = calendar.NewDiskStore(cfg.StoreDir()); err != nil {
+					log.Fatalf("Failed to open event store: %v", err)
+				}
+			}
+			backend = calendar.NewCachedBackend(calendarClient, cfg.CacheTTL, disk)
 		}
-		fmt.Println("Authentication successful!")
-		return
 	}
 
 	// Create MCP server
-	server := mcp.NewServer(calendarClient, *port)
+	server := mcp.NewServer(backend, cfg, *port)
 
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -66,10 +142,17 @@ func main() {
 	}()
 
 	// Start server
-	logrus.Infof("Starting Google Calendar MCP server on port %d", *port)
-	if err := server.Start(ctx); err != nil {
-		log.Fatalf("Server failed: %v", err)
+	if *transport == "stdio" {
+		logrus.Info("Starting Google Calendar MCP server on stdio")
+		if err := server.ServeStdio(ctx, os.Stdin, os.Stdout); err != nil {
+			log.Fatalf("Server failed: %v", err)
+		}
+	} else {
+		logrus.Infof("Starting Google Calendar MCP server on port %d", *port)
+		if err := server.Start(ctx); err != nil {
+			log.Fatalf("Server failed: %v", err)
+		}
 	}
 
 	logrus.Info("Server shutdown complete")
-}
\ No newline at end of file
+}
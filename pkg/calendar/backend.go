@@ -0,0 +1,65 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// Backend is the calendar store the MCP server operates on. Client
+// implements it against the Google Calendar API.
+//
+// Listings return at most MaxResults items, or all of them when it is zero,
+// and a cursor to continue them through the Cursor argument when more
+// remain. Cursors are opaque and only valid for the listing they came from.
+type Backend interface {
+	CreateEvent(ctx context.Context, args *types.CreateEventArgs) (string, error)
+	GetEvent(ctx context.Context, calendarID, eventID string) (*types.CalendarEvent, error)
+	UpdateEvent(ctx context.Context, args *types.UpdateEventArgs) error
+	DeleteEvent(ctx context.Context, calendarID, eventID string) error
+	ListEvents(ctx context.Context, args *types.ListEventsArgs) ([]*types.CalendarEvent, string, error)
+	ListInstances(ctx context.Context, args *types.ListInstancesArgs) ([]*types.CalendarEvent, string, error)
+
+	ListCalendars(ctx context.Context, args *types.ListCalendarsArgs) ([]*types.Calendar, string, error)
+	GetCalendar(ctx context.Context, calendarID string) (*types.Calendar, error)
+	CreateCalendar(ctx context.Context, args *types.CreateCalendarArgs) (string, error)
+	DeleteCalendar(ctx context.Context, calendarID string) error
+
+	GetFreeBusy(ctx context.Context, args *types.FreeBusyArgs) (*types.FreeBusyResponse, error)
+}
+
+// Watcher is implemented by backends that can push change notifications to
+// a webhook. Resource subscriptions are only offered on such backends.
+type Watcher interface {
+	WatchEvents(ctx context.Context, calendarID, channelID, address, token string, ttl time.Duration) (*types.WatchChannel, error)
+	StopChannel(ctx context.Context, channel *types.WatchChannel) error
+}
+
+// Syncer is implemented by backends that can list the changes to a
+// calendar's events since an earlier listing. A CachedBackend keeps its
+// copies of calendars current through it.
+type Syncer interface {
+	// SyncEvents returns the events that changed since the listing
+	// syncToken came from, or every event when syncToken is empty, and the
+	// token to pass next time. Changes include cancelled events.
+	SyncEvents(ctx context.Context, calendarID, syncToken string) ([]*types.CalendarEvent, string, error)
+}
+
+// ErrFullSyncRequired is returned by SyncEvents when a sync token has
+// expired, and the calendar has to be synced from scratch
+var ErrFullSyncRequired = errors.New("sync token is no longer valid, a full sync is required")
+
+// Authenticator is implemented by backends that need stored credentials
+// before they can serve requests
+type Authenticator interface {
+	IsAuthenticated() bool
+}
+
+var (
+	_ Backend       = (*Client)(nil)
+	_ Watcher       = (*Client)(nil)
+	_ Syncer        = (*Client)(nil)
+	_ Authenticator = (*Client)(nil)
+)
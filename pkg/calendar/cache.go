@@ -0,0 +1,379 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/internal/logging"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+	"google.golang.org/api/googleapi"
+)
+
+// SyncBackend is a Backend that can also sync events, as Client can
+type SyncBackend interface {
+	Backend
+	Syncer
+}
+
+// CachedBackend is a Backend that serves events from local copies of the
+// calendars it is asked about. A calendar is copied in full the first time
+// it is read, and afterwards kept current with incremental syncs, which
+// cost one request that usually returns nothing. Reads within ttl of the
+// last sync are served without any request at all.
+//
+// Writes go straight to the remote backend and make every copy sync before
+// it is read again, so changes made through the CachedBackend are seen at
+// once; changes made elsewhere are seen within ttl. Calendars, free/busy
+// queries and anything the copies cannot answer are passed through, and so
+// are the events of calendars with events the copies cannot index, such as
+// series with recurrence rules they cannot expand.
+//
+// With a DiskStore, every sync and complete calendar listing is saved, and
+// copies start from the saved state after a restart.
+type CachedBackend struct {
+	SyncBackend
+	ttl  time.Duration
+	now  func() time.Time
+	disk *DiskStore // nil when copies only live in memory
+
+	mu         sync.Mutex
+	calendars  map[string]*cachedCalendar
+	generation int64 // incremented by every write
+}
+
+// cachedCalendar is the local copy of one calendar
+type cachedCalendar struct {
+	mu         sync.Mutex // held while syncing
+	calendar   *types.Calendar
+	store      *MemoryBackend
+	syncToken  string
+	synced     time.Time
+	generation int64 // of the CachedBackend when the sync started
+
+	// unindexed holds the IDs of synced events the copy cannot index, such
+	// as series with rules it cannot expand. Reads go to the remote backend
+	// while there are any, since the copy would silently miss them.
+	unindexed map[string]bool
+}
+
+// NewCachedBackend caches the events of remote, serving them without a sync
+// for ttl. The copies are also saved to disk unless disk is nil.
+func NewCachedBackend(remote SyncBackend, ttl time.Duration, disk *DiskStore) *CachedBackend {
+	return &CachedBackend{
+		SyncBackend: remote,
+		ttl:         ttl,
+		now:         time.Now,
+		disk:        disk,
+		calendars:   make(map[string]*cachedCalendar),
+	}
+}
+
+// GetEvent retrieves an event from the cached calendar, or from the remote
+// backend if the copy does not have it
+func (b *CachedBackend) GetEvent(ctx context.Context, calendarID, eventID string) (*types.CalendarEvent, error) {
+	store, err := b.sync(ctx, calendarID)
+	if err != nil {
+		return b.SyncBackend.GetEvent(ctx, calendarID, eventID)
+	}
+	event, err := store.GetEvent(ctx, cacheKey(calendarID), eventID)
+	if isNotFound(err) {
+		return b.SyncBackend.GetEvent(ctx, calendarID, eventID)
+	}
+	return event, err
+}
+
+// ListEvents lists the events of the cached calendar
+func (b *CachedBackend) ListEvents(ctx context.Context, args *types.ListEventsArgs) ([]*types.CalendarEvent, string, error) {
+	store, err := b.sync(ctx, args.CalendarID)
+	if err != nil {
+		return b.SyncBackend.ListEvents(ctx, args)
+	}
+	local := *args
+	local.CalendarID = cacheKey(args.CalendarID)
+	return store.ListEvents(ctx, &local)
+}
+
+// ListInstances lists the instances of a recurring event in the cached
+// calendar
+func (b *CachedBackend) ListInstances(ctx context.Context, args *types.ListInstancesArgs) ([]*types.CalendarEvent, string, error) {
+	store, err := b.sync(ctx, args.CalendarID)
+	if err != nil {
+		return b.SyncBackend.ListInstances(ctx, args)
+	}
+	local := *args
+	local.CalendarID = cacheKey(args.CalendarID)
+	instances, next, err := store.ListInstances(ctx, &local)
+	if isNotFound(err) {
+		return b.SyncBackend.ListInstances(ctx, args)
+	}
+	return instances, next, err
+}
+
+// ListCalendars lists calendars on the remote backend, saving complete
+// listings for offline use
+func (b *CachedBackend) ListCalendars(ctx context.Context, args *types.ListCalendarsArgs) ([]*types.Calendar, string, error) {
+	calendars, next, err := b.SyncBackend.ListCalendars(ctx, args)
+	if err == nil && b.disk != nil && args.Cursor == "" && next == "" {
+		snapshot := &calendarListSnapshot{SyncedAt: b.now(), Calendars: calendars}
+		if err := b.disk.saveCalendarList(snapshot); err != nil {
+			logging.FromContext(ctx).WithError(err).Warn("Failed to save calendar list")
+		}
+	}
+	return calendars, next, err
+}
+
+// CreateEvent creates an event on the remote backend
+func (b *CachedBackend) CreateEvent(ctx context.Context, args *types.CreateEventArgs) (string, error) {
+	defer b.Invalidate()
+	return b.SyncBackend.CreateEvent(ctx, args)
+}
+
+// UpdateEvent updates an event on the remote backend
+func (b *CachedBackend) UpdateEvent(ctx context.Context, args *types.UpdateEventArgs) error {
+	defer b.Invalidate()
+	return b.SyncBackend.UpdateEvent(ctx, args)
+}
+
+// DeleteEvent deletes an event on the remote backend
+func (b *CachedBackend) DeleteEvent(ctx context.Context, calendarID, eventID string) error {
+	defer b.Invalidate()
+	return b.SyncBackend.DeleteEvent(ctx, calendarID, eventID)
+}
+
+// DeleteCalendar deletes a calendar on the remote backend and drops its
+// copy
+func (b *CachedBackend) DeleteCalendar(ctx context.Context, calendarID string) error {
+	if err := b.SyncBackend.DeleteCalendar(ctx, calendarID); err != nil {
+		return err
+	}
+
+	key := cacheKey(calendarID)
+	b.mu.Lock()
+	delete(b.calendars, key)
+	b.mu.Unlock()
+	b.Invalidate()
+	if b.disk != nil {
+		if err := b.disk.removeCalendar(key); err != nil {
+			logging.FromContext(ctx).WithError(err).Warn("Failed to remove deleted calendar from store")
+		}
+	}
+	return nil
+}
+
+// WatchEvents passes through to the remote backend, if it is a Watcher
+func (b *CachedBackend) WatchEvents(ctx context.Context, calendarID, channelID, address, token string, ttl time.Duration) (*types.WatchChannel, error) {
+	watcher, ok := b.SyncBackend.(Watcher)
+	if !ok {
+		return nil, fmt.Errorf("calendar backend cannot push notifications")
+	}
+	return watcher.WatchEvents(ctx, calendarID, channelID, address, token, ttl)
+}
+
+// StopChannel passes through to the remote backend, if it is a Watcher
+func (b *CachedBackend) StopChannel(ctx context.Context, channel *types.WatchChannel) error {
+	watcher, ok := b.SyncBackend.(Watcher)
+	if !ok {
+		return fmt.Errorf("calendar backend cannot push notifications")
+	}
+	return watcher.StopChannel(ctx, channel)
+}
+
+// IsAuthenticated passes through to the remote backend, if it is an
+// Authenticator
+func (b *CachedBackend) IsAuthenticated() bool {
+	if auth, ok := b.SyncBackend.(Authenticator); ok {
+		return auth.IsAuthenticated()
+	}
+	return true
+}
+
+// Invalidate makes every cached calendar sync before it is read again, for
+// when the remote calendars are known to have changed
+func (b *CachedBackend) Invalidate() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.generation++
+}
+
+// sync returns the copy of a calendar, syncing it first unless it is fresh.
+// A copy whose sync token has expired is replaced by a full sync. Errors are
+// logged; callers fall back to the remote backend.
+func (b *CachedBackend) sync(ctx context.Context, calendarID string) (*MemoryBackend, error) {
+	key := cacheKey(calendarID)
+	b.mu.Lock()
+	cal, ok := b.calendars[key]
+	if !ok {
+		cal = &cachedCalendar{}
+		b.calendars[key] = cal
+	}
+	generation := b.generation
+	b.mu.Unlock()
+
+	cal.mu.Lock()
+	defer cal.mu.Unlock()
+
+	log := logging.FromContext(ctx).WithField("calendar_id", key)
+	if cal.store == nil && b.disk != nil {
+		// Start from where the last run left off. Saved copies only count
+		// as fresh if no write happened since this backend started.
+		if err := b.restore(cal, key); err != nil {
+			log.WithError(err).Warn("Failed to restore cached calendar from store")
+		}
+	}
+	if cal.store != nil && cal.generation == generation && b.now().Sub(cal.synced) < b.ttl {
+		return cal.readable()
+	}
+
+	started := b.now()
+	err := ErrFullSyncRequired
+	if cal.store != nil {
+		err = b.syncChanges(ctx, cal, key)
+		if errors.Is(err, ErrFullSyncRequired) {
+			log.Info("Sync token expired, syncing cached calendar in full")
+		}
+	}
+	if errors.Is(err, ErrFullSyncRequired) {
+		err = b.syncAll(ctx, cal, key)
+	}
+	if err != nil {
+		log.WithError(err).Warn("Failed to sync cached calendar, reading it from the API")
+		return nil, err
+	}
+	cal.synced, cal.generation = started, generation
+
+	if b.disk != nil && len(cal.unindexed) == 0 {
+		if err := b.save(cal, key); err != nil {
+			log.WithError(err).Warn("Failed to save cached calendar")
+		}
+	}
+	return cal.readable()
+}
+
+// readable returns the copy of a calendar, unless it misses events. Callers
+// hold cal.mu.
+func (cal *cachedCalendar) readable() (*MemoryBackend, error) {
+	if len(cal.unindexed) > 0 {
+		return nil, fmt.Errorf("%d events cannot be cached", len(cal.unindexed))
+	}
+	return cal.store, nil
+}
+
+// syncAll replaces the copy of a calendar with a full sync. Callers hold
+// cal.mu.
+func (b *CachedBackend) syncAll(ctx context.Context, cal *cachedCalendar, key string) error {
+	// All-day events are read in the calendar's time zone
+	remote, err := b.SyncBackend.GetCalendar(ctx, key)
+	if err != nil {
+		return err
+	}
+	events, syncToken, err := b.SyncBackend.SyncEvents(ctx, key, "")
+	if err != nil {
+		return err
+	}
+
+	store := newCalendarStore(key, remote)
+	unindexed := make(map[string]bool)
+	if err := b.put(ctx, store, key, events, unindexed); err != nil {
+		return err
+	}
+	cal.calendar, cal.store, cal.syncToken, cal.unindexed = remote, store, syncToken, unindexed
+	return nil
+}
+
+// syncChanges applies the changes since the last sync to the copy of a
+// calendar. Callers hold cal.mu.
+func (b *CachedBackend) syncChanges(ctx context.Context, cal *cachedCalendar, key string) error {
+	events, syncToken, err := b.SyncBackend.SyncEvents(ctx, key, cal.syncToken)
+	if err != nil {
+		return err
+	}
+	if cal.unindexed == nil {
+		cal.unindexed = make(map[string]bool)
+	}
+	if err := b.put(ctx, cal.store, key, events, cal.unindexed); err != nil {
+		return err
+	}
+	cal.syncToken = syncToken
+	return nil
+}
+
+// restore loads the saved copy of a calendar, if there is one. Callers hold
+// cal.mu.
+func (b *CachedBackend) restore(cal *cachedCalendar, key string) error {
+	snapshot, err := b.disk.loadCalendar(key)
+	if err != nil || snapshot == nil || snapshot.Calendar == nil {
+		return err
+	}
+	store := newCalendarStore(key, snapshot.Calendar)
+	if _, err := store.putEvents(key, snapshot.Events); err != nil {
+		return err
+	}
+	cal.calendar, cal.store, cal.syncToken, cal.synced = snapshot.Calendar, store, snapshot.SyncToken, snapshot.SyncedAt
+	return nil
+}
+
+// save writes the copy of a calendar to disk. Callers hold cal.mu.
+func (b *CachedBackend) save(cal *cachedCalendar, key string) error {
+	events, err := cal.store.syncedEvents(key)
+	if err != nil {
+		return err
+	}
+	return b.disk.saveCalendar(key, &calendarSnapshot{
+		Calendar:  cal.calendar,
+		SyncToken: cal.syncToken,
+		SyncedAt:  cal.synced,
+		Events:    events,
+	})
+}
+
+// put stores synced events in a copy, keeping unindexed current: events
+// the copy could not index are added to it, and events it now holds or that
+// were cancelled are removed
+func (b *CachedBackend) put(ctx context.Context, store *MemoryBackend, key string, events []*types.CalendarEvent, unindexed map[string]bool) error {
+	skipped, err := store.putEvents(key, events)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		delete(unindexed, event.ID)
+	}
+	for _, id := range skipped {
+		unindexed[id] = true
+	}
+	if len(skipped) > 0 {
+		logging.FromContext(ctx).WithField("event_ids", skipped).Warn("Cannot cache some events, reading their calendar from the API")
+	}
+	return nil
+}
+
+// newCalendarStore returns an empty copy of cal under key. All-day events
+// are read in the calendar's time zone.
+func newCalendarStore(key string, cal *types.Calendar) *MemoryBackend {
+	store := NewMemoryBackend(key)
+	store.calendars[key].calendar.TimeZone = cal.TimeZone
+	return store
+}
+
+// cacheKey names the copy of a calendar. The primary calendar is cached
+// under its alias.
+func cacheKey(calendarID string) string {
+	if calendarID == "" {
+		return "primary"
+	}
+	return calendarID
+}
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+var (
+	_ SyncBackend   = (*CachedBackend)(nil)
+	_ Watcher       = (*CachedBackend)(nil)
+	_ Authenticator = (*CachedBackend)(nil)
+)
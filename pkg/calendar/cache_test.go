@@ -0,0 +1,247 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar/calendartest"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+	gcalendar "google.golang.org/api/calendar/v3"
+)
+
+// cacheFixture holds a CachedBackend with a one minute TTL over a Client
+// talking to a fake Calendar API, and the clock the cache reads
+type cacheFixture struct {
+	cache *CachedBackend
+	fake  *calendartest.Server
+	now   time.Time
+	seen  int // requests the fake had received at the last reads call
+}
+
+func newCacheFixture(t *testing.T, disk *DiskStore) *cacheFixture {
+	t.Helper()
+	fake := calendartest.NewServer()
+	t.Cleanup(fake.Close)
+
+	client, err := NewClient(&config.Config{}, WithEndpoint(fake.Endpoint()), WithHTTPClient(fake.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	f := &cacheFixture{
+		cache: NewCachedBackend(client, time.Minute, disk),
+		fake:  fake,
+		now:   time.Date(2025, time.January, 6, 8, 0, 0, 0, time.UTC),
+	}
+	f.cache.now = func() time.Time { return f.now }
+	return f
+}
+
+// reads describes the event requests the fake received since the last
+// call: "full" and "sync" for full and incremental syncs, "api" for reads
+// passed through to the API
+func (f *cacheFixture) reads() []string {
+	requests := f.fake.Requests()
+	var out []string
+	for _, request := range requests[f.seen:] {
+		switch {
+		case !strings.HasPrefix(request, "GET ") || !strings.Contains(request, "/events"):
+		case strings.Contains(request, "syncToken="):
+			out = append(out, "sync")
+		case strings.Contains(request, "/events?alt=json&maxResults=2500&prettyPrint=false"):
+			out = append(out, "full")
+		default:
+			out = append(out, "api")
+		}
+	}
+	f.seen = len(requests)
+	return out
+}
+
+// summaries lists the events of a calendar through the cache
+func (f *cacheFixture) summaries(t *testing.T, calendarID string) []string {
+	t.Helper()
+	events, _, err := f.cache.ListEvents(context.Background(), &types.ListEventsArgs{CalendarID: calendarID})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	var out []string
+	for _, event := range events {
+		out = append(out, event.Summary)
+	}
+	return out
+}
+
+func TestCachedBackendSync(t *testing.T) {
+	f := newCacheFixture(t, nil)
+	ctx := context.Background()
+	create := func(summary string) {
+		t.Helper()
+		if _, err := f.cache.CreateEvent(ctx, &types.CreateEventArgs{
+			Summary:   summary,
+			StartTime: "2025-01-06T10:00:00Z",
+			EndTime:   "2025-01-06T11:00:00Z",
+		}); err != nil {
+			t.Fatalf("CreateEvent(%s) error = %v", summary, err)
+		}
+	}
+	create("Planning")
+
+	steps := []struct {
+		name  string
+		setup func()
+		want  []string // summaries listed
+		reads []string
+	}{
+		{
+			name:  "first read syncs in full",
+			want:  []string{"Planning"},
+			reads: []string{"full"},
+		},
+		{
+			name: "fresh copy",
+			setup: func() {
+				f.now = f.now.Add(30 * time.Second)
+			},
+			want: []string{"Planning"},
+		},
+		{
+			name: "changes made elsewhere wait for the TTL",
+			setup: func() {
+				f.fake.AddEvent("primary", &gcalendar.Event{
+					Summary: "Elsewhere",
+					Start:   &gcalendar.EventDateTime{DateTime: "2025-01-06T12:00:00Z"},
+					End:     &gcalendar.EventDateTime{DateTime: "2025-01-06T13:00:00Z"},
+				})
+			},
+			want: []string{"Planning"},
+		},
+		{
+			name: "expired copy syncs incrementally",
+			setup: func() {
+				f.now = f.now.Add(time.Minute)
+			},
+			want:  []string{"Planning", "Elsewhere"},
+			reads: []string{"sync"},
+		},
+		{
+			name: "writes invalidate the copy",
+			setup: func() {
+				create("Review")
+				f.reads()
+			},
+			want:  []string{"Planning", "Elsewhere", "Review"},
+			reads: []string{"sync"},
+		},
+		{
+			name: "expired sync token syncs in full",
+			setup: func() {
+				f.fake.ExpireSyncTokens()
+				f.now = f.now.Add(time.Minute)
+			},
+			want:  []string{"Planning", "Elsewhere", "Review"},
+			reads: []string{"sync", "full"},
+		},
+		{
+			name: "failed sync reads from the API",
+			setup: func() {
+				f.now = f.now.Add(time.Minute)
+				f.fake.FailNext(http.StatusBadRequest, 1)
+			},
+			want:  []string{"Planning", "Elsewhere", "Review"},
+			reads: []string{"sync", "api"},
+		},
+	}
+
+	for _, step := range steps {
+		if step.setup != nil {
+			step.setup()
+		}
+		if got := f.summaries(t, "primary"); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: ListEvents() = %q, want %q", step.name, got, step.want)
+		}
+		if got := f.reads(); !reflect.DeepEqual(got, step.reads) {
+			t.Errorf("%s: reads = %q, want %q", step.name, got, step.reads)
+		}
+	}
+}
+
+func TestCachedBackendDeleteCalendar(t *testing.T) {
+	f := newCacheFixture(t, nil)
+	ctx := context.Background()
+
+	calendarID, err := f.cache.CreateCalendar(ctx, &types.CreateCalendarArgs{Summary: "Team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.cache.CreateEvent(ctx, &types.CreateEventArgs{
+		CalendarID: calendarID,
+		Summary:    "Offsite",
+		StartTime:  "2025-01-06T10:00:00Z",
+		EndTime:    "2025-01-06T11:00:00Z",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.summaries(t, calendarID); !reflect.DeepEqual(got, []string{"Offsite"}) {
+		t.Fatalf("ListEvents() = %q, want [Offsite]", got)
+	}
+
+	if err := f.cache.DeleteCalendar(ctx, calendarID); err != nil {
+		t.Fatalf("DeleteCalendar() error = %v", err)
+	}
+	f.cache.mu.Lock()
+	_, cached := f.cache.calendars[calendarID]
+	f.cache.mu.Unlock()
+	if cached {
+		t.Error("deleted calendar is still cached")
+	}
+	if _, _, err := f.cache.ListEvents(ctx, &types.ListEventsArgs{CalendarID: calendarID}); !isNotFound(err) {
+		t.Errorf("ListEvents() of a deleted calendar error = %v, want a 404", err)
+	}
+}
+
+func TestCachedBackendUnindexedEvents(t *testing.T) {
+	f := newCacheFixture(t, nil)
+	ctx := context.Background()
+
+	if _, err := f.cache.CreateEvent(ctx, &types.CreateEventArgs{
+		Summary:   "Planning",
+		StartTime: "2025-01-06T10:00:00Z",
+		EndTime:   "2025-01-06T11:00:00Z",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Google accepts BYSETPOS, which the cache cannot expand
+	monthEnd := f.fake.AddEvent("primary", &gcalendar.Event{
+		Summary:    "Month end",
+		Start:      &gcalendar.EventDateTime{DateTime: "2025-01-31T16:00:00Z", TimeZone: "UTC"},
+		End:        &gcalendar.EventDateTime{DateTime: "2025-01-31T17:00:00Z", TimeZone: "UTC"},
+		Recurrence: []string{"RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1"},
+	})
+
+	want := []string{"Planning", "Month end"}
+	if got := f.summaries(t, "primary"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListEvents() = %q, want %q", got, want)
+	}
+	if event, err := f.cache.GetEvent(ctx, "primary", monthEnd); err != nil || event.Summary != "Month end" {
+		t.Errorf("GetEvent() = %+v, %v; want the series", event, err)
+	}
+	if got := f.reads(); !reflect.DeepEqual(got, []string{"full", "api", "api"}) {
+		t.Errorf("reads = %q, want a full sync and then only the API", got)
+	}
+
+	// Once the series is gone, the copy is complete again
+	if err := f.cache.DeleteEvent(ctx, "primary", monthEnd); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.summaries(t, "primary"); !reflect.DeepEqual(got, []string{"Planning"}) {
+		t.Errorf("ListEvents() after delete = %q, want [Planning]", got)
+	}
+	if got := f.reads(); !reflect.DeepEqual(got, []string{"sync"}) {
+		t.Errorf("reads after delete = %q, want only a sync", got)
+	}
+}
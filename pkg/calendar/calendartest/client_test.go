@@ -0,0 +1,296 @@
+package calendartest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar/calendartest"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+	"google.golang.org/api/googleapi"
+)
+
+// newClient starts a fake Calendar API and returns a Client talking to it
+func newClient(t *testing.T) (*calendar.Client, *calendartest.Server) {
+	t.Helper()
+	fake := calendartest.NewServer()
+	t.Cleanup(fake.Close)
+
+	client, err := calendar.NewClient(&config.Config{},
+		calendar.WithEndpoint(fake.Endpoint()),
+		calendar.WithHTTPClient(fake.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client, fake
+}
+
+// createEvents creates n half-hour events summarised "event 0" onwards,
+// an hour apart from 2025-01-06T00:00:00Z
+func createEvents(t *testing.T, b calendar.Backend, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		hour := i % 24
+		day := 6 + i/24
+		if _, err := b.CreateEvent(context.Background(), &types.CreateEventArgs{
+			Summary:   fmt.Sprintf("event %d", i),
+			StartTime: fmt.Sprintf("2025-01-%02dT%02d:00:00Z", day, hour),
+			EndTime:   fmt.Sprintf("2025-01-%02dT%02d:30:00Z", day, hour),
+		}); err != nil {
+			t.Fatalf("CreateEvent(event %d) error = %v", i, err)
+		}
+	}
+}
+
+// statusCode returns the HTTP status of a Calendar API error, or 0
+func statusCode(err error) int {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
+func TestClientEvents(t *testing.T) {
+	client, fake := newClient(t)
+	ctx := context.Background()
+
+	id, err := client.CreateEvent(ctx, &types.CreateEventArgs{
+		Summary:   "Planning",
+		Location:  "Room 1",
+		StartTime: "2025-01-06T10:00:00Z",
+		EndTime:   "2025-01-06T11:00:00Z",
+		Attendees: []string{"bob@example.com"},
+		Reminders: []*types.EventReminder{{Method: "popup", Minutes: 10}},
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent() error = %v", err)
+	}
+
+	event, err := client.GetEvent(ctx, "primary", id)
+	if err != nil {
+		t.Fatalf("GetEvent() error = %v", err)
+	}
+	if event.Summary != "Planning" || event.Location != "Room 1" || event.StartTime != "2025-01-06T10:00:00Z" {
+		t.Errorf("GetEvent() = %+v, want the created event", event)
+	}
+	if event.Organizer != fake.Owner {
+		t.Errorf("organizer = %q, want %q", event.Organizer, fake.Owner)
+	}
+	if len(event.Attendees) != 1 || event.Attendees[0].Email != "bob@example.com" || event.Attendees[0].ResponseStatus != "needsAction" {
+		t.Errorf("attendees = %+v, want bob@example.com needing action", event.Attendees)
+	}
+	if len(event.Reminders) != 1 || event.Reminders[0].Method != "popup" || event.Reminders[0].Minutes != 10 {
+		t.Errorf("reminders = %+v, want a popup 10 minutes before", event.Reminders)
+	}
+
+	if err := client.UpdateEvent(ctx, &types.UpdateEventArgs{EventID: id, Summary: "Planning (moved)"}); err != nil {
+		t.Fatalf("UpdateEvent() error = %v", err)
+	}
+	events, _, err := client.ListEvents(ctx, &types.ListEventsArgs{Query: "moved"})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(events) != 1 || events[0].ID != id || events[0].Location != "Room 1" {
+		t.Errorf("ListEvents() = %+v, want the updated event with its location kept", events)
+	}
+
+	if err := client.DeleteEvent(ctx, "primary", id); err != nil {
+		t.Fatalf("DeleteEvent() error = %v", err)
+	}
+	// Google still returns deleted events, as cancelled
+	if event, err := client.GetEvent(ctx, "primary", id); err != nil || event.Status != "cancelled" {
+		t.Fatalf("GetEvent() of a deleted event = %+v, %v; want it cancelled", event, err)
+	}
+	events, _, err = client.ListEvents(ctx, &types.ListEventsArgs{})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("ListEvents() after delete = %d events, want none", len(events))
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	client, fake := newClient(t)
+	ctx := context.Background()
+
+	id, err := client.CreateEvent(ctx, &types.CreateEventArgs{Summary: "x", StartTime: "2025-01-06T10:00:00Z", EndTime: "2025-01-06T11:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := client.CreateEvent(ctx, &types.CreateEventArgs{Summary: "y", StartTime: "2025-01-06T10:00:00Z", EndTime: "2025-01-06T11:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteEvent(ctx, "primary", deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		failNext int // status the fake answers the first request with
+		call     func() error
+		want     int
+	}{
+		{
+			name: "missing event",
+			call: func() error {
+				_, err := client.GetEvent(ctx, "primary", "missing")
+				return err
+			},
+			want: http.StatusNotFound,
+		},
+		{
+			name: "missing calendar",
+			call: func() error {
+				_, _, err := client.ListEvents(ctx, &types.ListEventsArgs{CalendarID: "nobody@example.com"})
+				return err
+			},
+			want: http.StatusNotFound,
+		},
+		{
+			name: "deleted event",
+			call: func() error {
+				return client.DeleteEvent(ctx, "primary", deleted)
+			},
+			want: http.StatusGone,
+		},
+		{
+			name: "end before start",
+			call: func() error {
+				return client.UpdateEvent(ctx, &types.UpdateEventArgs{EventID: id, StartTime: "2025-01-07T10:00:00Z", EndTime: "2025-01-07T09:00:00Z"})
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "primary calendar",
+			call: func() error {
+				return client.DeleteCalendar(ctx, "primary")
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name:     "read retried",
+			failNext: http.StatusServiceUnavailable,
+			call: func() error {
+				_, err := client.GetEvent(ctx, "primary", id)
+				return err
+			},
+		},
+		{
+			name:     "write not retried",
+			failNext: http.StatusServiceUnavailable,
+			call: func() error {
+				return client.DeleteEvent(ctx, "primary", id)
+			},
+			want: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.failNext != 0 {
+				fake.FailNext(tt.failNext, 1)
+			}
+			err := tt.call()
+			if tt.want == 0 {
+				if err != nil {
+					t.Errorf("error = %v, want none", err)
+				}
+				return
+			}
+			if got := statusCode(err); got != tt.want {
+				t.Errorf("error = %v, want status %d", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientSyncEvents(t *testing.T) {
+	client, fake := newClient(t)
+	ctx := context.Background()
+	createEvents(t, client, 3)
+
+	events, token, err := client.SyncEvents(ctx, "primary", "")
+	if err != nil {
+		t.Fatalf("SyncEvents() error = %v", err)
+	}
+	if len(events) != 3 || token == "" {
+		t.Fatalf("full sync = %d events, token %q; want 3 events and a token", len(events), token)
+	}
+
+	id, err := client.CreateEvent(ctx, &types.CreateEventArgs{Summary: "new", StartTime: "2025-01-09T10:00:00Z", EndTime: "2025-01-09T11:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, next, err := client.SyncEvents(ctx, "primary", token)
+	if err != nil {
+		t.Fatalf("SyncEvents(token) error = %v", err)
+	}
+	if len(events) != 1 || events[0].ID != id {
+		t.Errorf("incremental sync = %d events, want only the new one", len(events))
+	}
+	if next == "" {
+		t.Error("incremental sync returned no token")
+	}
+
+	fake.ExpireSyncTokens()
+	if _, _, err := client.SyncEvents(ctx, "primary", next); !errors.Is(err, calendar.ErrFullSyncRequired) {
+		t.Errorf("SyncEvents(expired token) error = %v, want %v", err, calendar.ErrFullSyncRequired)
+	}
+}
+
+func TestClientFollowsPages(t *testing.T) {
+	client, fake := newClient(t)
+	ctx := context.Background()
+
+	// More than the 250 events Google returns on one page by default
+	createEvents(t, client, 260)
+
+	events, cursor, err := client.ListEvents(ctx, &types.ListEventsArgs{})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(events) != 260 || cursor != "" {
+		t.Errorf("ListEvents() = %d events, cursor %q; want all 260 and no cursor", len(events), cursor)
+	}
+
+	pages := 0
+	for _, request := range fake.Requests() {
+		if strings.HasPrefix(request, "GET ") && strings.Contains(request, "/events?") && strings.Contains(request, "pageToken=") {
+			pages++
+		}
+	}
+	if pages != 1 {
+		t.Errorf("%d requests followed nextPageToken, want 1", pages)
+	}
+
+	// Free/busy queries are split into batches Google accepts
+	calendarIDs := []string{"primary"}
+	for i := 0; i < 60; i++ {
+		calendarIDs = append(calendarIDs, fmt.Sprintf("user%d@example.com", i))
+	}
+	response, err := client.GetFreeBusy(ctx, &types.FreeBusyArgs{
+		TimeMin:     "2025-01-06T00:00:00Z",
+		TimeMax:     "2025-01-06T02:00:00Z",
+		CalendarIDs: calendarIDs,
+	})
+	if err != nil {
+		t.Fatalf("GetFreeBusy() error = %v", err)
+	}
+	if len(response.Calendars) != len(calendarIDs) {
+		t.Errorf("GetFreeBusy() answered for %d calendars, want %d", len(response.Calendars), len(calendarIDs))
+	}
+	if busy := response.Calendars["primary"].Busy; len(busy) != 2 {
+		t.Errorf("primary busy = %v, want the 2 events in range", busy)
+	}
+	if errs := response.Calendars["user0@example.com"].Errors; len(errs) != 1 || errs[0] != "notFound" {
+		t.Errorf("unknown calendar errors = %v, want [notFound]", errs)
+	}
+}
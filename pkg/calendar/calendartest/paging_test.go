@@ -0,0 +1,180 @@
+package calendartest_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// pagingBackends returns a Client on the fake and a MemoryBackend, which
+// must page alike
+func pagingBackends(t *testing.T) map[string]calendar.Backend {
+	client, _ := newClient(t)
+	return map[string]calendar.Backend{
+		"client": client,
+		"memory": calendar.NewMemoryBackend(""),
+	}
+}
+
+func TestListEventsCursor(t *testing.T) {
+	for name, b := range pagingBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			createEvents(t, b, 23)
+			var want []string
+			for i := 0; i < 23; i++ {
+				want = append(want, fmt.Sprintf("event %d", i))
+			}
+
+			tests := []struct {
+				first, rest int // maxResults of the first and later pages
+				calls       int
+			}{
+				{first: 5, rest: 5, calls: 5},
+				{first: 10, rest: 3, calls: 6},
+				{first: 23, rest: 23, calls: 1},
+				{first: 50, rest: 50, calls: 1},
+			}
+			for _, tt := range tests {
+				var got []string
+				cursor := ""
+				calls := 0
+				for {
+					maxResults := tt.rest
+					if calls == 0 {
+						maxResults = tt.first
+					}
+					events, next, err := b.ListEvents(ctx, &types.ListEventsArgs{
+						OrderBy:    "startTime",
+						MaxResults: maxResults,
+						Cursor:     cursor,
+					})
+					if err != nil {
+						t.Fatalf("ListEvents() error = %v", err)
+					}
+					calls++
+					if len(events) > maxResults {
+						t.Errorf("page of %d events, want at most %d", len(events), maxResults)
+					}
+					for _, event := range events {
+						got = append(got, event.Summary)
+					}
+					if next == "" {
+						break
+					}
+					cursor = next
+				}
+
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("pages of %d then %d = %q, want %q", tt.first, tt.rest, got, want)
+				}
+				if calls != tt.calls {
+					t.Errorf("pages of %d then %d took %d calls, want %d", tt.first, tt.rest, calls, tt.calls)
+				}
+			}
+
+			if _, _, err := b.ListEvents(ctx, &types.ListEventsArgs{Cursor: "garbage!"}); err == nil {
+				t.Error("ListEvents() with an invalid cursor succeeded")
+			}
+		})
+	}
+}
+
+func TestListInstancesCursor(t *testing.T) {
+	for name, b := range pagingBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			id, err := b.CreateEvent(ctx, &types.CreateEventArgs{
+				Summary:    "Standup",
+				StartTime:  "2025-01-06T09:00:00Z",
+				EndTime:    "2025-01-06T09:15:00Z",
+				TimeZone:   "UTC",
+				Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=23"},
+			})
+			if err != nil {
+				t.Fatalf("CreateEvent() error = %v", err)
+			}
+
+			for _, maxResults := range []int{5, 23, 50} {
+				var got []string
+				cursor := ""
+				calls := 0
+				for {
+					instances, next, err := b.ListInstances(ctx, &types.ListInstancesArgs{EventID: id, MaxResults: maxResults, Cursor: cursor})
+					if err != nil {
+						t.Fatalf("ListInstances() error = %v", err)
+					}
+					calls++
+					if len(instances) > maxResults {
+						t.Errorf("page of %d instances, want at most %d", len(instances), maxResults)
+					}
+					for _, instance := range instances {
+						got = append(got, instance.StartTime)
+					}
+					if next == "" {
+						break
+					}
+					cursor = next
+				}
+
+				if len(got) != 23 || got[0] != "2025-01-06T09:00:00Z" || got[22] != "2025-01-28T09:00:00Z" {
+					t.Errorf("pages of %d = %q, want the 23 days from 2025-01-06", maxResults, got)
+				}
+				if want := (23 + maxResults - 1) / maxResults; calls != want {
+					t.Errorf("pages of %d took %d calls, want %d", maxResults, calls, want)
+				}
+			}
+
+			if _, _, err := b.ListInstances(ctx, &types.ListInstancesArgs{EventID: id, Cursor: "garbage!"}); err == nil {
+				t.Error("ListInstances() with an invalid cursor succeeded")
+			}
+		})
+	}
+}
+
+func TestListCalendarsCursor(t *testing.T) {
+	for name, b := range pagingBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			want := []string{calendar.DefaultMemoryOwner}
+			for i := 0; i < 7; i++ {
+				summary := fmt.Sprintf("calendar %d", i)
+				if _, err := b.CreateCalendar(ctx, &types.CreateCalendarArgs{Summary: summary}); err != nil {
+					t.Fatalf("CreateCalendar() error = %v", err)
+				}
+				want = append(want, summary)
+			}
+
+			var got []string
+			cursor := ""
+			for {
+				calendars, next, err := b.ListCalendars(ctx, &types.ListCalendarsArgs{MaxResults: 3, Cursor: cursor})
+				if err != nil {
+					t.Fatalf("ListCalendars() error = %v", err)
+				}
+				for _, cal := range calendars {
+					got = append(got, cal.Summary)
+				}
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("pages of 3 = %q, want %q", got, want)
+			}
+
+			all, next, err := b.ListCalendars(ctx, &types.ListCalendarsArgs{})
+			if err != nil {
+				t.Fatalf("ListCalendars() error = %v", err)
+			}
+			if len(all) != len(want) || next != "" {
+				t.Errorf("ListCalendars() = %d calendars, cursor %q; want %d and no cursor", len(all), next, len(want))
+			}
+		})
+	}
+}
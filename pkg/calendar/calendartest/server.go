@@ -0,0 +1,1172 @@
+// Package calendartest provides a fake Google Calendar API for hermetic
+// tests of calendar.Client and of the MCP server built on it.
+//
+// The fake implements the Events, Calendars, CalendarList and Freebusy
+// endpoints of Calendar API v3 over HTTP, with the JSON, ETags, page and sync
+// tokens and error bodies Google uses, so the client's request construction,
+// error mapping, pagination and syncing run unchanged:
+//
+//	fake := calendartest.NewServer()
+//	defer fake.Close()
+//	client, err := calendar.NewClient(&config.Config{},
+//		calendar.WithEndpoint(fake.Endpoint()),
+//		calendar.WithHTTPClient(fake.Client()))
+package calendartest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/recurrence"
+	"google.golang.org/api/calendar/v3"
+)
+
+// DefaultOwner owns the primary calendar of a new Server
+const DefaultOwner = "me@example.com"
+
+const (
+	// basePath is where the API is served, as on www.googleapis.com
+	basePath = "/calendar/v3"
+
+	defaultEventPageSize = 250
+	maxEventPageSize     = 2500
+
+	defaultCalendarPageSize = 100
+	maxCalendarPageSize     = 250
+
+	// maxFreeBusyCalendars is the most calendars one free/busy query may ask
+	// about
+	maxFreeBusyCalendars = 50
+
+	timestampFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// maxTime stands in for an open upper bound when expanding recurrences
+var maxTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// Server is a fake Google Calendar API. The primary calendar of Owner
+// exists from the start; everything else is created through the API.
+type Server struct {
+	*httptest.Server
+
+	// Owner is the email address of the primary calendar and the creator of
+	// every event
+	Owner string
+
+	mu        sync.Mutex
+	now       func() time.Time
+	calendars map[string]*fakeCalendar
+	order     []string // calendar IDs in creation order
+	version   int64    // source of ETags
+	syncEpoch int      // only sync tokens issued in this epoch are valid
+	failures  []int    // status codes to answer the next requests with
+	requests  []string
+}
+
+type fakeCalendar struct {
+	calendar *calendar.Calendar
+	entry    *calendar.CalendarListEntry
+	events   map[string]*calendar.Event
+	order    []string // event IDs in creation order
+}
+
+// NewServer starts a fake Calendar API. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Owner:     DefaultOwner,
+		now:       time.Now,
+		calendars: make(map[string]*fakeCalendar),
+	}
+	s.addCalendar(&calendar.Calendar{
+		Id:       s.Owner,
+		Summary:  s.Owner,
+		TimeZone: "UTC",
+	}, true)
+
+	router := mux.NewRouter().UseEncodedPath()
+	api := router.PathPrefix(basePath).Subrouter()
+	api.HandleFunc("/users/me/calendarList", s.listCalendarList).Methods("GET")
+	api.HandleFunc("/users/me/calendarList/{calendarId}", s.getCalendarListEntry).Methods("GET")
+	api.HandleFunc("/calendars", s.insertCalendar).Methods("POST")
+	api.HandleFunc("/calendars/{calendarId}", s.getCalendar).Methods("GET")
+	api.HandleFunc("/calendars/{calendarId}", s.deleteCalendar).Methods("DELETE")
+	api.HandleFunc("/calendars/{calendarId}/events", s.listEvents).Methods("GET")
+	api.HandleFunc("/calendars/{calendarId}/events", s.insertEvent).Methods("POST")
+	api.HandleFunc("/calendars/{calendarId}/events/{eventId}", s.getEvent).Methods("GET")
+	api.HandleFunc("/calendars/{calendarId}/events/{eventId}", s.updateEvent).Methods("PUT")
+	api.HandleFunc("/calendars/{calendarId}/events/{eventId}", s.deleteEvent).Methods("DELETE")
+	api.HandleFunc("/calendars/{calendarId}/events/{eventId}/instances", s.listInstances).Methods("GET")
+	api.HandleFunc("/freeBusy", s.queryFreeBusy).Methods("POST")
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+	})
+
+	s.Server = httptest.NewServer(s.middleware(router))
+	return s
+}
+
+// Endpoint is the base URL to pass to calendar.WithEndpoint
+func (s *Server) Endpoint() string {
+	return s.URL + basePath + "/"
+}
+
+// SetNow replaces the clock used for created and updated times
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// FailNext answers the next n requests with an error of the given status
+// code, such as 429 or 503, instead of handling them
+func (s *Server) FailNext(code, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, code)
+	}
+}
+
+// ExpireSyncTokens makes every sync token issued so far fail with 410 Gone,
+// as Google's do after a while, forcing clients into a full sync
+func (s *Server) ExpireSyncTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncEpoch++
+}
+
+// Requests returns the method and path, with query, of every request
+// received so far
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		var failure int
+		if len(s.failures) > 0 {
+			failure, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if failure != 0 {
+			writeError(w, failure, failureReason(failure), http.StatusText(failure))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func failureReason(code int) string {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return "rateLimitExceeded"
+	case code >= 500:
+		return "backendError"
+	default:
+		return "badRequest"
+	}
+}
+
+// Calendars
+
+func (s *Server) addCalendar(cal *calendar.Calendar, primary bool) {
+	cal.Kind = "calendar#calendar"
+	cal.Etag = s.nextETag()
+	s.calendars[cal.Id] = &fakeCalendar{
+		calendar: cal,
+		entry: &calendar.CalendarListEntry{
+			Kind:        "calendar#calendarListEntry",
+			Etag:        cal.Etag,
+			Id:          cal.Id,
+			Summary:     cal.Summary,
+			Description: cal.Description,
+			TimeZone:    cal.TimeZone,
+			AccessRole:  "owner",
+			Primary:     primary,
+		},
+		events: make(map[string]*calendar.Event),
+	}
+	s.order = append(s.order, cal.Id)
+}
+
+// calendar resolves the calendarId path variable, including the "primary"
+// alias. Callers hold s.mu.
+func (s *Server) calendar(r *http.Request) (*fakeCalendar, bool) {
+	id := pathVar(r, "calendarId")
+	if id == "primary" {
+		id = s.Owner
+	}
+	cal, ok := s.calendars[id]
+	return cal, ok
+}
+
+func (s *Server) listCalendarList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []*calendar.CalendarListEntry
+	for _, id := range s.order {
+		items = append(items, s.calendars[id].entry)
+	}
+
+	page, next, ok := paginate(len(items), r.URL.Query(), defaultCalendarPageSize, maxCalendarPageSize)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid", "Invalid page token value.")
+		return
+	}
+	writeJSON(w, http.StatusOK, &calendar.CalendarList{
+		Kind:          "calendar#calendarList",
+		Etag:          s.listETag(),
+		Items:         items[page[0]:page[1]],
+		NextPageToken: next,
+	})
+}
+
+func (s *Server) getCalendarListEntry(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cal, ok := s.calendar(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, cal.entry)
+}
+
+func (s *Server) insertCalendar(w http.ResponseWriter, r *http.Request) {
+	var cal calendar.Calendar
+	if err := json.NewDecoder(r.Body).Decode(&cal); err != nil {
+		writeError(w, http.StatusBadRequest, "parseError", "Parse Error")
+		return
+	}
+	if cal.Summary == "" {
+		writeError(w, http.StatusBadRequest, "required", "Missing title.")
+		return
+	}
+	if cal.TimeZone == "" {
+		cal.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(cal.TimeZone); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid", "Invalid time zone definition.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cal.Id = newID() + "@group.calendar.google.com"
+	s.addCalendar(&cal, false)
+	writeJSON(w, http.StatusOK, &cal)
+}
+
+func (s *Server) getCalendar(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cal, ok := s.calendar(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, cal.calendar)
+}
+
+func (s *Server) deleteCalendar(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cal, ok := s.calendar(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return
+	}
+	if cal.entry.Primary {
+		writeError(w, http.StatusBadRequest, "cannotDeletePrimaryCalendar", "Cannot delete primary calendar.")
+		return
+	}
+	delete(s.calendars, cal.calendar.Id)
+	s.order = removeID(s.order, cal.calendar.Id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Events
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	timeMin, ok := parseBound(query.Get("timeMin"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "badRequest", "Bad Request")
+		return
+	}
+	timeMax, ok := parseBound(query.Get("timeMax"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "badRequest", "Bad Request")
+		return
+	}
+	orderBy := query.Get("orderBy")
+	if orderBy != "" && orderBy != "startTime" && orderBy != "updated" {
+		writeError(w, http.StatusBadRequest, "invalid", "Invalid Value")
+		return
+	}
+	if orderBy == "startTime" && query.Get("singleEvents") != "true" {
+		writeError(w, http.StatusBadRequest, "badRequest", "The requested ordering is not available for the particular query.")
+		return
+	}
+	showDeleted := query.Get("showDeleted") == "true"
+	singleEvents := query.Get("singleEvents") == "true"
+	terms := strings.Fields(strings.ToLower(query.Get("q")))
+
+	// Only listings of everything can be synced incrementally
+	syncable := query.Get("timeMin") == "" && query.Get("timeMax") == "" && query.Get("q") == "" && orderBy == ""
+	syncToken := query.Get("syncToken")
+	if syncToken != "" && !syncable {
+		writeError(w, http.StatusBadRequest, "invalid", "Sync token cannot be used together with timeMin, timeMax, q or orderBy.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since := int64(-1)
+	if syncToken != "" {
+		if since, ok = s.parseSyncToken(syncToken); !ok {
+			writeError(w, http.StatusGone, "fullSyncRequired", "Sync token is no longer valid, a full sync is required.")
+			return
+		}
+	}
+
+	cal, ok := s.calendar(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return
+	}
+	loc := cal.location()
+
+	type match struct {
+		event      *calendar.Event
+		start, end time.Time
+	}
+	var matches []match
+	for _, id := range cal.order {
+		event := cal.events[id]
+		if since >= 0 {
+			// Syncs list every change, deletions included
+			if etagVersion(event.Etag) > since {
+				start, end := eventSpan(event, loc)
+				matches = append(matches, match{event, start, end})
+			}
+			continue
+		}
+		if event.Status == "cancelled" && !showDeleted && (singleEvents || !cal.isCancelledInstance(event)) {
+			// Cancelled instances of live series are still listed with
+			// their series, as on Google
+			continue
+		}
+		if singleEvents && event.RecurringEventId != "" {
+			// Listed with the rest of its series
+			continue
+		}
+		if singleEvents && len(event.Recurrence) > 0 {
+			for _, instance := range cal.instances(event, timeMin, timeMax, showDeleted) {
+				if matchesQuery(instance, terms) {
+					start, end := eventSpan(instance, loc)
+					matches = append(matches, match{instance, start, end})
+				}
+			}
+			continue
+		}
+		start, end := eventSpan(event, loc)
+		if len(occurrences(event, loc, timeMin, timeMax, 1)) == 0 {
+			continue
+		}
+		if !matchesQuery(event, terms) {
+			continue
+		}
+		matches = append(matches, match{event, start, end})
+	}
+
+	switch orderBy {
+	case "startTime":
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].start.Before(matches[j].start)
+		})
+	case "updated":
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].event.Updated < matches[j].event.Updated
+		})
+	}
+
+	page, next, ok := paginate(len(matches), query, defaultEventPageSize, maxEventPageSize)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid", "Invalid page token value.")
+		return
+	}
+	items := []*calendar.Event{}
+	for _, m := range matches[page[0]:page[1]] {
+		items = append(items, m.event)
+	}
+	nextSyncToken := ""
+	if next == "" && syncable {
+		nextSyncToken = s.syncToken()
+	}
+
+	writeJSON(w, http.StatusOK, &calendar.Events{
+		Kind:          "calendar#events",
+		Etag:          s.listETag(),
+		Summary:       cal.calendar.Summary,
+		Description:   cal.calendar.Description,
+		TimeZone:      cal.calendar.TimeZone,
+		AccessRole:    cal.entry.AccessRole,
+		Updated:       s.now().UTC().Format(timestampFormat),
+		Items:         items,
+		NextPageToken: next,
+		NextSyncToken: nextSyncToken,
+	})
+}
+
+func (s *Server) listInstances(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	timeMin, ok := parseBound(query.Get("timeMin"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "badRequest", "Bad Request")
+		return
+	}
+	timeMax, ok := parseBound(query.Get("timeMax"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "badRequest", "Bad Request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.event(w, r)
+	if !ok {
+		return
+	}
+	cal, _ := s.calendar(r)
+
+	// A single event is its own only instance
+	items := []*calendar.Event{event}
+	if len(event.Recurrence) > 0 {
+		items = cal.instances(event, timeMin, timeMax, query.Get("showDeleted") == "true")
+	}
+	page, next, ok := paginate(len(items), query, defaultEventPageSize, maxEventPageSize)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid", "Invalid page token value.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &calendar.Events{
+		Kind:          "calendar#events",
+		Etag:          s.listETag(),
+		Summary:       cal.calendar.Summary,
+		TimeZone:      cal.calendar.TimeZone,
+		AccessRole:    cal.entry.AccessRole,
+		Updated:       s.now().UTC().Format(timestampFormat),
+		Items:         append([]*calendar.Event{}, items[page[0]:page[1]]...),
+		NextPageToken: next,
+	})
+}
+
+func (s *Server) insertEvent(w http.ResponseWriter, r *http.Request) {
+	var event calendar.Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		writeError(w, http.StatusBadRequest, "parseError", "Parse Error")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cal, ok := s.calendar(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return
+	}
+	if code, reason, message := validateTimes(&event, cal.location()); code != 0 {
+		writeError(w, code, reason, message)
+		return
+	}
+	if event.Id != "" {
+		if _, exists := cal.events[event.Id]; exists {
+			writeError(w, http.StatusConflict, "duplicate", "The requested identifier already exists.")
+			return
+		}
+	}
+	s.insert(cal, &event)
+	writeJSON(w, http.StatusOK, &event)
+}
+
+// AddEvent stores event in a calendar as an insert would, but without
+// validating it, for events Google accepts and the fake cannot expand, such
+// as series with BYSETPOS rules. It returns the event's ID, or "" if the
+// calendar does not exist.
+func (s *Server) AddEvent(calendarID string, event *calendar.Event) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if calendarID == "primary" {
+		calendarID = s.Owner
+	}
+	cal, ok := s.calendars[calendarID]
+	if !ok {
+		return ""
+	}
+	stored := *event
+	s.insert(cal, &stored)
+	return stored.Id
+}
+
+// insert fills in what Google sets on new events and stores the event.
+// Callers hold s.mu.
+func (s *Server) insert(cal *fakeCalendar, event *calendar.Event) {
+	if event.Id == "" {
+		event.Id = newID()
+	}
+
+	now := s.now().UTC().Format(timestampFormat)
+	event.Kind = "calendar#event"
+	event.Etag = s.nextETag()
+	event.Status = "confirmed"
+	event.HtmlLink = "https://www.google.com/calendar/event?eid=" + base64.RawURLEncoding.EncodeToString([]byte(event.Id+" "+cal.calendar.Id))
+	event.ICalUID = event.Id + "@google.com"
+	event.Created = now
+	event.Updated = now
+	event.Sequence = 0
+	event.EventType = "default"
+	event.Creator = &calendar.EventCreator{Email: s.Owner, Self: true}
+	event.Organizer = &calendar.EventOrganizer{Email: cal.calendar.Id, DisplayName: cal.calendar.Summary, Self: true}
+	normalizeEvent(event)
+
+	cal.events[event.Id] = event
+	cal.order = append(cal.order, event.Id)
+}
+
+func (s *Server) getEvent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.event(w, r)
+	if !ok {
+		return
+	}
+	if r.Header.Get("If-None-Match") == event.Etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, event)
+}
+
+func (s *Server) updateEvent(w http.ResponseWriter, r *http.Request) {
+	var update calendar.Event
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "parseError", "Parse Error")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.event(w, r)
+	if !ok {
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && match != existing.Etag {
+		writeError(w, http.StatusPreconditionFailed, "conditionNotMet", "Precondition Failed")
+		return
+	}
+	cal, _ := s.calendar(r)
+	if code, reason, message := validateTimes(&update, cal.location()); code != 0 {
+		writeError(w, code, reason, message)
+		return
+	}
+	if existing.RecurringEventId != "" && len(update.Recurrence) > 0 {
+		writeError(w, http.StatusBadRequest, "invalid", "Invalid recurrence rule.")
+		return
+	}
+	cal.store(existing)
+
+	// Server-managed fields survive the replacement
+	update.Kind = existing.Kind
+	update.Id = existing.Id
+	update.HtmlLink = existing.HtmlLink
+	update.ICalUID = existing.ICalUID
+	update.Created = existing.Created
+	update.Creator = existing.Creator
+	update.Organizer = existing.Organizer
+	update.EventType = existing.EventType
+	update.Sequence = existing.Sequence
+	update.RecurringEventId = existing.RecurringEventId
+	update.OriginalStartTime = existing.OriginalStartTime
+	if !sameTime(update.Start, existing.Start) || !sameTime(update.End, existing.End) {
+		update.Sequence++
+	}
+	if update.Status == "" {
+		update.Status = "confirmed"
+	}
+	update.Etag = s.nextETag()
+	update.Updated = s.now().UTC().Format(timestampFormat)
+	normalizeEvent(&update)
+
+	*existing = update
+	writeJSON(w, http.StatusOK, existing)
+}
+
+func (s *Server) deleteEvent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.event(w, r)
+	if !ok {
+		return
+	}
+	if event.Status == "cancelled" {
+		writeError(w, http.StatusGone, "deleted", "Resource has been deleted")
+		return
+	}
+
+	// Deleted events stay behind as cancelled ones, as they do on Google,
+	// and take the exceptions to their series with them
+	cal, _ := s.calendar(r)
+	cal.store(event)
+	for _, exception := range cal.events {
+		if exception == event || (exception.RecurringEventId == event.Id && exception.Status != "cancelled") {
+			exception.Status = "cancelled"
+			exception.Etag = s.nextETag()
+			exception.Updated = s.now().UTC().Format(timestampFormat)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// event finds the event named by the request path, answering with an error
+// if there is none. Callers hold s.mu.
+func (s *Server) event(w http.ResponseWriter, r *http.Request) (*calendar.Event, bool) {
+	cal, ok := s.calendar(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return nil, false
+	}
+	id := pathVar(r, "eventId")
+	if event, ok := cal.events[id]; ok {
+		return event, true
+	}
+	if event := cal.instance(id); event != nil {
+		return event, true
+	}
+	writeError(w, http.StatusNotFound, "notFound", "Not Found")
+	return nil, false
+}
+
+// instance returns the unmodified instance of a recurring event with the
+// given ID, or nil if there is none. Changing it takes store.
+func (c *fakeCalendar) instance(id string) *calendar.Event {
+	masterID, start, allDay, ok := recurrence.ParseInstanceID(id)
+	if !ok {
+		return nil
+	}
+	master, ok := c.events[masterID]
+	if !ok || master.Status == "cancelled" || len(master.Recurrence) == 0 || allDay != (master.Start.Date != "") {
+		return nil
+	}
+
+	loc := c.location()
+	if allDay {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	}
+	for _, t := range occurrences(master, loc, start, start.Add(time.Nanosecond), 0) {
+		if t.Equal(start) {
+			return newInstance(master, t, loc)
+		}
+	}
+	return nil
+}
+
+// instances returns the instances of a recurring event that overlap
+// [timeMin, timeMax), with the exceptions to the series in place of the
+// instances they change
+func (c *fakeCalendar) instances(master *calendar.Event, timeMin, timeMax time.Time, showDeleted bool) []*calendar.Event {
+	loc := c.location()
+	allDay := master.Start.Date != ""
+
+	var items []*calendar.Event
+	for _, start := range occurrences(master, loc, timeMin, timeMax, 0) {
+		if _, ok := c.events[recurrence.InstanceID(master.Id, start, allDay)]; !ok {
+			items = append(items, newInstance(master, start, loc))
+		}
+	}
+	for _, id := range c.order {
+		event := c.events[id]
+		if event.RecurringEventId != master.Id || (event.Status == "cancelled" && !showDeleted) {
+			continue
+		}
+		if len(occurrences(event, loc, timeMin, timeMax, 1)) > 0 {
+			items = append(items, event)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, _ := eventSpan(items[i], loc)
+		b, _ := eventSpan(items[j], loc)
+		return a.Before(b)
+	})
+	return items
+}
+
+// isCancelledInstance reports whether event is a cancelled instance of a
+// recurring event that still exists
+func (c *fakeCalendar) isCancelledInstance(event *calendar.Event) bool {
+	if event.Status != "cancelled" || event.RecurringEventId == "" {
+		return false
+	}
+	master, ok := c.events[event.RecurringEventId]
+	return ok && master.Status != "cancelled"
+}
+
+// store keeps an instance that is about to become an exception to its
+// series. Callers hold s.mu.
+func (c *fakeCalendar) store(event *calendar.Event) {
+	if _, ok := c.events[event.Id]; !ok {
+		c.events[event.Id] = event
+		c.order = append(c.order, event.Id)
+	}
+}
+
+// newInstance returns the occurrence of a recurring event starting at start
+func newInstance(master *calendar.Event, start time.Time, loc *time.Location) *calendar.Event {
+	masterStart, masterEnd := eventSpan(master, loc)
+	instance := *master
+	instance.Recurrence = nil
+	instance.RecurringEventId = master.Id
+
+	if master.Start.Date != "" {
+		days := int(masterEnd.Sub(masterStart).Round(24*time.Hour) / (24 * time.Hour))
+		instance.Id = recurrence.InstanceID(master.Id, start, true)
+		instance.Start = &calendar.EventDateTime{Date: start.Format("2006-01-02")}
+		instance.End = &calendar.EventDateTime{Date: start.AddDate(0, 0, days).Format("2006-01-02")}
+	} else {
+		instance.Id = recurrence.InstanceID(master.Id, start, false)
+		instance.Start = &calendar.EventDateTime{DateTime: start.Format(time.RFC3339), TimeZone: master.Start.TimeZone}
+		instance.End = &calendar.EventDateTime{DateTime: start.Add(masterEnd.Sub(masterStart)).Format(time.RFC3339), TimeZone: master.End.TimeZone}
+	}
+	originalStart := *instance.Start
+	instance.OriginalStartTime = &originalStart
+	return &instance
+}
+
+// Free/busy
+
+func (s *Server) queryFreeBusy(w http.ResponseWriter, r *http.Request) {
+	var request calendar.FreeBusyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "parseError", "Parse Error")
+		return
+	}
+	timeMin, err := time.Parse(time.RFC3339, request.TimeMin)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "required", "Missing timeMin parameter.")
+		return
+	}
+	timeMax, err := time.Parse(time.RFC3339, request.TimeMax)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "required", "Missing timeMax parameter.")
+		return
+	}
+	if timeMax.Before(timeMin) {
+		writeError(w, http.StatusBadRequest, "timeRangeEmpty", "The specified time range is empty.")
+		return
+	}
+	if len(request.Items) > maxFreeBusyCalendars {
+		writeError(w, http.StatusBadRequest, "tooManyCalendarsRequested", "Too many calendars requested.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := &calendar.FreeBusyResponse{
+		Kind:      "calendar#freeBusy",
+		TimeMin:   timeMin.UTC().Format(timestampFormat),
+		TimeMax:   timeMax.UTC().Format(timestampFormat),
+		Calendars: make(map[string]calendar.FreeBusyCalendar),
+	}
+	for _, item := range request.Items {
+		id := item.Id
+		if id == "primary" {
+			id = s.Owner
+		}
+		cal, ok := s.calendars[id]
+		if !ok {
+			response.Calendars[item.Id] = calendar.FreeBusyCalendar{
+				Errors: []*calendar.Error{{Domain: "global", Reason: "notFound"}},
+			}
+			continue
+		}
+		response.Calendars[item.Id] = calendar.FreeBusyCalendar{
+			Busy: cal.busy(timeMin, timeMax),
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// busy returns the merged periods in which the calendar has opaque events,
+// clipped to [timeMin, timeMax)
+func (c *fakeCalendar) busy(timeMin, timeMax time.Time) []*calendar.TimePeriod {
+	type span struct{ start, end time.Time }
+
+	loc := c.location()
+	var spans []span
+	for _, event := range c.events {
+		if event.Status == "cancelled" || event.Transparency == "transparent" {
+			continue
+		}
+		start, end := eventSpan(event, loc)
+		duration := end.Sub(start)
+		if duration <= 0 {
+			continue
+		}
+		allDay := event.Start.Date != ""
+		for _, start := range occurrences(event, loc, timeMin, timeMax, 0) {
+			if len(event.Recurrence) > 0 && c.events[recurrence.InstanceID(event.Id, start, allDay)] != nil {
+				// The exception to the series counts instead
+				continue
+			}
+			end := start.Add(duration)
+			if start.Before(timeMin) {
+				start = timeMin
+			}
+			if end.After(timeMax) {
+				end = timeMax
+			}
+			spans = append(spans, span{start, end})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start.Before(spans[j].start)
+	})
+
+	var busy []*calendar.TimePeriod
+	var last span
+	for i, sp := range spans {
+		if i > 0 && !sp.start.After(last.end) {
+			if sp.end.After(last.end) {
+				last.end = sp.end
+				busy[len(busy)-1].End = last.end.UTC().Format(time.RFC3339)
+			}
+			continue
+		}
+		last = sp
+		busy = append(busy, &calendar.TimePeriod{
+			Start: sp.start.UTC().Format(time.RFC3339),
+			End:   sp.end.UTC().Format(time.RFC3339),
+		})
+	}
+	return busy
+}
+
+// Helpers
+
+func (c *fakeCalendar) location() *time.Location {
+	loc, err := time.LoadLocation(c.calendar.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// validateTimes checks an event's start and end as Google does, returning
+// the status, reason and message of the error if they are invalid
+func validateTimes(event *calendar.Event, loc *time.Location) (int, string, string) {
+	if event.Start == nil || (event.Start.DateTime == "" && event.Start.Date == "") {
+		return http.StatusBadRequest, "required", "Missing start time."
+	}
+	if event.End == nil || (event.End.DateTime == "" && event.End.Date == "") {
+		return http.StatusBadRequest, "required", "Missing end time."
+	}
+	if (event.Start.Date != "") != (event.End.Date != "") {
+		return http.StatusBadRequest, "invalid", "Start and end times must either both be date or both be dateTime."
+	}
+
+	start, err := parseEventTime(event.Start, loc)
+	if err != nil {
+		return http.StatusBadRequest, "invalid", "Invalid start time."
+	}
+	end, err := parseEventTime(event.End, loc)
+	if err != nil {
+		return http.StatusBadRequest, "invalid", "Invalid end time."
+	}
+	if end.Before(start) || (event.Start.Date != "" && !end.After(start)) {
+		return http.StatusBadRequest, "timeRangeEmpty", "The specified time range is empty."
+	}
+
+	if len(event.Recurrence) > 0 {
+		if _, err := recurrence.Parse(event.Recurrence); err != nil {
+			return http.StatusBadRequest, "invalid", "Invalid recurrence rule."
+		}
+		if event.Start.DateTime != "" && (event.Start.TimeZone == "" || event.End.TimeZone == "") {
+			return http.StatusBadRequest, "required", "Missing time zone definition for start time."
+		}
+	}
+	return 0, "", ""
+}
+
+// occurrences returns the start times of the event's occurrences that
+// overlap [timeMin, timeMax), at most limit of them when limit is positive.
+// Zero bounds are open.
+func occurrences(event *calendar.Event, loc *time.Location, timeMin, timeMax time.Time, limit int) []time.Time {
+	start, end := eventSpan(event, loc)
+	if timeMax.IsZero() {
+		timeMax = maxTime
+	}
+	var from time.Time
+	if !timeMin.IsZero() {
+		from = timeMin.Add(start.Sub(end) + 1)
+	}
+
+	set, err := recurrence.Parse(event.Recurrence)
+	if len(event.Recurrence) == 0 || err != nil {
+		if start.Before(from) || !start.Before(timeMax) {
+			return nil
+		}
+		return []time.Time{start}
+	}
+
+	// Timed occurrences keep their wall-clock time in the event's zone
+	if event.Start.DateTime != "" {
+		if zone, err := time.LoadLocation(event.Start.TimeZone); err == nil {
+			start = start.In(zone)
+		}
+	}
+	return set.Between(start, from, timeMax, limit)
+}
+
+// normalizeEvent fills the defaults Google adds to stored events
+func normalizeEvent(event *calendar.Event) {
+	for _, attendee := range event.Attendees {
+		if attendee.ResponseStatus == "" {
+			attendee.ResponseStatus = "needsAction"
+		}
+	}
+	if event.Reminders == nil {
+		event.Reminders = &calendar.EventReminders{UseDefault: true}
+	}
+}
+
+func eventSpan(event *calendar.Event, loc *time.Location) (time.Time, time.Time) {
+	start, _ := parseEventTime(event.Start, loc)
+	end, _ := parseEventTime(event.End, loc)
+	return start, end
+}
+
+func parseEventTime(t *calendar.EventDateTime, loc *time.Location) (time.Time, error) {
+	if t == nil {
+		return time.Time{}, fmt.Errorf("no time")
+	}
+	if t.DateTime != "" {
+		return time.Parse(time.RFC3339, t.DateTime)
+	}
+	return time.ParseInLocation("2006-01-02", t.Date, loc)
+}
+
+func sameTime(a, b *calendar.EventDateTime) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.DateTime == b.DateTime && a.Date == b.Date && a.TimeZone == b.TimeZone
+}
+
+func parseBound(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, err == nil
+}
+
+// matchesQuery reports whether every term appears in the event's summary,
+// description, location, organizer or attendees
+func matchesQuery(event *calendar.Event, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+
+	fields := []string{event.Summary, event.Description, event.Location}
+	if event.Organizer != nil {
+		fields = append(fields, event.Organizer.Email, event.Organizer.DisplayName)
+	}
+	for _, attendee := range event.Attendees {
+		fields = append(fields, attendee.Email, attendee.DisplayName)
+	}
+	text := strings.ToLower(strings.Join(fields, "\n"))
+
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// paginate selects the page of n items asked for by the maxResults and
+// pageToken parameters. Page tokens are opaque offsets.
+func paginate(n int, query url.Values, defaultSize, maxSize int) (page [2]int, next string, ok bool) {
+	size := defaultSize
+	if v := query.Get("maxResults"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return page, "", false
+		}
+		size = parsed
+	}
+	if size > maxSize {
+		size = maxSize
+	}
+
+	offset := 0
+	if token := query.Get("pageToken"); token != "" {
+		data, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			return page, "", false
+		}
+		offset, err = strconv.Atoi(strings.TrimPrefix(string(data), "offset:"))
+		if err != nil || offset < 0 || offset > n {
+			return page, "", false
+		}
+	}
+
+	end := offset + size
+	if end < n {
+		next = base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(end)))
+	} else {
+		end = n
+	}
+	return [2]int{offset, end}, next, true
+}
+
+// nextETag returns a new quoted ETag. Callers hold s.mu.
+func (s *Server) nextETag() string {
+	s.version++
+	return fmt.Sprintf(`"%d"`, 3180000000000000+s.version)
+}
+
+// syncToken returns a token for the changes after the current version.
+// Callers hold s.mu.
+func (s *Server) syncToken() string {
+	return fmt.Sprintf("sync%d-%d", s.syncEpoch, s.version)
+}
+
+// parseSyncToken returns the version a sync token was issued at, unless it
+// is malformed or has expired. Callers hold s.mu.
+func (s *Server) parseSyncToken(token string) (int64, bool) {
+	var epoch int
+	var version int64
+	if _, err := fmt.Sscanf(token, "sync%d-%d", &epoch, &version); err != nil || epoch != s.syncEpoch || version > s.version {
+		return 0, false
+	}
+	return version, true
+}
+
+// etagVersion returns the version an ETag from nextETag was issued at
+func etagVersion(etag string) int64 {
+	n, err := strconv.ParseInt(strings.Trim(etag, `"`), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n - 3180000000000000
+}
+
+// listETag identifies the current state of the whole store. Callers hold
+// s.mu.
+func (s *Server) listETag() string {
+	return fmt.Sprintf(`"p%d"`, 3180000000000000+s.version)
+}
+
+func pathVar(r *http.Request, name string) string {
+	value := mux.Vars(r)[name]
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+func removeID(ids []string, id string) []string {
+	for i, existing := range ids {
+		if existing == id {
+			return append(ids[:i:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+// newID returns an ID in the alphabet Google uses for event IDs
+func newID() string {
+	const alphabet = "0123456789abcdefghijklmnopqrstuv"
+	buf := make([]byte, 26)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	for i, b := range buf {
+		buf[i] = alphabet[b%32]
+	}
+	return string(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	if etag := etagOf(v); etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func etagOf(v interface{}) string {
+	switch resource := v.(type) {
+	case *calendar.Event:
+		return resource.Etag
+	case *calendar.Calendar:
+		return resource.Etag
+	case *calendar.CalendarListEntry:
+		return resource.Etag
+	case *calendar.Events:
+		return resource.Etag
+	case *calendar.CalendarList:
+		return resource.Etag
+	}
+	return ""
+}
+
+// writeError answers with an error body in Google's format, which the API
+// client turns into a *googleapi.Error
+func writeError(w http.ResponseWriter, code int, reason, message string) {
+	body := map[string]interface{}{
+		"error": map[string]interface{}{
+			"errors": []map[string]string{{
+				"domain":  "global",
+				"reason":  reason,
+				"message": message,
+			}},
+			"code":    code,
+			"message": message,
+		},
+	}
+	writeJSON(w, code, body)
+}
@@ -10,7 +10,7 @@ import (
 	"runtime"
 	"time"
 
-	"github.com/phildougherty/mcp-google-calendar-go/internal/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/calendar/v3"
@@ -21,9 +21,35 @@ type Client struct {
 	service *calendar.Service
 	config  *config.Config
 	oauth   *oauth2.Config
+
+	// endpoint overrides the Calendar API base URL
+	endpoint string
+
+	// httpClient, when set, carries API requests without OAuth
+	httpClient *http.Client
+}
+
+// ClientOption customizes a Client
+type ClientOption func(*Client)
+
+// WithEndpoint sends Calendar API requests to endpoint, the equivalent of
+// https://www.googleapis.com/calendar/v3/, instead of Google
+func WithEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.endpoint = endpoint
+	}
 }
 
-func NewClient(cfg *config.Config) (*Client, error) {
+// WithHTTPClient sends Calendar API requests through httpClient's transport
+// without OAuth, for fakes and proxies that authorize requests themselves.
+// The client still logs and retries requests.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func NewClient(cfg *config.Config, opts ...ClientOption) (*Client, error) {
 	oauthConfig := &oauth2.Config{
 		ClientID:     cfg.OAuth.ClientID,
 		ClientSecret: cfg.OAuth.ClientSecret,
@@ -40,10 +66,28 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		},
 		Endpoint: google.Endpoint,
 	}
+	if cfg.ReadOnly {
+		oauthConfig.Scopes = []string{
+			"https://www.googleapis.com/auth/userinfo.email",
+			calendar.CalendarReadonlyScope,
+			calendar.CalendarEventsReadonlyScope,
+		}
+	}
 
 	client := &Client{
-		config: cfg,
-		oauth:  oauthConfig,
+		config:   cfg,
+		oauth:    oauthConfig,
+		endpoint: cfg.APIEndpoint,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if client.httpClient != nil {
+		if err := client.initService(client.newHTTPClient(nil)); err != nil {
+			return nil, fmt.Errorf("failed to create Calendar service: %w", err)
+		}
+		return client, nil
 	}
 
 	// Try to load existing credentials
@@ -55,7 +99,7 @@ func NewClient(cfg *config.Config) (*Client, error) {
 }
 
 func (c *Client) loadCredentials() error {
-	data, err := os.ReadFile(c.config.CredentialsPath)
+	data, err := os.ReadFile(c.config.TokenPath())
 	if err != nil {
 		return err
 	}
@@ -72,15 +116,22 @@ func (c *Client) loadCredentials() error {
 		}
 	}
 
-	httpClient := c.oauth.Client(context.Background(), &token)
-	
 	// Initialize Calendar service
-	service, err := calendar.NewService(context.Background(), option.WithHTTPClient(httpClient))
+	return c.initService(c.newHTTPClient(&token))
+}
+
+// initService creates the Calendar service on top of httpClient
+func (c *Client) initService(httpClient *http.Client) error {
+	opts := []option.ClientOption{option.WithHTTPClient(httpClient)}
+	if c.endpoint != "" {
+		opts = append(opts, option.WithEndpoint(c.endpoint))
+	}
+
+	service, err := calendar.NewService(context.Background(), opts...)
 	if err != nil {
 		return err
 	}
 	c.service = service
-
 	return nil
 }
 
@@ -163,23 +214,14 @@ func (c *Client) handleAuthCallback() error {
 	}
 
 	// Save credentials
-	data, err := json.Marshal(token)
-	if err != nil {
-		return fmt.Errorf("failed to marshal token: %w", err)
-	}
-
-	if err := os.WriteFile(c.config.CredentialsPath, data, 0600); err != nil {
+	if err := c.saveToken(token); err != nil {
 		return fmt.Errorf("failed to save credentials: %w", err)
 	}
 
 	// Initialize service
-	httpClient := c.oauth.Client(context.Background(), token)
-	
-	service, err := calendar.NewService(context.Background(), option.WithHTTPClient(httpClient))
-	if err != nil {
+	if err := c.initService(c.newHTTPClient(token)); err != nil {
 		return fmt.Errorf("failed to create Calendar service: %w", err)
 	}
-	c.service = service
 
 	return nil
 }
@@ -196,12 +238,7 @@ func (c *Client) refreshToken(token *oauth2.Token) error {
 	}
 
 	// Save the refreshed token
-	data, err := json.Marshal(newToken)
-	if err != nil {
-		return fmt.Errorf("failed to marshal refreshed token: %w", err)
-	}
-
-	if err := os.WriteFile(c.config.CredentialsPath, data, 0600); err != nil {
+	if err := c.saveToken(newToken); err != nil {
 		return fmt.Errorf("failed to save refreshed token: %w", err)
 	}
 
@@ -210,6 +247,15 @@ func (c *Client) refreshToken(token *oauth2.Token) error {
 	return nil
 }
 
+// saveToken persists token to the credentials file of the current mode
+func (c *Client) saveToken(token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+	return os.WriteFile(c.config.TokenPath(), data, 0600)
+}
+
 func (c *Client) IsAuthenticated() bool {
 	return c.service != nil
 }
\ No newline at end of file
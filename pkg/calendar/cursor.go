@@ -0,0 +1,95 @@
+package calendar
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Google caps the page sizes of event and calendar listings
+const (
+	maxEventPageSize    = 2500
+	maxCalendarPageSize = 250
+)
+
+// cursor is where a listing that hit its limit stopped, handed out as an
+// opaque string so a later call can continue it. PageToken fetches the page
+// it stopped in and Offset counts the items of that page already returned.
+// Google page tokens only hold for the page size they were issued with, so
+// that is kept too. The memory backend, which has no pages, only uses Offset.
+type cursor struct {
+	PageToken string `json:"t,omitempty"`
+	Offset    int    `json:"o,omitempty"`
+	PageSize  int    `json:"s,omitempty"`
+}
+
+func (c cursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parseCursor reads a cursor handed out by String. The empty string is the
+// start of a listing.
+func parseCursor(s string) (cursor, error) {
+	var c cursor
+	if s == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.Offset < 0 || c.PageSize < 0 {
+		return cursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	return c, nil
+}
+
+// pager collects the items of a paged listing until it reaches its limit,
+// remembering where it stopped
+type pager struct {
+	limit int
+	count int
+	at    cursor
+	next  string
+}
+
+// newPager starts a listing of at most limit items, 0 for all of them, at
+// start. Listings with a limit fetch pages of at most limit items, up to
+// maxPageSize, and keep that size when they are continued.
+func newPager(limit int, start cursor, maxPageSize int) *pager {
+	if start.PageSize == 0 && limit > 0 {
+		start.PageSize = limit
+		if start.PageSize > maxPageSize {
+			start.PageSize = maxPageSize
+		}
+	}
+	return &pager{limit: limit, at: start}
+}
+
+// page takes a page of n items, fetched with the current page token,
+// calling add with the index of each item to return. It reports whether
+// paging is done, either because the limit is reached or because
+// nextPageToken is empty.
+func (p *pager) page(n int, nextPageToken string, add func(i int)) bool {
+	for i := p.at.Offset; i < n; i++ {
+		if p.full() {
+			p.at.Offset = i
+			p.next = p.at.String()
+			return true
+		}
+		add(i)
+		p.count++
+	}
+
+	p.at = cursor{PageToken: nextPageToken, PageSize: p.at.PageSize}
+	if nextPageToken == "" {
+		return true
+	}
+	if p.full() {
+		p.next = p.at.String()
+		return true
+	}
+	return false
+}
+
+func (p *pager) full() bool {
+	return p.limit > 0 && p.count >= p.limit
+}
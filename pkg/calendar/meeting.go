@@ -0,0 +1,358 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// Defaults of FindMeetingTimes
+const (
+	defaultGranularity  = 30 * time.Minute
+	defaultMeetingSlots = 5
+	defaultWorkStart    = "09:00"
+	defaultWorkEnd      = "17:00"
+)
+
+var defaultWorkDays = []string{"MO", "TU", "WE", "TH", "FR"}
+
+var workDays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// roomyGap is how much free time around a slot counts as fully relaxed;
+// closer neighbours make a slot rank lower
+const roomyGap = time.Hour
+
+// workingHours are parsed types.WorkingHours
+type workingHours struct {
+	loc        *time.Location
+	start, end int // minutes after midnight
+	days       map[time.Weekday]bool
+}
+
+// interval is a span of time [start, end)
+type interval struct{ start, end time.Time }
+
+// FindMeetingTimes finds times in the window of args that every attendee
+// is free for the meeting, on any Backend. A slot must fall within the
+// working hours of every time zone given, and leave args.BufferMinutes
+// free on both sides. Slots are ranked by a score between 0 and 1 that
+// favours, in this order, earlier slots, slots away from other events and
+// slots in the middle of the working day; the best ones that do not overlap
+// each other are returned.
+//
+// Attendees whose free/busy information Google cannot give, typically
+// because their calendar is not shared with the user, are reported in the
+// result and otherwise treated as free.
+func FindMeetingTimes(ctx context.Context, b Backend, args *types.FindMeetingTimesArgs) (*types.MeetingTimesResult, error) {
+	if len(args.Attendees) == 0 {
+		return nil, fmt.Errorf("at least one attendee is required")
+	}
+	if args.DurationMinutes <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+	if args.BufferMinutes < 0 {
+		return nil, fmt.Errorf("buffer must not be negative")
+	}
+	duration := time.Duration(args.DurationMinutes) * time.Minute
+	buffer := time.Duration(args.BufferMinutes) * time.Minute
+	granularity := defaultGranularity
+	if args.GranularityMinutes > 0 {
+		granularity = time.Duration(args.GranularityMinutes) * time.Minute
+	}
+	maxSlots := args.MaxResults
+	if maxSlots <= 0 {
+		maxSlots = defaultMeetingSlots
+	}
+
+	windowStart, err := time.Parse(time.RFC3339, args.TimeMin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeMin: %w", err)
+	}
+	windowEnd, err := time.Parse(time.RFC3339, args.TimeMax)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeMax: %w", err)
+	}
+	if windowEnd.Sub(windowStart) < duration {
+		return nil, fmt.Errorf("the window from timeMin to timeMax is shorter than the meeting")
+	}
+
+	hours, err := parseWorkingHours(ctx, b, args.WorkingHours)
+	if err != nil {
+		return nil, err
+	}
+
+	// Events just outside the window still need their buffer
+	freeBusy, err := b.GetFreeBusy(ctx, &types.FreeBusyArgs{
+		TimeMin:     windowStart.Add(-buffer).Format(time.RFC3339),
+		TimeMax:     windowEnd.Add(buffer).Format(time.RFC3339),
+		CalendarIDs: args.Attendees,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.MeetingTimesResult{Slots: []*types.MeetingSlot{}}
+	var busy []interval
+	for _, attendee := range args.Attendees {
+		cal, ok := freeBusy.Calendars[attendee]
+		if !ok || len(cal.Errors) > 0 {
+			reason := "no free/busy information returned"
+			if ok {
+				reason = strings.Join(cal.Errors, ", ")
+			}
+			if result.UncheckedAttendees == nil {
+				result.UncheckedAttendees = make(map[string]string)
+			}
+			result.UncheckedAttendees[attendee] = reason
+			continue
+		}
+		for _, period := range cal.Busy {
+			start, err := time.Parse(time.RFC3339, period.Start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid busy period start %q: %w", period.Start, err)
+			}
+			end, err := time.Parse(time.RFC3339, period.End)
+			if err != nil {
+				return nil, fmt.Errorf("invalid busy period end %q: %w", period.End, err)
+			}
+			busy = append(busy, interval{start, end})
+		}
+	}
+	if len(result.UncheckedAttendees) == len(args.Attendees) {
+		return nil, fmt.Errorf("could not get free/busy information for any attendee")
+	}
+	busy = mergeIntervals(busy)
+
+	type candidate struct {
+		interval
+		score float64
+	}
+	var candidates []candidate
+	next := 0 // first busy period that ends after the candidate's buffer starts
+	loc := hours[0].loc
+	for start := alignStart(windowStart, granularity, loc); !start.Add(duration).After(windowEnd); start = alignStart(start.Add(granularity), granularity, loc) {
+		end := start.Add(duration)
+		for next < len(busy) && !busy[next].end.After(start.Add(-buffer)) {
+			next++
+		}
+		if next < len(busy) && busy[next].start.Before(end.Add(buffer)) {
+			continue
+		}
+
+		comfort, ok := workingComfort(hours, start, end)
+		if !ok {
+			continue
+		}
+
+		// Free time to the neighbouring events, with the window's edges
+		// counting as free beyond
+		before, after := roomyGap, roomyGap
+		if next > 0 {
+			before = start.Sub(busy[next-1].end)
+		}
+		if next < len(busy) {
+			after = busy[next].start.Sub(end)
+		}
+		room := (minDuration(before, roomyGap) + minDuration(after, roomyGap)).Seconds() / (2 * roomyGap).Seconds()
+
+		soon := 1.0
+		if span := windowEnd.Sub(windowStart) - duration; span > 0 {
+			soon = 1 - start.Sub(windowStart).Seconds()/span.Seconds()
+		}
+
+		score := 0.5*soon + 0.3*room + 0.2*comfort
+		candidates = append(candidates, candidate{interval{start, end}, math.Round(score*100) / 100})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	var chosen []interval
+	for _, c := range candidates {
+		if len(result.Slots) == maxSlots {
+			break
+		}
+		overlaps := false
+		for _, slot := range chosen {
+			if c.start.Before(slot.end) && slot.start.Before(c.end) {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		chosen = append(chosen, c.interval)
+
+		slot := &types.MeetingSlot{
+			Start:      c.start.In(loc).Format(time.RFC3339),
+			End:        c.end.In(loc).Format(time.RFC3339),
+			Score:      c.score,
+			LocalTimes: make(map[string]string),
+		}
+		for _, h := range hours {
+			slot.LocalTimes[h.loc.String()] = fmt.Sprintf("%s-%s", c.start.In(h.loc).Format("Mon 2006-01-02 15:04"), c.end.In(h.loc).Format("15:04"))
+		}
+		result.Slots = append(result.Slots, slot)
+	}
+	return result, nil
+}
+
+// parseWorkingHours checks and fills in the working hours of a search,
+// defaulting to office hours in the primary calendar's time zone
+func parseWorkingHours(ctx context.Context, b Backend, specs []*types.WorkingHours) ([]*workingHours, error) {
+	if len(specs) == 0 {
+		timeZone := "UTC"
+		if primary, err := b.GetCalendar(ctx, "primary"); err == nil && primary.TimeZone != "" {
+			timeZone = primary.TimeZone
+		}
+		specs = []*types.WorkingHours{{TimeZone: timeZone}}
+	}
+
+	hours := make([]*workingHours, 0, len(specs))
+	for _, spec := range specs {
+		loc, err := time.LoadLocation(spec.TimeZone)
+		if err != nil || spec.TimeZone == "" {
+			return nil, fmt.Errorf("invalid working hours time zone %q", spec.TimeZone)
+		}
+		h := &workingHours{loc: loc, days: make(map[time.Weekday]bool)}
+
+		start, end := spec.Start, spec.End
+		if start == "" {
+			start = defaultWorkStart
+		}
+		if end == "" {
+			end = defaultWorkEnd
+		}
+		if h.start, err = parseClock(start); err != nil {
+			return nil, fmt.Errorf("invalid working hours start: %w", err)
+		}
+		if h.end, err = parseClock(end); err != nil {
+			return nil, fmt.Errorf("invalid working hours end: %w", err)
+		}
+		if h.end <= h.start {
+			return nil, fmt.Errorf("working hours in %s end at %s, before they start at %s", spec.TimeZone, end, start)
+		}
+
+		days := spec.Days
+		if len(days) == 0 {
+			days = defaultWorkDays
+		}
+		for _, day := range days {
+			weekday, ok := workDays[strings.ToUpper(day)]
+			if !ok {
+				return nil, fmt.Errorf("invalid working day %q, expected MO to SU", day)
+			}
+			h.days[weekday] = true
+		}
+		hours = append(hours, h)
+	}
+	return hours, nil
+}
+
+// workingComfort reports whether [start, end) is within working hours in
+// every time zone, and its comfort in the zone where it is least central
+func workingComfort(hours []*workingHours, start, end time.Time) (float64, bool) {
+	least := 1.0
+	for _, h := range hours {
+		comfort, ok := h.comfort(start, end)
+		if !ok {
+			return 0, false
+		}
+		least = math.Min(least, comfort)
+	}
+	return least, true
+}
+
+// comfort reports whether [start, end) lies within one working day, and
+// how close its middle is to the middle of that day, from 0 at its edges
+// to 1
+func (h *workingHours) comfort(start, end time.Time) (float64, bool) {
+	local := start.In(h.loc)
+	if !h.days[local.Weekday()] {
+		return 0, false
+	}
+	year, month, day := local.Date()
+	dayStart := time.Date(year, month, day, 0, h.start, 0, 0, h.loc)
+	dayEnd := time.Date(year, month, day, 0, h.end, 0, 0, h.loc)
+	if start.Before(dayStart) || end.After(dayEnd) {
+		return 0, false
+	}
+
+	middle := start.Add(end.Sub(start) / 2)
+	position := middle.Sub(dayStart).Seconds() / dayEnd.Sub(dayStart).Seconds()
+	if position > 0.5 {
+		position = 1 - position
+	}
+	return 2 * position, true
+}
+
+// parseClock parses a time of day as HH:MM into minutes after midnight,
+// accepting 24:00 for the end of the day
+func parseClock(value string) (int, error) {
+	hh, mm, ok := strings.Cut(value, ":")
+	hour, hourErr := strconv.Atoi(hh)
+	minute, minuteErr := strconv.Atoi(mm)
+	if !ok || len(mm) != 2 || hourErr != nil || minuteErr != nil ||
+		hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("%q is not a time of day as HH:MM", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// alignStart returns the first time at or after t whose wall-clock time in
+// loc is a multiple of granularity after midnight. The grid follows the
+// wall clock, so it does not shift on days that gain or lose an hour.
+func alignStart(t time.Time, granularity time.Duration, loc *time.Location) time.Time {
+	local := t.In(loc)
+	year, month, day := local.Date()
+	hour, minute, second := local.Clock()
+	sinceMidnight := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(local.Nanosecond())
+	steps := (sinceMidnight + granularity - 1) / granularity
+	aligned := time.Date(year, month, day, 0, 0, 0, int(steps*granularity), loc)
+	// A wall-clock time that occurs twice resolves to the first
+	for aligned.Before(t) {
+		aligned = aligned.Add(granularity)
+	}
+	return aligned
+}
+
+// mergeIntervals sorts intervals and joins those that overlap or touch
+func mergeIntervals(intervals []interval) []interval {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start.Before(intervals[j].start)
+	})
+	var merged []interval
+	for _, in := range intervals {
+		if n := len(merged); n > 0 && !in.start.After(merged[n-1].end) {
+			if in.end.After(merged[n-1].end) {
+				merged[n-1].end = in.end
+			}
+			continue
+		}
+		merged = append(merged, in)
+	}
+	return merged
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,348 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// newMeetingFixture returns a MemoryBackend whose primary calendar, in UTC,
+// and whose calendar "bob" have these events on Monday 2025-01-06, and the
+// ID of bob's calendar:
+//
+//	primary  Standup 09:00-09:30, Lunch 12:00-13:00
+//	bob      Review 11:00-12:00, Design 11:30-12:30, Drinks 17:00-17:30
+func newMeetingFixture(t *testing.T) (*MemoryBackend, string) {
+	t.Helper()
+	ctx := context.Background()
+	b := NewMemoryBackend("")
+	bob, err := b.CreateCalendar(ctx, &types.CreateCalendarArgs{Summary: "bob", TimeZone: "UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []struct {
+		calendarID, summary, start, end string
+	}{
+		{"primary", "Standup", "09:00", "09:30"},
+		{"primary", "Lunch", "12:00", "13:00"},
+		{bob, "Review", "11:00", "12:00"},
+		{bob, "Design", "11:30", "12:30"},
+		{bob, "Drinks", "17:00", "17:30"},
+	}
+	for _, event := range events {
+		if _, err := b.CreateEvent(ctx, &types.CreateEventArgs{
+			CalendarID: event.calendarID,
+			Summary:    event.summary,
+			StartTime:  "2025-01-06T" + event.start + ":00Z",
+			EndTime:    "2025-01-06T" + event.end + ":00Z",
+		}); err != nil {
+			t.Fatalf("CreateEvent(%s) error = %v", event.summary, err)
+		}
+	}
+	return b, bob
+}
+
+// slots describes meeting slots by start and score, best first
+func slots(result *types.MeetingTimesResult) []string {
+	var out []string
+	for _, slot := range result.Slots {
+		out = append(out, fmt.Sprintf("%s %.2f", slot.Start, slot.Score))
+	}
+	return out
+}
+
+func TestFindMeetingTimes(t *testing.T) {
+	b, bob := newMeetingFixture(t)
+	workday := func(args types.FindMeetingTimesArgs) types.FindMeetingTimesArgs {
+		if args.Attendees == nil {
+			args.Attendees = []string{"primary", bob}
+		}
+		if args.DurationMinutes == 0 {
+			args.DurationMinutes = 60
+		}
+		if args.TimeMin == "" {
+			args.TimeMin, args.TimeMax = "2025-01-06T09:00:00Z", "2025-01-06T17:00:00Z"
+		}
+		return args
+	}
+
+	tests := []struct {
+		name      string
+		args      types.FindMeetingTimesArgs
+		want      []string
+		unchecked map[string]string
+	}{
+		{
+			// Busy periods of both calendars merge into 09:00-09:30 and
+			// 11:00-13:00. The best candidates are picked as long as they do
+			// not overlap an earlier pick.
+			name: "best slots that do not overlap",
+			args: workday(types.FindMeetingTimesArgs{MaxResults: 4}),
+			want: []string{
+				"2025-01-06T09:30:00Z 0.59",
+				"2025-01-06T14:00:00Z 0.57",
+				"2025-01-06T13:00:00Z 0.54",
+				"2025-01-06T15:00:00Z 0.45",
+			},
+		},
+		{
+			// Bob's review starts as the window ends
+			name: "one attendee",
+			args: workday(types.FindMeetingTimesArgs{
+				Attendees:       []string{bob},
+				DurationMinutes: 30,
+				TimeMin:         "2025-01-06T09:00:00Z",
+				TimeMax:         "2025-01-06T11:00:00Z",
+			}),
+			want: []string{
+				"2025-01-06T09:00:00Z 0.81",
+				"2025-01-06T09:30:00Z 0.67",
+				"2025-01-06T10:00:00Z 0.53",
+				"2025-01-06T10:30:00Z 0.39",
+			},
+		},
+		{
+			name: "buffer around events",
+			args: workday(types.FindMeetingTimesArgs{BufferMinutes: 15, MaxResults: 10}),
+			want: []string{"2025-01-06T14:00:00Z 0.57", "2025-01-06T15:00:00Z 0.45"},
+		},
+		{
+			name: "events just after the window need their buffer",
+			args: workday(types.FindMeetingTimesArgs{TimeMin: "2025-01-06T16:00:00Z", TimeMax: "2025-01-06T17:00:00Z", BufferMinutes: 15}),
+		},
+		{
+			name: "events just before the window need their buffer",
+			args: workday(types.FindMeetingTimesArgs{TimeMin: "2025-01-06T13:00:00Z", TimeMax: "2025-01-06T14:00:00Z", BufferMinutes: 15}),
+		},
+		{
+			// Lunch ends as the window starts, and events outside the
+			// window do not count as neighbours
+			name: "events touching the window",
+			args: workday(types.FindMeetingTimesArgs{DurationMinutes: 30, TimeMin: "2025-01-06T13:00:00Z", TimeMax: "2025-01-06T14:00:00Z"}),
+			want: []string{"2025-01-06T13:00:00Z 0.99", "2025-01-06T13:30:00Z 0.46"},
+		},
+		{
+			name: "unaligned window",
+			args: workday(types.FindMeetingTimesArgs{TimeMin: "2025-01-06T13:10:00Z", TimeMax: "2025-01-06T14:40:00Z", GranularityMinutes: 15}),
+			want: []string{"2025-01-06T13:15:00Z 0.88"},
+		},
+		{
+			// 14:00-17:00 UTC is 09:00-12:00 in New York
+			name: "working hours in every time zone",
+			args: workday(types.FindMeetingTimesArgs{
+				Attendees: []string{"primary"},
+				WorkingHours: []*types.WorkingHours{
+					{TimeZone: "UTC"},
+					{TimeZone: "America/New_York"},
+				},
+				MaxResults: 2,
+			}),
+			want: []string{"2025-01-06T14:00:00Z 0.47", "2025-01-06T15:00:00Z 0.45"},
+		},
+		{
+			name: "working days",
+			args: workday(types.FindMeetingTimesArgs{
+				WorkingHours: []*types.WorkingHours{{TimeZone: "UTC", Days: []string{"TU"}}},
+			}),
+		},
+		{
+			name: "attendees without free/busy information",
+			args: workday(types.FindMeetingTimesArgs{
+				Attendees:  []string{"primary", "carol@example.com"},
+				TimeMin:    "2025-01-06T09:00:00Z",
+				TimeMax:    "2025-01-06T11:00:00Z",
+				MaxResults: 1,
+			}),
+			want:      []string{"2025-01-06T09:30:00Z 0.45"},
+			unchecked: map[string]string{"carol@example.com": "notFound"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FindMeetingTimes(context.Background(), b, &tt.args)
+			if err != nil {
+				t.Fatalf("FindMeetingTimes() error = %v", err)
+			}
+			if got := slots(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("slots = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(result.UncheckedAttendees, tt.unchecked) {
+				t.Errorf("unchecked attendees = %v, want %v", result.UncheckedAttendees, tt.unchecked)
+			}
+		})
+	}
+}
+
+func TestFindMeetingTimesLocalTimes(t *testing.T) {
+	b := NewMemoryBackend("")
+	result, err := FindMeetingTimes(context.Background(), b, &types.FindMeetingTimesArgs{
+		Attendees:       []string{"primary"},
+		DurationMinutes: 30,
+		TimeMin:         "2025-01-06T00:00:00Z",
+		TimeMax:         "2025-01-07T00:00:00Z",
+		WorkingHours: []*types.WorkingHours{
+			{TimeZone: "Europe/London", Start: "13:00", End: "18:00"},
+			{TimeZone: "Asia/Tokyo", Start: "00:00", End: "24:00", Days: []string{"MO", "TU"}},
+		},
+		MaxResults: 1,
+	})
+	if err != nil {
+		t.Fatalf("FindMeetingTimes() error = %v", err)
+	}
+	if len(result.Slots) != 1 {
+		t.Fatalf("slots = %q, want one", slots(result))
+	}
+	// Tokyo is nine hours ahead, so its Monday ends at 15:00 in London
+	want := map[string]string{
+		"Europe/London": "Mon 2025-01-06 13:00-13:30",
+		"Asia/Tokyo":    "Mon 2025-01-06 22:00-22:30",
+	}
+	if got := result.Slots[0].LocalTimes; !reflect.DeepEqual(got, want) {
+		t.Errorf("local times = %v, want %v", got, want)
+	}
+}
+
+func TestFindMeetingTimesAcrossDST(t *testing.T) {
+	// New York moves to daylight saving time on Sunday 2025-03-09, so the
+	// working day starts at 14:00 UTC on Friday and at 13:00 UTC on Monday
+	result, err := FindMeetingTimes(context.Background(), NewMemoryBackend(""), &types.FindMeetingTimesArgs{
+		Attendees:          []string{"primary"},
+		DurationMinutes:    45,
+		GranularityMinutes: 45,
+		TimeMin:            "2025-03-07T00:00:00Z",
+		TimeMax:            "2025-03-11T00:00:00Z",
+		WorkingHours:       []*types.WorkingHours{{TimeZone: "America/New_York", Start: "09:00", End: "11:00"}},
+		MaxResults:         10,
+	})
+	if err != nil {
+		t.Fatalf("FindMeetingTimes() error = %v", err)
+	}
+
+	var got []string
+	for _, slot := range result.Slots {
+		got = append(got, slot.LocalTimes["America/New_York"])
+	}
+	// The grid runs 00:00, 00:45, 01:30, ... on the wall clock every day,
+	// and slots nearer the middle of the day rank higher
+	want := []string{
+		"Fri 2025-03-07 09:45-10:30",
+		"Fri 2025-03-07 09:00-09:45",
+		"Mon 2025-03-10 09:45-10:30",
+		"Mon 2025-03-10 09:00-09:45",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("slots = %q, want %q", got, want)
+	}
+}
+
+func TestFindMeetingTimesErrors(t *testing.T) {
+	b, _ := newMeetingFixture(t)
+	valid := func(args types.FindMeetingTimesArgs) *types.FindMeetingTimesArgs {
+		if args.Attendees == nil {
+			args.Attendees = []string{"primary"}
+		}
+		if args.DurationMinutes == 0 {
+			args.DurationMinutes = 30
+		}
+		if args.TimeMin == "" {
+			args.TimeMin = "2025-01-06T09:00:00Z"
+		}
+		if args.TimeMax == "" {
+			args.TimeMax = "2025-01-06T17:00:00Z"
+		}
+		return &args
+	}
+
+	tests := []struct {
+		name string
+		args *types.FindMeetingTimesArgs
+	}{
+		{"no attendees", valid(types.FindMeetingTimesArgs{Attendees: []string{}})},
+		{"negative duration", valid(types.FindMeetingTimesArgs{DurationMinutes: -30})},
+		{"negative buffer", valid(types.FindMeetingTimesArgs{BufferMinutes: -5})},
+		{"window shorter than the meeting", valid(types.FindMeetingTimesArgs{TimeMax: "2025-01-06T09:15:00Z"})},
+		{"invalid time zone", valid(types.FindMeetingTimesArgs{WorkingHours: []*types.WorkingHours{{TimeZone: "Mars/Olympus"}}})},
+		{"day ends before it starts", valid(types.FindMeetingTimesArgs{WorkingHours: []*types.WorkingHours{{TimeZone: "UTC", Start: "17:00", End: "09:00"}}})},
+		{"invalid day", valid(types.FindMeetingTimesArgs{WorkingHours: []*types.WorkingHours{{TimeZone: "UTC", Days: []string{"XX"}}}})},
+		{"no attendee can be queried", valid(types.FindMeetingTimesArgs{Attendees: []string{"carol@example.com"}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result, err := FindMeetingTimes(context.Background(), b, tt.args); err == nil {
+				t.Errorf("FindMeetingTimes() = %q, want an error", slots(result))
+			}
+		})
+	}
+}
+
+func TestAlignStart(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		t           string
+		granularity time.Duration
+		loc         *time.Location
+		want        string
+	}{
+		{"aligned", "2025-01-06T10:30:00Z", 30 * time.Minute, time.UTC, "2025-01-06T10:30:00Z"},
+		{"rounds up", "2025-01-06T10:07:00Z", 30 * time.Minute, time.UTC, "2025-01-06T10:30:00Z"},
+		{"next day", "2025-01-06T23:50:00Z", 30 * time.Minute, time.UTC, "2025-01-07T00:00:00Z"},
+		{"in the zone", "2025-01-06T14:10:00Z", time.Hour, newYork, "2025-01-06T10:00:00-05:00"},
+		// 2025-03-09 loses 02:00-03:00 in New York
+		{"after spring forward", "2025-03-09T07:10:00Z", 45 * time.Minute, newYork, "2025-03-09T03:45:00-04:00"},
+		{"skipped hour", "2025-03-09T06:59:00Z", 30 * time.Minute, newYork, "2025-03-09T03:00:00-04:00"},
+		// 2025-11-02 has 01:00-02:00 twice in New York
+		{"after fall back", "2025-11-02T15:10:00Z", 45 * time.Minute, newYork, "2025-11-02T10:30:00-05:00"},
+		{"repeated hour", "2025-11-02T06:10:00Z", 30 * time.Minute, newYork, "2025-11-02T01:30:00-05:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := alignStart(at, tt.granularity, tt.loc).In(tt.loc).Format(time.RFC3339); got != tt.want {
+				t.Errorf("alignStart(%s, %v) = %s, want %s", tt.t, tt.granularity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeIntervals(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, time.January, 6, hour, minute, 0, 0, time.UTC)
+	}
+	span := func(from, to int) interval {
+		return interval{at(from, 0), at(to, 0)}
+	}
+
+	tests := []struct {
+		name string
+		in   []interval
+		want []interval
+	}{
+		{"none", nil, nil},
+		{"apart", []interval{span(9, 10), span(11, 12)}, []interval{span(9, 10), span(11, 12)}},
+		{"unsorted", []interval{span(11, 12), span(9, 10)}, []interval{span(9, 10), span(11, 12)}},
+		{"overlapping", []interval{span(9, 11), span(10, 12)}, []interval{span(9, 12)}},
+		{"touching", []interval{span(9, 10), span(10, 11)}, []interval{span(9, 11)}},
+		{"contained", []interval{span(9, 13), span(10, 11), span(12, 14)}, []interval{span(9, 14)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeIntervals(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeIntervals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
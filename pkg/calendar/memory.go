@@ -0,0 +1,983 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/recurrence"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultMemoryOwner owns the primary calendar of a MemoryBackend created
+// without an owner
+const DefaultMemoryOwner = "me@example.com"
+
+// timestampFormat is how Google formats created and updated times
+const timestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// MemoryBackend is a Backend that keeps calendars and events in memory,
+// for demos and tests. It follows the Google Calendar API semantics the
+// Client relies on: timeMin and timeMax select events overlapping the range,
+// query terms must all appear in an event's text, all-day events span whole
+// days in their calendar's time zone with an exclusive end date, recurring
+// events match when any of their occurrences does, and errors are
+// *googleapi.Error values with the same status codes.
+type MemoryBackend struct {
+	mu        sync.RWMutex
+	owner     string
+	calendars map[string]*memoryCalendar
+	order     []string // calendar IDs in creation order
+	now       func() time.Time
+}
+
+type memoryCalendar struct {
+	calendar *types.Calendar
+	events   map[string]*memoryEvent
+	order    []string // event IDs in creation order
+}
+
+type memoryEvent struct {
+	event      *types.CalendarEvent
+	start      time.Time // in the event's time zone
+	end        time.Time
+	recurrence *recurrence.Set // nil for single events
+
+	// exceptions are the modified and cancelled instances of a recurring
+	// event by instance ID
+	exceptions map[string]*memoryEvent
+}
+
+// maxTime stands in for an open upper bound when expanding recurrences
+var maxTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// NewMemoryBackend creates an empty backend whose primary calendar belongs
+// to owner, or to DefaultMemoryOwner if owner is empty
+func NewMemoryBackend(owner string) *MemoryBackend {
+	if owner == "" {
+		owner = DefaultMemoryOwner
+	}
+	b := &MemoryBackend{
+		owner:     owner,
+		calendars: make(map[string]*memoryCalendar),
+		now:       time.Now,
+	}
+	b.addCalendar(&types.Calendar{
+		ID:         owner,
+		Summary:    owner,
+		Primary:    true,
+		AccessRole: "owner",
+		TimeZone:   "UTC",
+	})
+	return b
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+func (b *MemoryBackend) addCalendar(cal *types.Calendar) {
+	b.calendars[cal.ID] = &memoryCalendar{
+		calendar: cal,
+		events:   make(map[string]*memoryEvent),
+	}
+	b.order = append(b.order, cal.ID)
+}
+
+// calendar resolves a calendar ID, including the "primary" alias. Callers
+// hold b.mu.
+func (b *MemoryBackend) calendar(calendarID string) (*memoryCalendar, error) {
+	if calendarID == "" || calendarID == "primary" {
+		calendarID = b.owner
+	}
+	cal, ok := b.calendars[calendarID]
+	if !ok {
+		return nil, apiError(http.StatusNotFound, "Not Found")
+	}
+	return cal, nil
+}
+
+// CreateEvent creates a new calendar event
+func (b *MemoryBackend) CreateEvent(ctx context.Context, args *types.CreateEventArgs) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines, err := recurrence.Lines(args.Recurrence, args.RecurrenceRule, args.AllDay)
+	if err != nil {
+		return "", fmt.Errorf("invalid recurrence: %w", err)
+	}
+
+	cal, err := b.calendar(args.CalendarID)
+	if err != nil {
+		return "", fmt.Errorf("failed to create event: %w", err)
+	}
+
+	now := b.now().UTC().Format(timestampFormat)
+	event := &types.CalendarEvent{
+		ID:          newMemoryID(),
+		Summary:     args.Summary,
+		Description: args.Description,
+		Location:    args.Location,
+		Creator:     b.owner,
+		Organizer:   cal.calendar.ID,
+		Status:      "confirmed",
+		Created:     now,
+		Updated:     now,
+		Attendees:   memoryAttendees(args.Attendees),
+		Reminders:   copyReminders(args.Reminders),
+		Recurrence:  lines,
+	}
+	if err := setEventTimes(event, args.StartTime, args.EndTime, args.TimeZone); err != nil {
+		return "", err
+	}
+	if args.AllDay {
+		if args.StartDate != "" {
+			event.StartTime, event.StartTimeZone, event.StartDate = "", "", args.StartDate
+		}
+		if args.EndDate != "" {
+			event.EndTime, event.EndTimeZone, event.EndDate = "", "", args.EndDate
+		}
+		event.AllDay = event.StartDate != "" || event.EndDate != ""
+	}
+
+	stored, err := cal.newEvent(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to create event: %w", err)
+	}
+	cal.events[event.ID] = stored
+	cal.order = append(cal.order, event.ID)
+
+	return event.ID, nil
+}
+
+// GetEvent retrieves a calendar event by ID
+func (b *MemoryBackend) GetEvent(ctx context.Context, calendarID, eventID string) (*types.CalendarEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	event, err := b.event(calendarID, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return copyEvent(event.event), nil
+}
+
+// event finds an event. Callers hold b.mu.
+func (b *MemoryBackend) event(calendarID, eventID string) (*memoryEvent, error) {
+	cal, err := b.calendar(calendarID)
+	if err != nil {
+		return nil, err
+	}
+	event, _, err := cal.lookup(eventID)
+	return event, err
+}
+
+// lookup finds an event or an instance of a recurring event, which comes
+// with the recurring event it belongs to
+func (c *memoryCalendar) lookup(eventID string) (*memoryEvent, *memoryEvent, error) {
+	if event, ok := c.events[eventID]; ok {
+		return event, nil, nil
+	}
+
+	notFound := apiError(http.StatusNotFound, "Not Found")
+	masterID, start, allDay, ok := recurrence.ParseInstanceID(eventID)
+	if !ok {
+		return nil, nil, notFound
+	}
+	master, ok := c.events[masterID]
+	if !ok || master.recurrence == nil || allDay != master.event.AllDay {
+		return nil, nil, notFound
+	}
+
+	if exception, ok := master.exceptions[eventID]; ok {
+		if exception.event.Status == "cancelled" {
+			return nil, nil, notFound
+		}
+		return exception, master, nil
+	}
+	if allDay {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, master.start.Location())
+	} else {
+		start = start.In(master.start.Location())
+	}
+	if len(master.recurrence.Between(master.start, start, start.Add(1), 1)) == 0 {
+		return nil, nil, notFound
+	}
+	return master.instance(start), master, nil
+}
+
+// UpdateEvent updates an existing calendar event
+func (b *MemoryBackend) UpdateEvent(ctx context.Context, args *types.UpdateEventArgs) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cal, err := b.calendar(args.CalendarID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing event: %w", err)
+	}
+	existing, master, err := cal.lookup(args.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing event: %w", err)
+	}
+
+	event := copyEvent(existing.event)
+	if len(args.Recurrence) > 0 || args.RecurrenceRule != nil {
+		if master != nil {
+			return fmt.Errorf("failed to update event: %w", apiError(http.StatusBadRequest, "Recurrence can only be set on the recurring event, not on its instances."))
+		}
+		lines, err := recurrence.Lines(args.Recurrence, args.RecurrenceRule, event.AllDay)
+		if err != nil {
+			return fmt.Errorf("invalid recurrence: %w", err)
+		}
+		event.Recurrence = lines
+	}
+	if args.Summary != "" {
+		event.Summary = args.Summary
+	}
+	if args.Description != "" {
+		event.Description = args.Description
+	}
+	if args.Location != "" {
+		event.Location = args.Location
+	}
+	if args.StartTime != "" {
+		event.StartDate = ""
+	}
+	if args.EndTime != "" {
+		event.EndDate = ""
+	}
+	timeZone := args.TimeZone
+	if timeZone == "" && len(event.Recurrence) > 0 {
+		// A recurring event keeps the time zone its rule is expanded in
+		timeZone = event.StartTimeZone
+	}
+	if err := setEventTimes(event, args.StartTime, args.EndTime, timeZone); err != nil {
+		return err
+	}
+	event.AllDay = event.StartDate != "" || event.EndDate != ""
+	if len(args.Attendees) > 0 {
+		event.Attendees = memoryAttendees(args.Attendees)
+	}
+	if len(args.Reminders) > 0 {
+		event.Reminders = copyReminders(args.Reminders)
+	}
+	event.Updated = b.now().UTC().Format(timestampFormat)
+
+	stored, err := cal.newEvent(event)
+	if err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	if master != nil {
+		// Changing an instance makes it an exception to its series
+		master.exceptions[event.ID] = stored
+		return nil
+	}
+	if existing.exceptions != nil && stored.exceptions != nil {
+		stored.exceptions = existing.exceptions
+	}
+	cal.events[event.ID] = stored
+
+	return nil
+}
+
+// DeleteEvent deletes a calendar event
+func (b *MemoryBackend) DeleteEvent(ctx context.Context, calendarID, eventID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cal, err := b.calendar(calendarID)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	existing, master, err := cal.lookup(eventID)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	if master != nil {
+		// Deleting an instance cancels it and leaves the series alone
+		cancelled := *existing
+		cancelled.event = copyEvent(existing.event)
+		cancelled.event.Status = "cancelled"
+		cancelled.event.Updated = b.now().UTC().Format(timestampFormat)
+		master.exceptions[eventID] = &cancelled
+		return nil
+	}
+	delete(cal.events, eventID)
+	cal.order = removeID(cal.order, eventID)
+
+	return nil
+}
+
+// putEvents stores events synced from another backend under their own IDs,
+// replacing earlier versions of them. Cancelled events are removed, and
+// instances of recurring events become exceptions to their series. It
+// returns the IDs of the events it could not index, which are left out.
+func (b *MemoryBackend) putEvents(calendarID string, events []*types.CalendarEvent) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cal, err := b.calendar(calendarID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Series go first, so that their exceptions find them
+	sorted := append([]*types.CalendarEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].RecurringEventID == "" && sorted[j].RecurringEventID != ""
+	})
+
+	var skipped []string
+	for _, event := range sorted {
+		event = copyEvent(event)
+		if master, ok := cal.events[event.RecurringEventID]; ok && master.recurrence != nil {
+			if event.Status == "cancelled" {
+				master.exceptions[event.ID] = &memoryEvent{event: event}
+				continue
+			}
+			stored, err := cal.newEvent(event)
+			if err != nil {
+				skipped = append(skipped, event.ID)
+				continue
+			}
+			master.exceptions[event.ID] = stored
+			continue
+		}
+
+		// Exceptions to series we do not have are kept as events of their own
+		if event.Status == "cancelled" {
+			if _, ok := cal.events[event.ID]; ok {
+				delete(cal.events, event.ID)
+				cal.order = removeID(cal.order, event.ID)
+			}
+			continue
+		}
+		stored, err := cal.newEvent(event)
+		if err != nil {
+			skipped = append(skipped, event.ID)
+			continue
+		}
+		if existing, ok := cal.events[event.ID]; ok {
+			if existing.exceptions != nil && stored.exceptions != nil {
+				stored.exceptions = existing.exceptions
+			}
+		} else {
+			cal.order = append(cal.order, event.ID)
+		}
+		cal.events[event.ID] = stored
+	}
+	return skipped, nil
+}
+
+// syncedEvents returns the events of a calendar as putEvents takes them:
+// single and recurring events followed by the exceptions to each series
+func (b *MemoryBackend) syncedEvents(calendarID string) ([]*types.CalendarEvent, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	cal, err := b.calendar(calendarID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*types.CalendarEvent
+	for _, id := range cal.order {
+		event := cal.events[id]
+		events = append(events, copyEvent(event.event))
+
+		exceptionIDs := make([]string, 0, len(event.exceptions))
+		for exceptionID := range event.exceptions {
+			exceptionIDs = append(exceptionIDs, exceptionID)
+		}
+		sort.Strings(exceptionIDs)
+		for _, exceptionID := range exceptionIDs {
+			events = append(events, copyEvent(event.exceptions[exceptionID].event))
+		}
+	}
+	return events, nil
+}
+
+// ListEvents lists calendar events
+func (b *MemoryBackend) ListEvents(ctx context.Context, args *types.ListEventsArgs) ([]*types.CalendarEvent, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	timeMin, err := parseBound("timeMin", args.TimeMin)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list events: %w", err)
+	}
+	timeMax, err := parseBound("timeMax", args.TimeMax)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list events: %w", err)
+	}
+	start, err := parseCursor(args.Cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list events: %w", err)
+	}
+	terms := strings.Fields(strings.ToLower(args.Query))
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	cal, err := b.calendar(args.CalendarID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list events: %w", err)
+	}
+
+	// Recurring events are listed once, unless they are expanded into
+	// their instances
+	singleEvents := args.SingleEvents || args.OrderBy == "startTime"
+	var matches []*memoryEvent
+	for _, id := range cal.order {
+		event := cal.events[id]
+		if singleEvents {
+			for _, instance := range event.instances(timeMin, timeMax, 0) {
+				if matchesQuery(instance.event, terms) {
+					matches = append(matches, instance)
+				}
+			}
+			continue
+		}
+		if matchesQuery(event.event, terms) && len(event.instances(timeMin, timeMax, 1)) > 0 {
+			matches = append(matches, event)
+		}
+		// Changed instances are listed next to their series, as on Google
+		for _, instance := range event.instances(timeMin, timeMax, 0) {
+			if _, ok := event.exceptions[instance.event.ID]; ok && matchesQuery(instance.event, terms) {
+				matches = append(matches, instance)
+			}
+		}
+	}
+
+	switch args.OrderBy {
+	case "startTime":
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].start.Before(matches[j].start)
+		})
+	case "updated":
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].event.Updated < matches[j].event.Updated
+		})
+	}
+
+	// Everything is on one page, so a cursor is just an offset into it
+	var events []*types.CalendarEvent
+	p := newPager(args.MaxResults, start, len(matches))
+	p.page(len(matches), "", func(i int) {
+		events = append(events, copyEvent(matches[i].event))
+	})
+	reportProgress(ctx, 1, 0, fmt.Sprintf("Fetched %d events (page 1)", len(events)))
+
+	return events, p.next, nil
+}
+
+// ListInstances lists the instances of a recurring event. A single event
+// is its own only instance.
+func (b *MemoryBackend) ListInstances(ctx context.Context, args *types.ListInstancesArgs) ([]*types.CalendarEvent, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	timeMin, err := parseBound("timeMin", args.TimeMin)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+	timeMax, err := parseBound("timeMax", args.TimeMax)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+	start, err := parseCursor(args.Cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	cal, err := b.calendar(args.CalendarID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+	event, ok := cal.events[args.EventID]
+	if !ok {
+		return nil, "", fmt.Errorf("failed to list instances: %w", apiError(http.StatusNotFound, "Not Found"))
+	}
+
+	// Series may never end, so only expand them as far as this page and
+	// one more instance, which tells whether another page follows
+	limit := 0
+	if args.MaxResults > 0 {
+		limit = start.Offset + args.MaxResults + 1
+	}
+	matches := event.instances(timeMin, timeMax, limit)
+
+	var instances []*types.CalendarEvent
+	p := newPager(args.MaxResults, start, len(matches))
+	p.page(len(matches), "", func(i int) {
+		instances = append(instances, copyEvent(matches[i].event))
+	})
+	reportProgress(ctx, 1, 0, fmt.Sprintf("Fetched %d instances (page 1)", len(instances)))
+
+	return instances, p.next, nil
+}
+
+// ListCalendars lists available calendars
+func (b *MemoryBackend) ListCalendars(ctx context.Context, args *types.ListCalendarsArgs) ([]*types.Calendar, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	start, err := parseCursor(args.Cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list calendars: %w", err)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var calendars []*types.Calendar
+	p := newPager(args.MaxResults, start, len(b.order))
+	p.page(len(b.order), "", func(i int) {
+		cal := *b.calendars[b.order[i]].calendar
+		calendars = append(calendars, &cal)
+	})
+	return calendars, p.next, nil
+}
+
+// GetCalendar retrieves a specific calendar
+func (b *MemoryBackend) GetCalendar(ctx context.Context, calendarID string) (*types.Calendar, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	cal, err := b.calendar(calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar: %w", err)
+	}
+
+	// Like calendars.get, this carries no calendar list properties
+	return &types.Calendar{
+		ID:          cal.calendar.ID,
+		Summary:     cal.calendar.Summary,
+		Description: cal.calendar.Description,
+		TimeZone:    cal.calendar.TimeZone,
+	}, nil
+}
+
+// CreateCalendar creates a new calendar
+func (b *MemoryBackend) CreateCalendar(ctx context.Context, args *types.CreateCalendarArgs) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if args.Summary == "" {
+		return "", fmt.Errorf("failed to create calendar: %w", apiError(http.StatusBadRequest, "Missing title."))
+	}
+	timeZone := args.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return "", fmt.Errorf("failed to create calendar: %w", apiError(http.StatusBadRequest, "Invalid time zone definition."))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cal := &types.Calendar{
+		ID:          newMemoryID() + "@group.calendar.google.com",
+		Summary:     args.Summary,
+		Description: args.Description,
+		AccessRole:  "owner",
+		TimeZone:    timeZone,
+	}
+	b.addCalendar(cal)
+
+	return cal.ID, nil
+}
+
+// DeleteCalendar deletes a calendar
+func (b *MemoryBackend) DeleteCalendar(ctx context.Context, calendarID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cal, err := b.calendar(calendarID)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar: %w", err)
+	}
+	if cal.calendar.Primary {
+		return fmt.Errorf("failed to delete calendar: %w", apiError(http.StatusBadRequest, "Cannot delete primary calendar."))
+	}
+	delete(b.calendars, cal.calendar.ID)
+	b.order = removeID(b.order, cal.calendar.ID)
+
+	return nil
+}
+
+// GetFreeBusy gets free/busy information. Every event is opaque, and
+// unknown calendars are reported with a notFound error, as Google does.
+func (b *MemoryBackend) GetFreeBusy(ctx context.Context, args *types.FreeBusyArgs) (*types.FreeBusyResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	timeMin, err := time.Parse(time.RFC3339, args.TimeMin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get free/busy: %w", apiError(http.StatusBadRequest, "Bad Request"))
+	}
+	timeMax, err := time.Parse(time.RFC3339, args.TimeMax)
+	if err != nil || timeMax.Before(timeMin) {
+		return nil, fmt.Errorf("failed to get free/busy: %w", apiError(http.StatusBadRequest, "Bad Request"))
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	result := &types.FreeBusyResponse{
+		TimeMin:   timeMin.UTC().Format(timestampFormat),
+		TimeMax:   timeMax.UTC().Format(timestampFormat),
+		Calendars: make(map[string]*types.FreeBusyCalendar),
+	}
+	for _, calID := range args.CalendarIDs {
+		cal, err := b.calendar(calID)
+		if err != nil {
+			result.Calendars[calID] = &types.FreeBusyCalendar{Busy: []*types.TimePeriod{}, Errors: []string{"notFound"}}
+			continue
+		}
+		result.Calendars[calID] = &types.FreeBusyCalendar{Busy: cal.busy(timeMin, timeMax)}
+	}
+	reportProgress(ctx, 1, 1, fmt.Sprintf("Queried %d of %d calendars", len(args.CalendarIDs), len(args.CalendarIDs)))
+
+	return result, nil
+}
+
+// busy returns the merged periods in which the calendar has events, clipped
+// to [timeMin, timeMax)
+func (c *memoryCalendar) busy(timeMin, timeMax time.Time) []*types.TimePeriod {
+	type span struct{ start, end time.Time }
+
+	var spans []span
+	for _, event := range c.events {
+		for _, instance := range event.instances(timeMin, timeMax, 0) {
+			start, end := instance.start, instance.end
+			if start.Before(timeMin) {
+				start = timeMin
+			}
+			if end.After(timeMax) {
+				end = timeMax
+			}
+			if end.After(start) {
+				spans = append(spans, span{start, end})
+			}
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start.Before(spans[j].start)
+	})
+
+	var merged []span
+	for _, s := range spans {
+		if n := len(merged); n > 0 && !s.start.After(merged[n-1].end) {
+			if s.end.After(merged[n-1].end) {
+				merged[n-1].end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	busy := make([]*types.TimePeriod, len(merged))
+	for i, s := range merged {
+		busy[i] = &types.TimePeriod{
+			Start: s.start.UTC().Format(time.RFC3339),
+			End:   s.end.UTC().Format(time.RFC3339),
+		}
+	}
+	return busy
+}
+
+// newEvent validates an event's times and indexes it for range queries.
+// All-day events are interpreted in the calendar's time zone.
+func (c *memoryCalendar) newEvent(event *types.CalendarEvent) (*memoryEvent, error) {
+	loc, err := time.LoadLocation(c.calendar.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	start, err := eventTime(event.StartTime, event.StartDate, loc)
+	if err != nil {
+		return nil, apiError(http.StatusBadRequest, "Missing start time.")
+	}
+	end, err := eventTime(event.EndTime, event.EndDate, loc)
+	if err != nil {
+		return nil, apiError(http.StatusBadRequest, "Missing end time.")
+	}
+	if (event.StartDate != "") != (event.EndDate != "") {
+		return nil, apiError(http.StatusBadRequest, "Start and end times must either both be date or both be dateTime.")
+	}
+	if end.Before(start) || (event.AllDay && !end.After(start)) {
+		return nil, apiError(http.StatusBadRequest, "The specified time range is empty.")
+	}
+
+	stored := &memoryEvent{event: event, start: start, end: end}
+	if len(event.Recurrence) > 0 {
+		set, err := recurrence.Parse(event.Recurrence)
+		if err != nil {
+			return nil, apiError(http.StatusBadRequest, "Invalid recurrence rule.")
+		}
+		stored.recurrence = set
+		stored.exceptions = make(map[string]*memoryEvent)
+
+		// Occurrences keep the wall-clock time of the first one in the
+		// event's time zone, UTC unless it has one
+		if event.StartDate == "" {
+			zone := time.UTC
+			if event.StartTimeZone != "" {
+				if zone, err = time.LoadLocation(event.StartTimeZone); err != nil {
+					return nil, apiError(http.StatusBadRequest, "Invalid time zone definition for start time.")
+				}
+			}
+			stored.start, stored.end = start.In(zone), end.In(zone)
+		}
+	}
+	return stored, nil
+}
+
+// occurrences returns the start times of the event's occurrences that
+// overlap [timeMin, timeMax), at most limit of them when limit is positive.
+// Zero bounds are open.
+func (e *memoryEvent) occurrences(timeMin, timeMax time.Time, limit int) []time.Time {
+	if timeMax.IsZero() {
+		timeMax = maxTime
+	}
+	duration := e.end.Sub(e.start)
+
+	// An occurrence overlaps when it ends after timeMin
+	var from time.Time
+	if !timeMin.IsZero() {
+		from = timeMin.Add(-duration + 1)
+	}
+
+	if e.recurrence == nil {
+		if e.start.Before(from) || !e.start.Before(timeMax) {
+			return nil
+		}
+		return []time.Time{e.start}
+	}
+	return e.recurrence.Between(e.start, from, timeMax, limit)
+}
+
+// instances returns the event if it overlaps [timeMin, timeMax) or, for a
+// recurring event, its instances that do, in order and at most limit of them
+// when limit is positive. Zero bounds are open.
+func (e *memoryEvent) instances(timeMin, timeMax time.Time, limit int) []*memoryEvent {
+	if e.recurrence == nil {
+		if len(e.occurrences(timeMin, timeMax, 1)) == 0 {
+			return nil
+		}
+		return []*memoryEvent{e}
+	}
+
+	// Exceptions may have moved into or out of the range
+	want := 0
+	if limit > 0 {
+		want = limit + len(e.exceptions)
+	}
+	var instances []*memoryEvent
+	for _, start := range e.occurrences(timeMin, timeMax, want) {
+		if _, ok := e.exceptions[recurrence.InstanceID(e.event.ID, start, e.event.AllDay)]; !ok {
+			instances = append(instances, e.instance(start))
+		}
+	}
+	for _, exception := range e.exceptions {
+		if exception.event.Status != "cancelled" && len(exception.occurrences(timeMin, timeMax, 1)) > 0 {
+			instances = append(instances, exception)
+		}
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].start.Before(instances[j].start)
+	})
+	if limit > 0 && len(instances) > limit {
+		instances = instances[:limit]
+	}
+	return instances
+}
+
+// instance returns the unmodified occurrence of a recurring event starting
+// at start
+func (e *memoryEvent) instance(start time.Time) *memoryEvent {
+	end := start.Add(e.end.Sub(e.start))
+	if e.event.AllDay {
+		// Whole days, whatever daylight saving does to their length
+		days := int(e.end.Sub(e.start).Round(24*time.Hour) / (24 * time.Hour))
+		end = start.AddDate(0, 0, days)
+	}
+	event := copyEvent(e.event)
+	event.ID = recurrence.InstanceID(e.event.ID, start, e.event.AllDay)
+	event.RecurringEventID = e.event.ID
+	event.Recurrence = nil
+	if e.event.AllDay {
+		event.StartDate, event.EndDate = start.Format("2006-01-02"), end.Format("2006-01-02")
+		event.OriginalStartTime = event.StartDate
+	} else {
+		event.StartTime, event.EndTime = start.Format(time.RFC3339), end.Format(time.RFC3339)
+		event.OriginalStartTime = event.StartTime
+	}
+	return &memoryEvent{event: event, start: start, end: end}
+}
+
+// setEventTimes applies RFC3339 start and end times as the Google path does,
+// keeping the times it is not given
+func setEventTimes(event *types.CalendarEvent, startTime, endTime, timeZone string) error {
+	if startTime != "" {
+		t, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			return fmt.Errorf("invalid start time format: %w", err)
+		}
+		event.StartTime, event.StartTimeZone = t.Format(time.RFC3339), timeZone
+	}
+	if endTime != "" {
+		t, err := time.Parse(time.RFC3339, endTime)
+		if err != nil {
+			return fmt.Errorf("invalid end time format: %w", err)
+		}
+		event.EndTime, event.EndTimeZone = t.Format(time.RFC3339), timeZone
+	}
+	return nil
+}
+
+func eventTime(dateTime, date string, loc *time.Location) (time.Time, error) {
+	if dateTime != "" {
+		return time.Parse(time.RFC3339, dateTime)
+	}
+	if date != "" {
+		return time.ParseInLocation("2006-01-02", date, loc)
+	}
+	return time.Time{}, fmt.Errorf("no time")
+}
+
+func parseBound(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, apiError(http.StatusBadRequest, fmt.Sprintf("Invalid %s.", name))
+	}
+	return t, nil
+}
+
+// matchesQuery reports whether every term appears in the event's summary,
+// description, location, organizer or attendees
+func matchesQuery(event *types.CalendarEvent, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+
+	fields := []string{event.Summary, event.Description, event.Location, event.Organizer}
+	for _, attendee := range event.Attendees {
+		fields = append(fields, attendee.Email, attendee.DisplayName)
+	}
+	text := strings.ToLower(strings.Join(fields, "\n"))
+
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func memoryAttendees(emails []string) []*types.EventAttendee {
+	if len(emails) == 0 {
+		return nil
+	}
+	attendees := make([]*types.EventAttendee, len(emails))
+	for i, email := range emails {
+		attendees[i] = &types.EventAttendee{
+			Email:          email,
+			ResponseStatus: "needsAction",
+		}
+	}
+	return attendees
+}
+
+func copyReminders(reminders []*types.EventReminder) []*types.EventReminder {
+	if len(reminders) == 0 {
+		return nil
+	}
+	copied := make([]*types.EventReminder, len(reminders))
+	for i, reminder := range reminders {
+		r := *reminder
+		copied[i] = &r
+	}
+	return copied
+}
+
+// copyEvent deep-copies an event so that callers cannot modify the store
+func copyEvent(event *types.CalendarEvent) *types.CalendarEvent {
+	copied := *event
+	if event.Attendees != nil {
+		copied.Attendees = make([]*types.EventAttendee, len(event.Attendees))
+		for i, attendee := range event.Attendees {
+			a := *attendee
+			copied.Attendees[i] = &a
+		}
+	}
+	copied.Reminders = copyReminders(event.Reminders)
+	copied.Recurrence = append([]string(nil), event.Recurrence...)
+	return &copied
+}
+
+func removeID(ids []string, id string) []string {
+	for i, existing := range ids {
+		if existing == id {
+			return append(ids[:i:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+// newMemoryID returns an ID in the alphabet Google uses for event IDs
+func newMemoryID() string {
+	const alphabet = "0123456789abcdefghijklmnopqrstuv"
+	buf := make([]byte, 26)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	for i, b := range buf {
+		buf[i] = alphabet[b%32]
+	}
+	return string(buf)
+}
+
+func apiError(code int, message string) error {
+	return &googleapi.Error{Code: code, Message: message}
+}
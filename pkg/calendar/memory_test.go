@@ -0,0 +1,407 @@
+package calendar
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/recurrence"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// memoryFixture holds a MemoryBackend with a few events on its primary
+// calendar, which is in UTC:
+//
+//	Standup with Bob  2025-01-06 10:00-10:30, with bob@example.com
+//	Early review      2025-01-06 09:00-10:15, updated after the rest
+//	Offsite           all day 2025-01-07
+//	Gym               2025-01-06 18:00-19:00, daily for three days
+type memoryFixture struct {
+	backend                  *MemoryBackend
+	standup, review, offsite string
+	gym                      string
+}
+
+func newMemoryFixture(t *testing.T) *memoryFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	// Every change is a minute after the previous one, so that updated
+	// times are distinct
+	clock := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	b := NewMemoryBackend("")
+	b.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+
+	create := func(args *types.CreateEventArgs) string {
+		t.Helper()
+		id, err := b.CreateEvent(ctx, args)
+		if err != nil {
+			t.Fatalf("CreateEvent(%s) error = %v", args.Summary, err)
+		}
+		return id
+	}
+	f := &memoryFixture{backend: b}
+	f.standup = create(&types.CreateEventArgs{
+		Summary:     "Standup with Bob",
+		Description: "Daily sync",
+		StartTime:   "2025-01-06T10:00:00Z",
+		EndTime:     "2025-01-06T10:30:00Z",
+		Attendees:   []string{"bob@example.com"},
+	})
+	f.review = create(&types.CreateEventArgs{
+		Summary:   "Early review",
+		StartTime: "2025-01-06T09:00:00Z",
+		EndTime:   "2025-01-06T10:15:00Z",
+	})
+	f.offsite = create(&types.CreateEventArgs{
+		Summary:   "Offsite",
+		AllDay:    true,
+		StartDate: "2025-01-07",
+		EndDate:   "2025-01-08",
+	})
+	f.gym = create(&types.CreateEventArgs{
+		Summary:    "Gym",
+		StartTime:  "2025-01-06T18:00:00Z",
+		EndTime:    "2025-01-06T19:00:00Z",
+		Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=3"},
+	})
+	if err := b.UpdateEvent(ctx, &types.UpdateEventArgs{EventID: f.review, Location: "Room 1"}); err != nil {
+		t.Fatalf("UpdateEvent() error = %v", err)
+	}
+	return f
+}
+
+// listed describes events by summary and start, for comparing listings
+func listed(events []*types.CalendarEvent) []string {
+	var out []string
+	for _, event := range events {
+		start := event.StartTime
+		if event.AllDay {
+			start = event.StartDate
+		}
+		out = append(out, event.Summary+" "+start)
+	}
+	return out
+}
+
+func TestMemoryListEvents(t *testing.T) {
+	f := newMemoryFixture(t)
+
+	tests := []struct {
+		name string
+		args types.ListEventsArgs
+		want []string
+	}{
+		{
+			name: "everything in creation order",
+			want: []string{
+				"Standup with Bob 2025-01-06T10:00:00Z",
+				"Early review 2025-01-06T09:00:00Z",
+				"Offsite 2025-01-07",
+				"Gym 2025-01-06T18:00:00Z",
+			},
+		},
+		{
+			name: "overlapping the range",
+			args: types.ListEventsArgs{TimeMin: "2025-01-06T10:20:00Z", TimeMax: "2025-01-07T00:00:01Z"},
+			want: []string{
+				"Standup with Bob 2025-01-06T10:00:00Z",
+				"Offsite 2025-01-07",
+				"Gym 2025-01-06T18:00:00Z",
+			},
+		},
+		{
+			name: "ends are exclusive",
+			args: types.ListEventsArgs{TimeMin: "2025-01-08T00:00:00Z"},
+			want: []string{"Gym 2025-01-06T18:00:00Z"},
+		},
+		{
+			name: "starts are inclusive",
+			args: types.ListEventsArgs{TimeMax: "2025-01-06T09:00:00Z"},
+		},
+		{
+			name: "query matches attendees, any case",
+			args: types.ListEventsArgs{Query: "BOB standup"},
+			want: []string{"Standup with Bob 2025-01-06T10:00:00Z"},
+		},
+		{
+			name: "query matches descriptions",
+			args: types.ListEventsArgs{Query: "sync"},
+			want: []string{"Standup with Bob 2025-01-06T10:00:00Z"},
+		},
+		{
+			name: "query needs every term",
+			args: types.ListEventsArgs{Query: "standup gym"},
+		},
+		{
+			name: "ordered by start time",
+			args: types.ListEventsArgs{OrderBy: "startTime"},
+			want: []string{
+				"Early review 2025-01-06T09:00:00Z",
+				"Standup with Bob 2025-01-06T10:00:00Z",
+				"Gym 2025-01-06T18:00:00Z",
+				"Offsite 2025-01-07",
+				"Gym 2025-01-07T18:00:00Z",
+				"Gym 2025-01-08T18:00:00Z",
+			},
+		},
+		{
+			name: "ordered by update",
+			args: types.ListEventsArgs{OrderBy: "updated"},
+			want: []string{
+				"Standup with Bob 2025-01-06T10:00:00Z",
+				"Offsite 2025-01-07",
+				"Gym 2025-01-06T18:00:00Z",
+				"Early review 2025-01-06T09:00:00Z",
+			},
+		},
+		{
+			name: "single events in range",
+			args: types.ListEventsArgs{SingleEvents: true, Query: "gym", TimeMin: "2025-01-07T00:00:00Z"},
+			want: []string{
+				"Gym 2025-01-07T18:00:00Z",
+				"Gym 2025-01-08T18:00:00Z",
+			},
+		},
+		{
+			name: "limited",
+			args: types.ListEventsArgs{OrderBy: "startTime", MaxResults: 2},
+			want: []string{
+				"Early review 2025-01-06T09:00:00Z",
+				"Standup with Bob 2025-01-06T10:00:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, _, err := f.backend.ListEvents(context.Background(), &tt.args)
+			if err != nil {
+				t.Fatalf("ListEvents() error = %v", err)
+			}
+			if got := listed(events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListEvents() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryAllDayTimeZone(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBackend("")
+	calendarID, err := b.CreateCalendar(ctx, &types.CreateCalendarArgs{Summary: "Team", TimeZone: "America/New_York"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.CreateEvent(ctx, &types.CreateEventArgs{
+		CalendarID: calendarID,
+		Summary:    "Holiday",
+		AllDay:     true,
+		StartDate:  "2025-01-07",
+		EndDate:    "2025-01-08",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The day runs from 05:00 UTC to 05:00 UTC the next day
+	tests := []struct {
+		timeMin, timeMax string
+		want             int
+	}{
+		{"2025-01-07T02:00:00Z", "2025-01-07T03:00:00Z", 0},
+		{"2025-01-07T04:00:00Z", "2025-01-07T05:00:01Z", 1},
+		{"2025-01-08T04:00:00Z", "2025-01-08T05:00:00Z", 1},
+		{"2025-01-08T05:00:00Z", "2025-01-08T06:00:00Z", 0},
+	}
+	for _, tt := range tests {
+		events, _, err := b.ListEvents(ctx, &types.ListEventsArgs{CalendarID: calendarID, TimeMin: tt.timeMin, TimeMax: tt.timeMax})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != tt.want {
+			t.Errorf("ListEvents(%s to %s) = %d events, want %d", tt.timeMin, tt.timeMax, len(events), tt.want)
+		}
+	}
+
+	response, err := b.GetFreeBusy(ctx, &types.FreeBusyArgs{
+		TimeMin:     "2025-01-07T00:00:00Z",
+		TimeMax:     "2025-01-09T00:00:00Z",
+		CalendarIDs: []string{calendarID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*types.TimePeriod{{Start: "2025-01-07T05:00:00Z", End: "2025-01-08T05:00:00Z"}}
+	if got := response.Calendars[calendarID].Busy; !reflect.DeepEqual(got, want) {
+		t.Errorf("busy = %v, want %v", got, want)
+	}
+}
+
+func TestMemoryCreateEventErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args types.CreateEventArgs
+	}{
+		{"missing end", types.CreateEventArgs{Summary: "x", StartTime: "2025-01-06T10:00:00Z"}},
+		{"end before start", types.CreateEventArgs{Summary: "x", StartTime: "2025-01-06T10:00:00Z", EndTime: "2025-01-06T09:00:00Z"}},
+		{"empty day", types.CreateEventArgs{Summary: "x", AllDay: true, StartDate: "2025-01-07", EndDate: "2025-01-07"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewMemoryBackend("")
+			if _, err := b.CreateEvent(context.Background(), &tt.args); err == nil {
+				t.Error("CreateEvent() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestMemoryFreeBusy(t *testing.T) {
+	f := newMemoryFixture(t)
+
+	response, err := f.backend.GetFreeBusy(context.Background(), &types.FreeBusyArgs{
+		TimeMin:     "2025-01-06T09:30:00Z",
+		TimeMax:     "2025-01-08T00:00:00Z",
+		CalendarIDs: []string{"primary", "nobody@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("GetFreeBusy() error = %v", err)
+	}
+
+	// Overlapping events merge, and periods are clipped to the query
+	want := map[string]*types.FreeBusyCalendar{
+		"primary": {Busy: []*types.TimePeriod{
+			{Start: "2025-01-06T09:30:00Z", End: "2025-01-06T10:30:00Z"},
+			{Start: "2025-01-06T18:00:00Z", End: "2025-01-06T19:00:00Z"},
+			{Start: "2025-01-07T00:00:00Z", End: "2025-01-08T00:00:00Z"},
+		}},
+		"nobody@example.com": {Busy: []*types.TimePeriod{}, Errors: []string{"notFound"}},
+	}
+	if !reflect.DeepEqual(response.Calendars, want) {
+		for id, cal := range response.Calendars {
+			t.Logf("%s: busy %v, errors %v", id, cal.Busy, cal.Errors)
+		}
+		t.Errorf("GetFreeBusy() calendars differ from %v", want)
+	}
+}
+
+func TestMemoryInstances(t *testing.T) {
+	f := newMemoryFixture(t)
+	ctx := context.Background()
+
+	// Cancelling an instance leaves the others
+	cancelled := recurrence.InstanceID(f.gym, time.Date(2025, time.January, 7, 18, 0, 0, 0, time.UTC), false)
+	if err := f.backend.DeleteEvent(ctx, "primary", cancelled); err != nil {
+		t.Fatalf("DeleteEvent(%s) error = %v", cancelled, err)
+	}
+
+	tests := []struct {
+		name string
+		args types.ListInstancesArgs
+		want []string
+	}{
+		{
+			name: "every instance",
+			args: types.ListInstancesArgs{EventID: f.gym},
+			want: []string{"Gym 2025-01-06T18:00:00Z", "Gym 2025-01-08T18:00:00Z"},
+		},
+		{
+			name: "in range",
+			args: types.ListInstancesArgs{EventID: f.gym, TimeMin: "2025-01-07T00:00:00Z"},
+			want: []string{"Gym 2025-01-08T18:00:00Z"},
+		},
+		{
+			name: "limited",
+			args: types.ListInstancesArgs{EventID: f.gym, MaxResults: 1},
+			want: []string{"Gym 2025-01-06T18:00:00Z"},
+		},
+		{
+			name: "single event",
+			args: types.ListInstancesArgs{EventID: f.standup},
+			want: []string{"Standup with Bob 2025-01-06T10:00:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances, _, err := f.backend.ListInstances(ctx, &tt.args)
+			if err != nil {
+				t.Fatalf("ListInstances() error = %v", err)
+			}
+			if got := listed(instances); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListInstances() = %q, want %q", got, tt.want)
+			}
+			for _, instance := range instances {
+				if tt.args.EventID == f.gym && instance.RecurringEventID != f.gym {
+					t.Errorf("instance %s has recurringEventId %q, want %q", instance.ID, instance.RecurringEventID, f.gym)
+				}
+			}
+		})
+	}
+
+	events, _, err := f.backend.ListEvents(ctx, &types.ListEventsArgs{SingleEvents: true, Query: "gym"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := listed(events), []string{"Gym 2025-01-06T18:00:00Z", "Gym 2025-01-08T18:00:00Z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListEvents(singleEvents) = %q, want %q", got, want)
+	}
+}
+
+func TestMemoryNotFound(t *testing.T) {
+	f := newMemoryFixture(t)
+	ctx := context.Background()
+	b := f.backend
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"get event", func() error {
+			_, err := b.GetEvent(ctx, "primary", "missing")
+			return err
+		}},
+		{"get event of unknown calendar", func() error {
+			_, err := b.GetEvent(ctx, "nobody@example.com", f.standup)
+			return err
+		}},
+		{"update event", func() error {
+			return b.UpdateEvent(ctx, &types.UpdateEventArgs{EventID: "missing", Summary: "x"})
+		}},
+		{"delete event", func() error {
+			return b.DeleteEvent(ctx, "primary", "missing")
+		}},
+		{"list events of unknown calendar", func() error {
+			_, _, err := b.ListEvents(ctx, &types.ListEventsArgs{CalendarID: "nobody@example.com"})
+			return err
+		}},
+		{"list instances", func() error {
+			_, _, err := b.ListInstances(ctx, &types.ListInstancesArgs{EventID: "missing"})
+			return err
+		}},
+		{"get calendar", func() error {
+			_, err := b.GetCalendar(ctx, "nobody@example.com")
+			return err
+		}},
+		{"delete calendar", func() error {
+			return b.DeleteCalendar(ctx, "nobody@example.com")
+		}},
+		{"deleted event", func() error {
+			if err := b.DeleteEvent(ctx, "primary", f.offsite); err != nil {
+				return err
+			}
+			_, err := b.GetEvent(ctx, "primary", f.offsite)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !isNotFound(err) {
+				t.Errorf("error = %v, want a 404 googleapi.Error", err)
+			}
+		})
+	}
+}
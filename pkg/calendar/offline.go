@@ -0,0 +1,223 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// Staleness is implemented by backends that answer from copies synced
+// earlier instead of from Google, so results can say how old they are
+type Staleness interface {
+	// EventsSyncedAt returns when the events of a calendar were last synced
+	EventsSyncedAt(calendarID string) time.Time
+	// CalendarsSyncedAt returns when the calendar list was last synced
+	CalendarsSyncedAt() time.Time
+}
+
+// ErrOffline is returned by an OfflineBackend for anything it cannot answer
+// from its store, including every write
+var ErrOffline = errors.New("not available offline")
+
+// OfflineBackend is a read-only Backend over the calendars a CachedBackend
+// saved to a DiskStore, for when Google cannot be reached. It answers as of
+// the last sync of each calendar; calendars that were never synced, and
+// every write, fail with ErrOffline.
+type OfflineBackend struct {
+	store             *MemoryBackend
+	synced            map[string]time.Time // by calendar ID
+	calendarsSyncedAt time.Time
+}
+
+// NewOfflineBackend loads the calendars saved in disk
+func NewOfflineBackend(disk *DiskStore) (*OfflineBackend, error) {
+	list, err := disk.loadCalendarList()
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := disk.loadCalendars()
+	if err != nil {
+		return nil, err
+	}
+	if list == nil && len(snapshots) == 0 {
+		return nil, fmt.Errorf("no synced calendars in %s, run online with -cache-ttl and -store first", disk.Dir())
+	}
+
+	b := &OfflineBackend{
+		store: &MemoryBackend{
+			calendars: make(map[string]*memoryCalendar),
+			now:       time.Now,
+		},
+		synced: make(map[string]time.Time),
+	}
+
+	// The primary calendar may be saved under its alias, its ID or both;
+	// Google resolves the alias to the ID, so the snapshot knows it
+	if primary := snapshots["primary"]; primary != nil && primary.Calendar != nil {
+		delete(snapshots, "primary")
+		if other := snapshots[primary.Calendar.ID]; other == nil || other.SyncedAt.Before(primary.SyncedAt) {
+			snapshots[primary.Calendar.ID] = primary
+		}
+		b.store.owner = primary.Calendar.ID
+	}
+
+	if list != nil {
+		b.calendarsSyncedAt = list.SyncedAt
+		for _, cal := range list.Calendars {
+			cal := *cal
+			if cal.Primary {
+				b.store.owner = cal.ID
+			}
+			b.store.addCalendar(&cal)
+		}
+	}
+	ids := make([]string, 0, len(snapshots))
+	for id := range snapshots {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		snapshot := snapshots[id]
+		if snapshot.Calendar == nil {
+			continue
+		}
+		if _, ok := b.store.calendars[id]; !ok {
+			cal := *snapshot.Calendar
+			cal.Primary = id == b.store.owner
+			b.store.addCalendar(&cal)
+		}
+		// All-day events are read in the calendar's time zone as synced
+		b.store.calendars[id].calendar.TimeZone = snapshot.Calendar.TimeZone
+		if _, err := b.store.putEvents(id, snapshot.Events); err != nil {
+			return nil, fmt.Errorf("failed to load %s from store: %w", id, err)
+		}
+		b.synced[id] = snapshot.SyncedAt
+
+		// Without a saved list, the calendars are as old as their events
+		if list == nil && (b.calendarsSyncedAt.IsZero() || snapshot.SyncedAt.Before(b.calendarsSyncedAt)) {
+			b.calendarsSyncedAt = snapshot.SyncedAt
+		}
+	}
+	return b, nil
+}
+
+// EventsSyncedAt returns when the events of a calendar were saved, or the
+// zero time if they never were
+func (b *OfflineBackend) EventsSyncedAt(calendarID string) time.Time {
+	return b.synced[b.resolve(calendarID)]
+}
+
+// CalendarsSyncedAt returns when the calendar list was saved, or the zero
+// time if it never was
+func (b *OfflineBackend) CalendarsSyncedAt() time.Time {
+	return b.calendarsSyncedAt
+}
+
+// GetEvent retrieves a saved event
+func (b *OfflineBackend) GetEvent(ctx context.Context, calendarID, eventID string) (*types.CalendarEvent, error) {
+	if err := b.check(calendarID); err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return b.store.GetEvent(ctx, calendarID, eventID)
+}
+
+// ListEvents lists the saved events of a calendar
+func (b *OfflineBackend) ListEvents(ctx context.Context, args *types.ListEventsArgs) ([]*types.CalendarEvent, string, error) {
+	if err := b.check(args.CalendarID); err != nil {
+		return nil, "", fmt.Errorf("failed to list events: %w", err)
+	}
+	return b.store.ListEvents(ctx, args)
+}
+
+// ListInstances lists the instances of a saved recurring event
+func (b *OfflineBackend) ListInstances(ctx context.Context, args *types.ListInstancesArgs) ([]*types.CalendarEvent, string, error) {
+	if err := b.check(args.CalendarID); err != nil {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+	return b.store.ListInstances(ctx, args)
+}
+
+// ListCalendars lists the saved calendars
+func (b *OfflineBackend) ListCalendars(ctx context.Context, args *types.ListCalendarsArgs) ([]*types.Calendar, string, error) {
+	return b.store.ListCalendars(ctx, args)
+}
+
+// GetCalendar retrieves a saved calendar
+func (b *OfflineBackend) GetCalendar(ctx context.Context, calendarID string) (*types.Calendar, error) {
+	return b.store.GetCalendar(ctx, calendarID)
+}
+
+// GetFreeBusy works out free/busy information from the saved events.
+// Calendars that were never synced are reported with a notAvailableOffline
+// error, the way Google reports calendars it cannot query.
+func (b *OfflineBackend) GetFreeBusy(ctx context.Context, args *types.FreeBusyArgs) (*types.FreeBusyResponse, error) {
+	var synced, missing []string
+	for _, calendarID := range args.CalendarIDs {
+		if b.check(calendarID) != nil {
+			missing = append(missing, calendarID)
+		} else {
+			synced = append(synced, calendarID)
+		}
+	}
+
+	query := *args
+	query.CalendarIDs = synced
+	response, err := b.store.GetFreeBusy(ctx, &query)
+	if err != nil {
+		return nil, err
+	}
+	for _, calendarID := range missing {
+		response.Calendars[calendarID] = &types.FreeBusyCalendar{Busy: []*types.TimePeriod{}, Errors: []string{"notAvailableOffline"}}
+	}
+	return response, nil
+}
+
+// CreateEvent fails with ErrOffline
+func (b *OfflineBackend) CreateEvent(ctx context.Context, args *types.CreateEventArgs) (string, error) {
+	return "", fmt.Errorf("failed to create event: %w", ErrOffline)
+}
+
+// UpdateEvent fails with ErrOffline
+func (b *OfflineBackend) UpdateEvent(ctx context.Context, args *types.UpdateEventArgs) error {
+	return fmt.Errorf("failed to update event: %w", ErrOffline)
+}
+
+// DeleteEvent fails with ErrOffline
+func (b *OfflineBackend) DeleteEvent(ctx context.Context, calendarID, eventID string) error {
+	return fmt.Errorf("failed to delete event: %w", ErrOffline)
+}
+
+// CreateCalendar fails with ErrOffline
+func (b *OfflineBackend) CreateCalendar(ctx context.Context, args *types.CreateCalendarArgs) (string, error) {
+	return "", fmt.Errorf("failed to create calendar: %w", ErrOffline)
+}
+
+// DeleteCalendar fails with ErrOffline
+func (b *OfflineBackend) DeleteCalendar(ctx context.Context, calendarID string) error {
+	return fmt.Errorf("failed to delete calendar: %w", ErrOffline)
+}
+
+// check reports ErrOffline for calendars whose events were never saved
+func (b *OfflineBackend) check(calendarID string) error {
+	if _, ok := b.synced[b.resolve(calendarID)]; !ok {
+		return fmt.Errorf("calendar %s was never synced: %w", cacheKey(calendarID), ErrOffline)
+	}
+	return nil
+}
+
+// resolve turns the "primary" alias into the primary calendar's ID
+func (b *OfflineBackend) resolve(calendarID string) string {
+	if calendarID == "" || calendarID == "primary" {
+		return b.store.owner
+	}
+	return calendarID
+}
+
+var (
+	_ Backend   = (*OfflineBackend)(nil)
+	_ Staleness = (*OfflineBackend)(nil)
+)
@@ -0,0 +1,166 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// newOfflineBackend syncs the primary calendar and the calendar list of a
+// fake API to a store, as a run with -store would, and opens the store
+// offline. The calendar list also names a Team calendar whose events were
+// never synced.
+func newOfflineBackend(t *testing.T) (*OfflineBackend, time.Time) {
+	t.Helper()
+	disk, err := NewDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newCacheFixture(t, disk)
+	ctx := context.Background()
+
+	if _, err := f.cache.CreateCalendar(ctx, &types.CreateCalendarArgs{Summary: "Team"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.cache.CreateEvent(ctx, &types.CreateEventArgs{
+		Summary:   "Planning",
+		StartTime: "2025-01-06T10:00:00Z",
+		EndTime:   "2025-01-06T11:00:00Z",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.summaries(t, "primary"); !reflect.DeepEqual(got, []string{"Planning"}) {
+		t.Fatalf("ListEvents() = %q, want [Planning]", got)
+	}
+	synced := f.now
+	f.now = f.now.Add(time.Hour)
+	if _, _, err := f.cache.ListCalendars(ctx, &types.ListCalendarsArgs{}); err != nil {
+		t.Fatal(err)
+	}
+
+	offline, err := NewOfflineBackend(disk)
+	if err != nil {
+		t.Fatalf("NewOfflineBackend() error = %v", err)
+	}
+	return offline, synced
+}
+
+func TestOfflineBackendReads(t *testing.T) {
+	b, synced := newOfflineBackend(t)
+	ctx := context.Background()
+
+	for _, calendarID := range []string{"primary", "me@example.com"} {
+		events, _, err := b.ListEvents(ctx, &types.ListEventsArgs{CalendarID: calendarID})
+		if err != nil {
+			t.Fatalf("ListEvents(%s) error = %v", calendarID, err)
+		}
+		if len(events) != 1 || events[0].Summary != "Planning" {
+			t.Fatalf("ListEvents(%s) = %+v, want Planning", calendarID, events)
+		}
+		if event, err := b.GetEvent(ctx, calendarID, events[0].ID); err != nil || event.Summary != "Planning" {
+			t.Errorf("GetEvent(%s) = %+v, %v; want Planning", calendarID, event, err)
+		}
+		if got := b.EventsSyncedAt(calendarID); !got.Equal(synced) {
+			t.Errorf("EventsSyncedAt(%s) = %v, want %v", calendarID, got, synced)
+		}
+	}
+	if got, want := b.CalendarsSyncedAt(), synced.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("CalendarsSyncedAt() = %v, want %v", got, want)
+	}
+
+	calendars, _, err := b.ListCalendars(ctx, &types.ListCalendarsArgs{})
+	if err != nil {
+		t.Fatalf("ListCalendars() error = %v", err)
+	}
+	var summaries []string
+	for _, cal := range calendars {
+		summaries = append(summaries, cal.Summary)
+	}
+	if len(summaries) != 2 || summaries[1] != "Team" {
+		t.Errorf("ListCalendars() = %q, want the primary calendar and Team", summaries)
+	}
+	team := calendars[1].ID
+	if cal, err := b.GetCalendar(ctx, team); err != nil || cal.Summary != "Team" {
+		t.Errorf("GetCalendar(%s) = %+v, %v; want Team", team, cal, err)
+	}
+	if got := b.EventsSyncedAt(team); !got.IsZero() {
+		t.Errorf("EventsSyncedAt() of a calendar never synced = %v, want zero", got)
+	}
+
+	freeBusy, err := b.GetFreeBusy(ctx, &types.FreeBusyArgs{
+		TimeMin:     "2025-01-06T00:00:00Z",
+		TimeMax:     "2025-01-07T00:00:00Z",
+		CalendarIDs: []string{"primary", team},
+	})
+	if err != nil {
+		t.Fatalf("GetFreeBusy() error = %v", err)
+	}
+	if busy := freeBusy.Calendars["primary"]; busy == nil || len(busy.Busy) != 1 || busy.Busy[0].Start != "2025-01-06T10:00:00Z" {
+		t.Errorf("GetFreeBusy() primary = %+v, want busy 10:00-11:00", busy)
+	}
+	if got := freeBusy.Calendars[team]; got == nil || !reflect.DeepEqual(got.Errors, []string{"notAvailableOffline"}) {
+		t.Errorf("GetFreeBusy() of a calendar never synced = %+v, want notAvailableOffline", got)
+	}
+}
+
+func TestOfflineBackendErrors(t *testing.T) {
+	b, _ := newOfflineBackend(t)
+	ctx := context.Background()
+	calendars, _, err := b.ListCalendars(ctx, &types.ListCalendarsArgs{})
+	if err != nil || len(calendars) != 2 {
+		t.Fatalf("ListCalendars() = %+v, %v; want two calendars", calendars, err)
+	}
+	team := calendars[1].ID
+
+	calls := map[string]func() error{
+		"list events of a calendar never synced": func() error {
+			_, _, err := b.ListEvents(ctx, &types.ListEventsArgs{CalendarID: team})
+			return err
+		},
+		"get event of an unknown calendar": func() error {
+			_, err := b.GetEvent(ctx, "nobody@example.com", "event")
+			return err
+		},
+		"list instances of a calendar never synced": func() error {
+			_, _, err := b.ListInstances(ctx, &types.ListInstancesArgs{CalendarID: team, EventID: "event"})
+			return err
+		},
+		"create event": func() error {
+			_, err := b.CreateEvent(ctx, &types.CreateEventArgs{Summary: "Offline"})
+			return err
+		},
+		"update event": func() error {
+			return b.UpdateEvent(ctx, &types.UpdateEventArgs{EventID: "event"})
+		},
+		"delete event": func() error {
+			return b.DeleteEvent(ctx, "primary", "event")
+		},
+		"create calendar": func() error {
+			_, err := b.CreateCalendar(ctx, &types.CreateCalendarArgs{Summary: "Offline"})
+			return err
+		},
+		"delete calendar": func() error {
+			return b.DeleteCalendar(ctx, team)
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrOffline) {
+			t.Errorf("%s: error = %v, want ErrOffline", name, err)
+		}
+	}
+}
+
+func TestNewOfflineBackendEmptyStore(t *testing.T) {
+	disk, err := NewDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewOfflineBackend(disk); err == nil || !strings.Contains(err.Error(), "no synced calendars") {
+		t.Errorf("NewOfflineBackend() of an empty store error = %v", err)
+	}
+}
@@ -0,0 +1,642 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/recurrence"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// CreateEvent creates a new calendar event
+func (c *Client) CreateEvent(ctx context.Context, args *types.CreateEventArgs) (string, error) {
+	event := &calendar.Event{
+		Summary:     args.Summary,
+		Description: args.Description,
+		Location:    args.Location,
+	}
+
+	// Set start time
+	if args.StartTime != "" {
+		startTime, err := time.Parse(time.RFC3339, args.StartTime)
+		if err != nil {
+			return "", fmt.Errorf("invalid start time format: %w", err)
+		}
+		event.Start = &calendar.EventDateTime{
+			DateTime: startTime.Format(time.RFC3339),
+			TimeZone: args.TimeZone,
+		}
+	}
+
+	// Set end time
+	if args.EndTime != "" {
+		endTime, err := time.Parse(time.RFC3339, args.EndTime)
+		if err != nil {
+			return "", fmt.Errorf("invalid end time format: %w", err)
+		}
+		event.End = &calendar.EventDateTime{
+			DateTime: endTime.Format(time.RFC3339),
+			TimeZone: args.TimeZone,
+		}
+	}
+
+	// Handle all-day events
+	if args.AllDay {
+		if args.StartDate != "" {
+			event.Start = &calendar.EventDateTime{
+				Date: args.StartDate,
+			}
+		}
+		if args.EndDate != "" {
+			event.End = &calendar.EventDateTime{
+				Date: args.EndDate,
+			}
+		}
+	}
+
+	// Add attendees
+	if len(args.Attendees) > 0 {
+		attendees := make([]*calendar.EventAttendee, len(args.Attendees))
+		for i, email := range args.Attendees {
+			attendees[i] = &calendar.EventAttendee{
+				Email: email,
+			}
+		}
+		event.Attendees = attendees
+	}
+
+	// Add reminders
+	if len(args.Reminders) > 0 {
+		reminders := make([]*calendar.EventReminder, len(args.Reminders))
+		for i, reminder := range args.Reminders {
+			reminders[i] = &calendar.EventReminder{
+				Method:  reminder.Method,
+				Minutes: int64(reminder.Minutes),
+			}
+		}
+		event.Reminders = &calendar.EventReminders{
+			UseDefault: false,
+			Overrides:  reminders,
+		}
+	}
+
+	// Add recurrence, checked here so that bad rules fail with a useful
+	// message instead of the API's generic one
+	lines, err := recurrence.Lines(args.Recurrence, args.RecurrenceRule, args.AllDay)
+	if err != nil {
+		return "", fmt.Errorf("invalid recurrence: %w", err)
+	}
+	if len(lines) > 0 {
+		event.Recurrence = lines
+		setRecurringTimeZone(event)
+	}
+
+	calendarID := args.CalendarID
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	result, err := c.service.Events.Insert(calendarID, event).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create event: %w", err)
+	}
+
+	return result.Id, nil
+}
+
+// GetEvent retrieves a calendar event by ID
+func (c *Client) GetEvent(ctx context.Context, calendarID, eventID string) (*types.CalendarEvent, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	event, err := c.service.Events.Get(calendarID, eventID).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return c.convertToCalendarEvent(event), nil
+}
+
+// UpdateEvent updates an existing calendar event
+func (c *Client) UpdateEvent(ctx context.Context, args *types.UpdateEventArgs) error {
+	calendarID := args.CalendarID
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	var lines []string
+	if len(args.Recurrence) > 0 || args.RecurrenceRule != nil {
+		var err error
+		if lines, err = recurrence.Lines(args.Recurrence, args.RecurrenceRule, false); err != nil {
+			return fmt.Errorf("invalid recurrence: %w", err)
+		}
+	}
+
+	// Get existing event
+	event, err := c.service.Events.Get(calendarID, args.EventID).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get existing event: %w", err)
+	}
+
+	// Update fields
+	if args.Summary != "" {
+		event.Summary = args.Summary
+	}
+	if args.Description != "" {
+		event.Description = args.Description
+	}
+	if args.Location != "" {
+		event.Location = args.Location
+	}
+
+	// A recurring event keeps the time zone its rule is expanded in
+	timeZone := args.TimeZone
+	if timeZone == "" && len(event.Recurrence) > 0 && event.Start != nil {
+		timeZone = event.Start.TimeZone
+	}
+
+	// Update start time
+	if args.StartTime != "" {
+		startTime, err := time.Parse(time.RFC3339, args.StartTime)
+		if err != nil {
+			return fmt.Errorf("invalid start time format: %w", err)
+		}
+		event.Start = &calendar.EventDateTime{
+			DateTime: startTime.Format(time.RFC3339),
+			TimeZone: timeZone,
+		}
+	}
+
+	// Update end time
+	if args.EndTime != "" {
+		endTime, err := time.Parse(time.RFC3339, args.EndTime)
+		if err != nil {
+			return fmt.Errorf("invalid end time format: %w", err)
+		}
+		event.End = &calendar.EventDateTime{
+			DateTime: endTime.Format(time.RFC3339),
+			TimeZone: timeZone,
+		}
+	}
+
+	// Replace attendees
+	if len(args.Attendees) > 0 {
+		attendees := make([]*calendar.EventAttendee, len(args.Attendees))
+		for i, email := range args.Attendees {
+			attendees[i] = &calendar.EventAttendee{
+				Email: email,
+			}
+		}
+		event.Attendees = attendees
+	}
+
+	// Replace reminders
+	if len(args.Reminders) > 0 {
+		reminders := make([]*calendar.EventReminder, len(args.Reminders))
+		for i, reminder := range args.Reminders {
+			reminders[i] = &calendar.EventReminder{
+				Method:  reminder.Method,
+				Minutes: int64(reminder.Minutes),
+			}
+		}
+		event.Reminders = &calendar.EventReminders{
+			UseDefault: false,
+			Overrides:  reminders,
+		}
+	}
+
+	// Replace recurrence
+	if len(lines) > 0 {
+		if args.RecurrenceRule != nil && event.Start != nil && event.Start.Date != "" {
+			// Rebuild the rule now that the event is known to be all-day
+			if lines, err = recurrence.Lines(args.Recurrence, args.RecurrenceRule, true); err != nil {
+				return fmt.Errorf("invalid recurrence: %w", err)
+			}
+		}
+		event.Recurrence = lines
+		setRecurringTimeZone(event)
+	}
+
+	_, err = c.service.Events.Update(calendarID, args.EventID, event).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEvent deletes a calendar event
+func (c *Client) DeleteEvent(ctx context.Context, calendarID, eventID string) error {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	err := c.service.Events.Delete(calendarID, eventID).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+
+	return nil
+}
+
+// ListEvents lists calendar events, following pages until MaxResults events
+// are collected. When more remain, it also returns a cursor that continues
+// the listing through args.Cursor.
+func (c *Client) ListEvents(ctx context.Context, args *types.ListEventsArgs) ([]*types.CalendarEvent, string, error) {
+	calendarID := args.CalendarID
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	start, err := parseCursor(args.Cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list events: %w", err)
+	}
+	p := newPager(args.MaxResults, start, maxEventPageSize)
+
+	call := c.service.Events.List(calendarID)
+
+	// Set time range
+	if args.TimeMin != "" {
+		call = call.TimeMin(args.TimeMin)
+	}
+	if args.TimeMax != "" {
+		call = call.TimeMax(args.TimeMax)
+	}
+
+	// Set the page to start from and its size
+	if p.at.PageToken != "" {
+		call = call.PageToken(p.at.PageToken)
+	}
+	if p.at.PageSize > 0 {
+		call = call.MaxResults(int64(p.at.PageSize))
+	}
+
+	// Set query
+	if args.Query != "" {
+		call = call.Q(args.Query)
+	}
+
+	// Set order. Google only orders by start time when recurring events
+	// are expanded into their instances.
+	if args.OrderBy != "" {
+		call = call.OrderBy(args.OrderBy)
+	}
+	if args.OrderBy == "startTime" || args.SingleEvents {
+		call = call.SingleEvents(true)
+	}
+
+	// Google may return short pages, so keep following them until
+	// MaxResults events have been collected
+	var events []*types.CalendarEvent
+	pages := 0
+	err = call.Pages(ctx, func(response *calendar.Events) error {
+		pages++
+		done := p.page(len(response.Items), response.NextPageToken, func(i int) {
+			events = append(events, c.convertToCalendarEvent(response.Items[i]))
+		})
+		reportProgress(ctx, float64(pages), 0, fmt.Sprintf("Fetched %d events (page %d)", len(events), pages))
+
+		if done {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil && err != errStopPaging {
+		return nil, "", fmt.Errorf("failed to list events: %w", err)
+	}
+
+	return events, p.next, nil
+}
+
+// ListInstances lists the instances of a recurring event
+func (c *Client) ListInstances(ctx context.Context, args *types.ListInstancesArgs) ([]*types.CalendarEvent, string, error) {
+	calendarID := args.CalendarID
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	start, err := parseCursor(args.Cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+	p := newPager(args.MaxResults, start, maxEventPageSize)
+
+	call := c.service.Events.Instances(calendarID, args.EventID)
+	if args.TimeMin != "" {
+		call = call.TimeMin(args.TimeMin)
+	}
+	if args.TimeMax != "" {
+		call = call.TimeMax(args.TimeMax)
+	}
+	if p.at.PageToken != "" {
+		call = call.PageToken(p.at.PageToken)
+	}
+	if p.at.PageSize > 0 {
+		call = call.MaxResults(int64(p.at.PageSize))
+	}
+
+	var instances []*types.CalendarEvent
+	pages := 0
+	err = call.Pages(ctx, func(response *calendar.Events) error {
+		pages++
+		done := p.page(len(response.Items), response.NextPageToken, func(i int) {
+			instances = append(instances, c.convertToCalendarEvent(response.Items[i]))
+		})
+		reportProgress(ctx, float64(pages), 0, fmt.Sprintf("Fetched %d instances (page %d)", len(instances), pages))
+
+		if done {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil && err != errStopPaging {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	return instances, p.next, nil
+}
+
+// SyncEvents lists the events of a calendar that changed since the listing
+// syncToken came from, or all of them when syncToken is empty, along with
+// the token for the next sync. Changes include cancelled events and
+// instances, and modified instances of recurring events are listed next to
+// their series. It fails with ErrFullSyncRequired once Google no longer
+// accepts syncToken.
+func (c *Client) SyncEvents(ctx context.Context, calendarID, syncToken string) ([]*types.CalendarEvent, string, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	call := c.service.Events.List(calendarID).MaxResults(maxEventPageSize)
+	if syncToken != "" {
+		call = call.SyncToken(syncToken)
+	}
+
+	var events []*types.CalendarEvent
+	nextSyncToken := ""
+	pages := 0
+	err := call.Pages(ctx, func(response *calendar.Events) error {
+		pages++
+		for _, event := range response.Items {
+			events = append(events, c.convertToCalendarEvent(event))
+		}
+		nextSyncToken = response.NextSyncToken
+		reportProgress(ctx, float64(pages), 0, fmt.Sprintf("Synced %d events (page %d)", len(events), pages))
+		return nil
+	})
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusGone {
+			return nil, "", fmt.Errorf("failed to sync events: %w", ErrFullSyncRequired)
+		}
+		return nil, "", fmt.Errorf("failed to sync events: %w", err)
+	}
+
+	return events, nextSyncToken, nil
+}
+
+// ListCalendars lists available calendars, following pages until
+// MaxResults calendars are collected. When more remain, it also returns a
+// cursor that continues the listing through args.Cursor.
+func (c *Client) ListCalendars(ctx context.Context, args *types.ListCalendarsArgs) ([]*types.Calendar, string, error) {
+	start, err := parseCursor(args.Cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list calendars: %w", err)
+	}
+	p := newPager(args.MaxResults, start, maxCalendarPageSize)
+
+	call := c.service.CalendarList.List()
+	if p.at.PageToken != "" {
+		call = call.PageToken(p.at.PageToken)
+	}
+	if p.at.PageSize > 0 {
+		call = call.MaxResults(int64(p.at.PageSize))
+	}
+
+	var calendars []*types.Calendar
+	err = call.Pages(ctx, func(response *calendar.CalendarList) error {
+		done := p.page(len(response.Items), response.NextPageToken, func(i int) {
+			cal := response.Items[i]
+			calendars = append(calendars, &types.Calendar{
+				ID:          cal.Id,
+				Summary:     cal.Summary,
+				Description: cal.Description,
+				Primary:     cal.Primary,
+				AccessRole:  cal.AccessRole,
+				TimeZone:    cal.TimeZone,
+			})
+		})
+		if done {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil && err != errStopPaging {
+		return nil, "", fmt.Errorf("failed to list calendars: %w", err)
+	}
+
+	return calendars, p.next, nil
+}
+
+// GetCalendar retrieves a specific calendar
+func (c *Client) GetCalendar(ctx context.Context, calendarID string) (*types.Calendar, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	cal, err := c.service.Calendars.Get(calendarID).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar: %w", err)
+	}
+
+	return &types.Calendar{
+		ID:          cal.Id,
+		Summary:     cal.Summary,
+		Description: cal.Description,
+		TimeZone:    cal.TimeZone,
+	}, nil
+}
+
+// CreateCalendar creates a new calendar
+func (c *Client) CreateCalendar(ctx context.Context, args *types.CreateCalendarArgs) (string, error) {
+	cal := &calendar.Calendar{
+		Summary:     args.Summary,
+		Description: args.Description,
+		TimeZone:    args.TimeZone,
+	}
+
+	result, err := c.service.Calendars.Insert(cal).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create calendar: %w", err)
+	}
+
+	return result.Id, nil
+}
+
+// DeleteCalendar deletes a calendar
+func (c *Client) DeleteCalendar(ctx context.Context, calendarID string) error {
+	err := c.service.Calendars.Delete(calendarID).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar: %w", err)
+	}
+
+	return nil
+}
+
+// freeBusyBatchSize is the most calendars Google accepts in one free/busy
+// query
+const freeBusyBatchSize = 50
+
+// GetFreeBusy gets free/busy information
+func (c *Client) GetFreeBusy(ctx context.Context, args *types.FreeBusyArgs) (*types.FreeBusyResponse, error) {
+	result := &types.FreeBusyResponse{
+		TimeMin:   args.TimeMin,
+		TimeMax:   args.TimeMax,
+		Calendars: make(map[string]*types.FreeBusyCalendar),
+	}
+
+	total := (len(args.CalendarIDs) + freeBusyBatchSize - 1) / freeBusyBatchSize
+	for batch := 0; batch < total; batch++ {
+		ids := args.CalendarIDs[batch*freeBusyBatchSize:]
+		if len(ids) > freeBusyBatchSize {
+			ids = ids[:freeBusyBatchSize]
+		}
+
+		items := make([]*calendar.FreeBusyRequestItem, len(ids))
+		for i, calID := range ids {
+			items[i] = &calendar.FreeBusyRequestItem{
+				Id: calID,
+			}
+		}
+
+		request := &calendar.FreeBusyRequest{
+			TimeMin: args.TimeMin,
+			TimeMax: args.TimeMax,
+			Items:   items,
+		}
+
+		response, err := c.service.Freebusy.Query(request).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get free/busy: %w", err)
+		}
+
+		result.TimeMin = response.TimeMin
+		result.TimeMax = response.TimeMax
+		for calID, cal := range response.Calendars {
+			busy := make([]*types.TimePeriod, len(cal.Busy))
+			for i, period := range cal.Busy {
+				busy[i] = &types.TimePeriod{
+					Start: period.Start,
+					End:   period.End,
+				}
+			}
+
+			result.Calendars[calID] = &types.FreeBusyCalendar{
+				Busy: busy,
+			}
+			for _, calErr := range cal.Errors {
+				result.Calendars[calID].Errors = append(result.Calendars[calID].Errors, calErr.Reason)
+			}
+		}
+
+		reportProgress(ctx, float64(batch+1), float64(total), fmt.Sprintf("Queried %d of %d calendars", batch*freeBusyBatchSize+len(ids), len(args.CalendarIDs)))
+	}
+
+	return result, nil
+}
+
+// Helper function to convert Google Calendar event to our type
+func (c *Client) convertToCalendarEvent(event *calendar.Event) *types.CalendarEvent {
+	calEvent := &types.CalendarEvent{
+		ID:          event.Id,
+		Summary:     event.Summary,
+		Description: event.Description,
+		Location:    event.Location,
+		Status:      event.Status,
+		HTMLLink:    event.HtmlLink,
+		Created:     event.Created,
+		Updated:     event.Updated,
+		Recurrence:  event.Recurrence,
+	}
+
+	// Deleted events come with little more than their ID and status
+	if event.Creator != nil {
+		calEvent.Creator = event.Creator.Email
+	}
+	if event.Organizer != nil {
+		calEvent.Organizer = event.Organizer.Email
+	}
+
+	// Start time
+	if event.Start != nil {
+		if event.Start.DateTime != "" {
+			calEvent.StartTime = event.Start.DateTime
+			calEvent.StartTimeZone = event.Start.TimeZone
+		} else if event.Start.Date != "" {
+			calEvent.StartDate = event.Start.Date
+			calEvent.AllDay = true
+		}
+	}
+
+	// End time
+	if event.End != nil {
+		if event.End.DateTime != "" {
+			calEvent.EndTime = event.End.DateTime
+			calEvent.EndTimeZone = event.End.TimeZone
+		} else if event.End.Date != "" {
+			calEvent.EndDate = event.End.Date
+			calEvent.AllDay = true
+		}
+	}
+
+	// Instance of a recurring event
+	calEvent.RecurringEventID = event.RecurringEventId
+	if event.OriginalStartTime != nil {
+		calEvent.OriginalStartTime = event.OriginalStartTime.DateTime
+		if calEvent.OriginalStartTime == "" {
+			calEvent.OriginalStartTime = event.OriginalStartTime.Date
+		}
+	}
+
+	// Attendees
+	if len(event.Attendees) > 0 {
+		attendees := make([]*types.EventAttendee, len(event.Attendees))
+		for i, attendee := range event.Attendees {
+			attendees[i] = &types.EventAttendee{
+				Email:          attendee.Email,
+				DisplayName:    attendee.DisplayName,
+				ResponseStatus: attendee.ResponseStatus,
+				Organizer:      attendee.Organizer,
+			}
+		}
+		calEvent.Attendees = attendees
+	}
+
+	// Reminders, when they override the calendar's defaults
+	if event.Reminders != nil && !event.Reminders.UseDefault {
+		for _, reminder := range event.Reminders.Overrides {
+			calEvent.Reminders = append(calEvent.Reminders, &types.EventReminder{
+				Method:  reminder.Method,
+				Minutes: int(reminder.Minutes),
+			})
+		}
+	}
+
+	return calEvent
+}
+
+// setRecurringTimeZone defaults the time zone of a timed recurring event to
+// UTC. The API rejects recurring events without one, since it is needed to
+// expand the rule across daylight saving changes.
+func setRecurringTimeZone(event *calendar.Event) {
+	for _, t := range []*calendar.EventDateTime{event.Start, event.End} {
+		if t != nil && t.DateTime != "" && t.TimeZone == "" {
+			t.TimeZone = "UTC"
+		}
+	}
+}
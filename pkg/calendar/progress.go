@@ -0,0 +1,26 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+)
+
+// errStopPaging ends a Pages loop early without reporting a failure
+var errStopPaging = errors.New("stop paging")
+
+// ProgressFunc receives progress reports from long-running operations.
+// progress increases with every call; total is zero when unknown.
+type ProgressFunc func(progress, total float64, message string)
+
+type progressKey struct{}
+
+// WithProgress makes operations run with ctx report their progress to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func reportProgress(ctx context.Context, progress, total float64, message string) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(progress, total, message)
+	}
+}
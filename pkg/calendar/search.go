@@ -0,0 +1,249 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+const (
+	// searchConcurrency bounds how many calendars SearchEvents lists at
+	// once, to stay clear of Google's rate limits
+	searchConcurrency = 4
+
+	// searchFetchLimit bounds how many matching events SearchEvents reads
+	// from each calendar before ranking them
+	searchFetchLimit = 250
+)
+
+// searchFields are the parts of an event a search ranks matches in, with
+// how much a match in each counts
+var searchFields = []struct {
+	name   string
+	weight float64
+	text   func(event *types.CalendarEvent) []string
+}{
+	{"summary", 4, func(event *types.CalendarEvent) []string {
+		return []string{event.Summary}
+	}},
+	{"location", 2, func(event *types.CalendarEvent) []string {
+		return []string{event.Location}
+	}},
+	{"attendees", 2, func(event *types.CalendarEvent) []string {
+		text := []string{event.Organizer}
+		for _, attendee := range event.Attendees {
+			text = append(text, attendee.Email, attendee.DisplayName)
+		}
+		return text
+	}},
+	{"description", 1, func(event *types.CalendarEvent) []string {
+		return []string{event.Description}
+	}},
+}
+
+// SearchEvents searches the events of several calendars on any Backend,
+// every calendar in the calendar list unless args.CalendarIDs names some.
+// The calendars are queried concurrently and their matches merged, with an
+// event found in several calendars, such as a meeting on the calendars of
+// two attendees, listed once under its organizer's calendar if that is one
+// of them. Matches are ranked by how many query terms they contain and
+// where, whole words counting more than parts of words and the summary
+// counting most, then by start time.
+//
+// Calendars that fail are reported in the result instead of failing the
+// search, unless all of them do.
+func SearchEvents(ctx context.Context, b Backend, args *types.SearchEventsArgs) (*types.SearchEventsResult, error) {
+	phrase := strings.Join(strings.Fields(strings.ToLower(args.Query)), " ")
+	if phrase == "" {
+		return nil, fmt.Errorf("query must not be empty")
+	}
+	terms := strings.Fields(phrase)
+
+	calendars, err := searchCalendars(ctx, b, args.CalendarIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	type listing struct {
+		events []*types.CalendarEvent
+		err    error
+	}
+	listings := make([]listing, len(calendars))
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex // serializes progress reports
+		done int
+		sem  = make(chan struct{}, searchConcurrency)
+	)
+	for i, cal := range calendars {
+		wg.Add(1)
+		go func(i int, cal *types.Calendar) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				listings[i].err = ctx.Err()
+				return
+			}
+
+			events, _, err := b.ListEvents(ctx, &types.ListEventsArgs{
+				CalendarID: cal.ID,
+				Query:      args.Query,
+				TimeMin:    args.TimeMin,
+				TimeMax:    args.TimeMax,
+				MaxResults: searchFetchLimit,
+			})
+			listings[i] = listing{events, err}
+
+			mu.Lock()
+			done++
+			reportProgress(ctx, float64(done), float64(len(calendars)), fmt.Sprintf("Searched %d of %d calendars", done, len(calendars)))
+			mu.Unlock()
+		}(i, cal)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &types.SearchEventsResult{Events: []*types.EventMatch{}}
+	byID := make(map[string]*types.EventMatch)
+	for i, cal := range calendars {
+		result.SearchedCalendars = append(result.SearchedCalendars, cal.ID)
+		if err := listings[i].err; err != nil {
+			if result.FailedCalendars == nil {
+				result.FailedCalendars = make(map[string]string)
+			}
+			result.FailedCalendars[cal.ID] = err.Error()
+			continue
+		}
+
+		for _, event := range listings[i].events {
+			if match, ok := byID[event.ID]; ok {
+				if event.Organizer == cal.ID && match.Organizer != match.CalendarID {
+					match.AlsoIn = append(match.AlsoIn, match.CalendarID)
+					match.CalendarEvent, match.CalendarID, match.CalendarSummary = event, cal.ID, cal.Summary
+				} else {
+					match.AlsoIn = append(match.AlsoIn, cal.ID)
+				}
+				continue
+			}
+
+			match := &types.EventMatch{
+				CalendarEvent:   event,
+				CalendarID:      cal.ID,
+				CalendarSummary: cal.Summary,
+			}
+			match.Score, match.MatchedFields = relevance(event, terms, phrase)
+			byID[event.ID] = match
+			result.Events = append(result.Events, match)
+		}
+	}
+	if len(calendars) > 0 && len(result.FailedCalendars) == len(calendars) {
+		return nil, listings[0].err
+	}
+
+	sort.SliceStable(result.Events, func(i, j int) bool {
+		a, b := result.Events[i], result.Events[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return startsBefore(a.CalendarEvent, b.CalendarEvent)
+	})
+	if args.MaxResults > 0 && len(result.Events) > args.MaxResults {
+		result.Events = result.Events[:args.MaxResults]
+	}
+	return result, nil
+}
+
+// searchCalendars returns the calendars to search: those named, labelled
+// from the calendar list where possible, or every calendar whose events
+// the user can read
+func searchCalendars(ctx context.Context, b Backend, calendarIDs []string) ([]*types.Calendar, error) {
+	list, _, err := b.ListCalendars(ctx, &types.ListCalendarsArgs{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendars: %w", err)
+	}
+
+	if len(calendarIDs) == 0 {
+		var calendars []*types.Calendar
+		for _, cal := range list {
+			// Free/busy readers only see when events are, not what they are
+			if cal.AccessRole != "freeBusyReader" {
+				calendars = append(calendars, cal)
+			}
+		}
+		return calendars, nil
+	}
+
+	calendars := make([]*types.Calendar, 0, len(calendarIDs))
+	seen := make(map[string]bool)
+	for _, id := range calendarIDs {
+		cal := &types.Calendar{ID: id}
+		for _, listed := range list {
+			if listed.ID == id || (id == "primary" && listed.Primary) {
+				cal = listed
+				break
+			}
+		}
+		if !seen[cal.ID] {
+			seen[cal.ID] = true
+			calendars = append(calendars, cal)
+		}
+	}
+	return calendars, nil
+}
+
+// relevance scores how well event matches the query terms, and names the
+// fields that contain any of them. A term counts the weight of every field
+// it is a word of and half of it in fields it is only part of a word of;
+// the whole phrase appearing in a field counts its weight once more.
+func relevance(event *types.CalendarEvent, terms []string, phrase string) (float64, []string) {
+	var score float64
+	var matched []string
+	for _, field := range searchFields {
+		text := strings.ToLower(strings.Join(field.text(event), "\n"))
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		words := make(map[string]bool)
+		for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			words[word] = true
+		}
+
+		var fieldScore float64
+		for _, term := range terms {
+			if words[term] {
+				fieldScore += field.weight
+			} else if strings.Contains(text, term) {
+				fieldScore += field.weight / 2
+			}
+		}
+		if len(terms) > 1 && strings.Contains(text, phrase) {
+			fieldScore += field.weight
+		}
+		if fieldScore > 0 {
+			score += fieldScore
+			matched = append(matched, field.name)
+		}
+	}
+	return score, matched
+}
+
+// startsBefore orders events by start, those without a valid start last
+func startsBefore(a, b *types.CalendarEvent) bool {
+	at, aErr := parseEventStart(startOf(a))
+	bt, bErr := parseEventStart(startOf(b))
+	if aErr != nil || bErr != nil {
+		return aErr == nil && bErr != nil
+	}
+	return at.Before(bt)
+}
@@ -0,0 +1,265 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// failingBackend is a MemoryBackend whose listings of some calendars fail
+type failingBackend struct {
+	*MemoryBackend
+	fail map[string]bool
+}
+
+func (b *failingBackend) ListEvents(ctx context.Context, args *types.ListEventsArgs) ([]*types.CalendarEvent, string, error) {
+	if b.fail[args.CalendarID] {
+		return nil, "", errors.New("backend unavailable")
+	}
+	return b.MemoryBackend.ListEvents(ctx, args)
+}
+
+// newSearchBackend returns a MemoryBackend with three calendars:
+//
+//	alice@example.com  primary, with a meeting bob organizes
+//	bob@example.com    with the same meeting
+//	carol@example.com  which alice only sees free/busy of
+func newSearchBackend(t *testing.T) *MemoryBackend {
+	t.Helper()
+	b := NewMemoryBackend("alice@example.com")
+	b.addCalendar(&types.Calendar{ID: "bob@example.com", Summary: "Bob", AccessRole: "reader", TimeZone: "UTC"})
+	b.addCalendar(&types.Calendar{ID: "carol@example.com", Summary: "Carol", AccessRole: "freeBusyReader", TimeZone: "UTC"})
+
+	event := func(id, organizer, summary, start string) *types.CalendarEvent {
+		return &types.CalendarEvent{
+			ID:        id,
+			Summary:   summary,
+			Organizer: organizer,
+			StartTime: "2025-01-" + start + ":00Z",
+			EndTime:   "2025-01-" + start[:3] + "23:00:00Z",
+		}
+	}
+	kickoff := event("kickoff", "bob@example.com", "Budget kickoff", "07T10:00")
+	kickoff.Attendees = []*types.EventAttendee{{Email: "alice@example.com"}, {Email: "bob@example.com"}}
+	room := event("room", "alice@example.com", "Planning", "06T09:00")
+	room.Location = "Budget room"
+	notes := event("notes", "alice@example.com", "Sync", "06T11:00")
+	notes.Description = "Go over the budget numbers"
+
+	put := func(calendarID string, events ...*types.CalendarEvent) {
+		t.Helper()
+		if skipped, err := b.putEvents(calendarID, events); err != nil || len(skipped) > 0 {
+			t.Fatalf("putEvents(%s) = %v, %v", calendarID, skipped, err)
+		}
+	}
+	put("alice@example.com",
+		event("review", "alice@example.com", "Budget review", "06T10:00"),
+		room,
+		event("budgetary", "alice@example.com", "Budgetary planning", "06T08:00"),
+		notes,
+		kickoff,
+		event("lunch", "alice@example.com", "Lunch", "06T12:00"),
+	)
+	put("bob@example.com",
+		kickoff,
+		event("forecast", "bob@example.com", "Budget forecast", "08T10:00"),
+	)
+	put("carol@example.com",
+		event("secret", "carol@example.com", "Budget secrets", "06T10:00"),
+	)
+	return b
+}
+
+// searchMatch summarizes an EventMatch for comparison
+type searchMatch struct {
+	ID, CalendarID string
+	AlsoIn         []string
+	Score          float64
+}
+
+func searchMatches(result *types.SearchEventsResult) []searchMatch {
+	var out []searchMatch
+	for _, match := range result.Events {
+		out = append(out, searchMatch{match.ID, match.CalendarID, match.AlsoIn, match.Score})
+	}
+	return out
+}
+
+func TestSearchEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     *types.SearchEventsArgs
+		want     []searchMatch
+		searched []string
+	}{
+		{
+			name: "ranked across calendars",
+			args: &types.SearchEventsArgs{Query: "budget"},
+			want: []searchMatch{
+				{"review", "alice@example.com", nil, 4},
+				// Listed once, under the calendar of bob, who organizes it
+				{"kickoff", "bob@example.com", []string{"alice@example.com"}, 4},
+				{"forecast", "bob@example.com", nil, 4},
+				{"budgetary", "alice@example.com", nil, 2},
+				{"room", "alice@example.com", nil, 2},
+				{"notes", "alice@example.com", nil, 1},
+			},
+			searched: []string{"alice@example.com", "bob@example.com"},
+		},
+		{
+			name: "phrase",
+			args: &types.SearchEventsArgs{Query: "  Budget   REVIEW "},
+			want: []searchMatch{
+				{"review", "alice@example.com", nil, 12},
+			},
+			searched: []string{"alice@example.com", "bob@example.com"},
+		},
+		{
+			name: "named calendars",
+			args: &types.SearchEventsArgs{Query: "budget", CalendarIDs: []string{"primary", "carol@example.com", "alice@example.com"}},
+			want: []searchMatch{
+				{"review", "alice@example.com", nil, 4},
+				{"secret", "carol@example.com", nil, 4},
+				{"kickoff", "alice@example.com", nil, 4},
+				{"budgetary", "alice@example.com", nil, 2},
+				{"room", "alice@example.com", nil, 2},
+				{"notes", "alice@example.com", nil, 1},
+			},
+			searched: []string{"alice@example.com", "carol@example.com"},
+		},
+		{
+			name: "max results",
+			args: &types.SearchEventsArgs{Query: "budget", MaxResults: 2, TimeMin: "2025-01-07T00:00:00Z"},
+			want: []searchMatch{
+				{"kickoff", "bob@example.com", []string{"alice@example.com"}, 4},
+				{"forecast", "bob@example.com", nil, 4},
+			},
+			searched: []string{"alice@example.com", "bob@example.com"},
+		},
+	}
+
+	b := newSearchBackend(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SearchEvents(context.Background(), b, tt.args)
+			if err != nil {
+				t.Fatalf("SearchEvents() error = %v", err)
+			}
+			if got := searchMatches(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchEvents() events = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(result.SearchedCalendars, tt.searched) {
+				t.Errorf("SearchEvents() searched %q, want %q", result.SearchedCalendars, tt.searched)
+			}
+			if result.FailedCalendars != nil {
+				t.Errorf("SearchEvents() failed calendars = %v", result.FailedCalendars)
+			}
+		})
+	}
+}
+
+func TestSearchEventsFailures(t *testing.T) {
+	b := &failingBackend{
+		MemoryBackend: newSearchBackend(t),
+		fail:          map[string]bool{"bob@example.com": true},
+	}
+	ctx := context.Background()
+
+	result, err := SearchEvents(ctx, b, &types.SearchEventsArgs{Query: "kickoff"})
+	if err != nil {
+		t.Fatalf("SearchEvents() error = %v", err)
+	}
+	want := []searchMatch{{"kickoff", "alice@example.com", nil, 4}}
+	if got := searchMatches(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchEvents() events = %+v, want %+v", got, want)
+	}
+	if got, want := result.FailedCalendars, map[string]string{"bob@example.com": "backend unavailable"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchEvents() failed calendars = %v, want %v", got, want)
+	}
+
+	b.fail["alice@example.com"] = true
+	if _, err := SearchEvents(ctx, b, &types.SearchEventsArgs{Query: "kickoff"}); err == nil || err.Error() != "backend unavailable" {
+		t.Errorf("SearchEvents() with every calendar failing error = %v, want backend unavailable", err)
+	}
+
+	if _, err := SearchEvents(ctx, b, &types.SearchEventsArgs{Query: " \t"}); err == nil || !strings.Contains(err.Error(), "query") {
+		t.Errorf("SearchEvents() with a blank query error = %v", err)
+	}
+}
+
+func TestRelevance(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       *types.CalendarEvent
+		query       string
+		wantScore   float64
+		wantMatched []string
+	}{
+		{
+			name:        "summary word",
+			event:       &types.CalendarEvent{Summary: "Budget review"},
+			query:       "budget",
+			wantScore:   4,
+			wantMatched: []string{"summary"},
+		},
+		{
+			name:        "part of a word counts half",
+			event:       &types.CalendarEvent{Summary: "Budgetary review"},
+			query:       "budget",
+			wantScore:   2,
+			wantMatched: []string{"summary"},
+		},
+		{
+			name:        "phrase counts once more",
+			event:       &types.CalendarEvent{Summary: "Budget review"},
+			query:       "budget review",
+			wantScore:   12,
+			wantMatched: []string{"summary"},
+		},
+		{
+			name:        "terms apart",
+			event:       &types.CalendarEvent{Summary: "Review of the budget"},
+			query:       "budget review",
+			wantScore:   8,
+			wantMatched: []string{"summary"},
+		},
+		{
+			name: "every field",
+			event: &types.CalendarEvent{
+				Summary:     "Budget",
+				Location:    "Budget room",
+				Organizer:   "alice@example.com",
+				Attendees:   []*types.EventAttendee{{Email: "finance@example.com", DisplayName: "Budget office"}},
+				Description: "budget-2025",
+			},
+			query:       "budget",
+			wantScore:   9,
+			wantMatched: []string{"summary", "location", "attendees", "description"},
+		},
+		{
+			name:        "organizer",
+			event:       &types.CalendarEvent{Summary: "Sync", Organizer: "budget@example.com"},
+			query:       "budget",
+			wantScore:   2,
+			wantMatched: []string{"attendees"},
+		},
+		{
+			name:  "no match",
+			event: &types.CalendarEvent{Summary: "Lunch", Description: "Tacos"},
+			query: "budget",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, matched := relevance(tt.event, strings.Fields(tt.query), tt.query)
+			if score != tt.wantScore || !reflect.DeepEqual(matched, tt.wantMatched) {
+				t.Errorf("relevance() = %v, %q; want %v, %q", score, matched, tt.wantScore, tt.wantMatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/recurrence"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// Scopes of a change to an instance of a recurring event, matching the
+// choices the Calendar UI offers
+const (
+	ScopeThis      = "this"
+	ScopeFollowing = "following"
+	ScopeAll       = "all"
+)
+
+// UpdateEventInScope updates an event on any Backend. With a scope,
+// args.EventID names an instance of a recurring event, and the change applies
+// to that instance alone, to it and the following instances, which are split
+// off into a new series, or to the whole series. It returns the ID of the
+// updated event, which for ScopeFollowing is the new series.
+func UpdateEventInScope(ctx context.Context, b Backend, args *types.UpdateEventArgs) (string, error) {
+	if args.Scope == "" {
+		return args.EventID, b.UpdateEvent(ctx, args)
+	}
+	instance, master, err := series(ctx, b, args.CalendarID, args.EventID, args.Scope)
+	if err != nil {
+		return "", err
+	}
+	changesRecurrence := len(args.Recurrence) > 0 || args.RecurrenceRule != nil
+
+	switch args.Scope {
+	case ScopeThis:
+		if changesRecurrence {
+			return "", fmt.Errorf("the recurrence of a series can only be changed for all or the following events")
+		}
+		return args.EventID, b.UpdateEvent(ctx, args)
+
+	case ScopeFollowing:
+		dtstart, at, err := splitPoint(instance, master)
+		if err != nil {
+			return "", err
+		}
+		if at.After(dtstart) {
+			return splitSeries(ctx, b, args, master, dtstart, at)
+		}
+		// From the first instance on, the following events are all of them
+	}
+
+	update := *args
+	update.EventID = master.ID
+	update.Scope = ""
+	if update.StartTime, err = shiftTime(args.StartTime, startOf(instance), startOf(master)); err != nil {
+		return "", fmt.Errorf("invalid start time format: %w", err)
+	}
+	if update.EndTime, err = shiftTime(args.EndTime, endOf(instance), endOf(master)); err != nil {
+		return "", fmt.Errorf("invalid end time format: %w", err)
+	}
+	return master.ID, b.UpdateEvent(ctx, &update)
+}
+
+// DeleteEventInScope deletes an event on any Backend. With a scope, eventID
+// names an instance of a recurring event, and the instance alone, it and the
+// following instances, or the whole series is deleted.
+func DeleteEventInScope(ctx context.Context, b Backend, args *types.DeleteEventArgs) error {
+	if args.Scope == "" {
+		return b.DeleteEvent(ctx, args.CalendarID, args.EventID)
+	}
+	instance, master, err := series(ctx, b, args.CalendarID, args.EventID, args.Scope)
+	if err != nil {
+		return err
+	}
+
+	switch args.Scope {
+	case ScopeThis:
+		return b.DeleteEvent(ctx, args.CalendarID, args.EventID)
+
+	case ScopeFollowing:
+		dtstart, at, err := splitPoint(instance, master)
+		if err != nil {
+			return err
+		}
+		if at.After(dtstart) {
+			before, _, err := recurrence.Split(master.Recurrence, dtstart, at, master.AllDay)
+			if err != nil {
+				return fmt.Errorf("invalid recurrence: %w", err)
+			}
+			return b.UpdateEvent(ctx, &types.UpdateEventArgs{
+				CalendarID: args.CalendarID,
+				EventID:    master.ID,
+				Recurrence: before,
+			})
+		}
+	}
+	return b.DeleteEvent(ctx, args.CalendarID, master.ID)
+}
+
+// series returns the instance eventID names and the recurring event it
+// belongs to. With ScopeAll, eventID may also name the recurring event.
+func series(ctx context.Context, b Backend, calendarID, eventID, scope string) (*types.CalendarEvent, *types.CalendarEvent, error) {
+	if scope != ScopeThis && scope != ScopeFollowing && scope != ScopeAll {
+		return nil, nil, fmt.Errorf("unknown scope %q, expected %s, %s or %s", scope, ScopeThis, ScopeFollowing, ScopeAll)
+	}
+
+	instance, err := b.GetEvent(ctx, calendarID, eventID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get existing event: %w", err)
+	}
+	if instance.RecurringEventID == "" {
+		if scope == ScopeAll && len(instance.Recurrence) > 0 {
+			return instance, instance, nil
+		}
+		return nil, nil, fmt.Errorf("scope %s needs the ID of an instance of a recurring event", scope)
+	}
+
+	master, err := b.GetEvent(ctx, calendarID, instance.RecurringEventID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get recurring event: %w", err)
+	}
+	return instance, master, nil
+}
+
+// splitPoint returns the start of a series, in its time zone, and the
+// original start of one of its instances
+func splitPoint(instance, master *types.CalendarEvent) (time.Time, time.Time, error) {
+	dtstart, err := parseEventStart(startOf(master))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("recurring event has an invalid start: %w", err)
+	}
+	if zone, err := time.LoadLocation(master.StartTimeZone); master.StartTime != "" && err == nil {
+		dtstart = dtstart.In(zone)
+	}
+	at, err := parseEventStart(instance.OriginalStartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("instance has an invalid original start: %w", err)
+	}
+	return dtstart, at.In(dtstart.Location()), nil
+}
+
+// splitSeries ends master before its instance at and continues it as a new
+// series from there with the changes in args
+func splitSeries(ctx context.Context, b Backend, args *types.UpdateEventArgs, master *types.CalendarEvent, dtstart, at time.Time) (string, error) {
+	before, after, err := recurrence.Split(master.Recurrence, dtstart, at, master.AllDay)
+	if err != nil {
+		return "", fmt.Errorf("invalid recurrence: %w", err)
+	}
+
+	create := &types.CreateEventArgs{
+		Summary:     master.Summary,
+		Description: master.Description,
+		Location:    master.Location,
+		TimeZone:    master.StartTimeZone,
+		AllDay:      master.AllDay,
+		CalendarID:  args.CalendarID,
+		Reminders:   master.Reminders,
+		Recurrence:  after,
+	}
+	for _, attendee := range master.Attendees {
+		create.Attendees = append(create.Attendees, attendee.Email)
+	}
+	if master.AllDay {
+		start, _ := parseEventStart(master.StartDate)
+		end, err := parseEventStart(master.EndDate)
+		if err != nil {
+			return "", fmt.Errorf("recurring event has an invalid end: %w", err)
+		}
+		create.StartDate = at.Format("2006-01-02")
+		create.EndDate = at.Add(end.Sub(start)).Format("2006-01-02")
+	} else {
+		end, err := time.Parse(time.RFC3339, master.EndTime)
+		if err != nil {
+			return "", fmt.Errorf("recurring event has an invalid end: %w", err)
+		}
+		create.StartTime = at.Format(time.RFC3339)
+		create.EndTime = at.Add(end.Sub(dtstart)).Format(time.RFC3339)
+
+		// Moving the start alone moves the end with it, keeping the length
+		// of the events
+		if args.StartTime != "" && args.EndTime == "" {
+			start, err := time.Parse(time.RFC3339, args.StartTime)
+			if err != nil {
+				return "", fmt.Errorf("invalid start time format: %w", err)
+			}
+			create.EndTime = start.Add(end.Sub(dtstart)).Format(time.RFC3339)
+		}
+	}
+
+	if args.Summary != "" {
+		create.Summary = args.Summary
+	}
+	if args.Description != "" {
+		create.Description = args.Description
+	}
+	if args.Location != "" {
+		create.Location = args.Location
+	}
+	if args.StartTime != "" {
+		create.StartTime = args.StartTime
+	}
+	if args.EndTime != "" {
+		create.EndTime = args.EndTime
+	}
+	if args.TimeZone != "" {
+		create.TimeZone = args.TimeZone
+	}
+	if len(args.Attendees) > 0 {
+		create.Attendees = args.Attendees
+	}
+	if len(args.Reminders) > 0 {
+		create.Reminders = args.Reminders
+	}
+	if len(args.Recurrence) > 0 || args.RecurrenceRule != nil {
+		create.Recurrence, create.RecurrenceRule = args.Recurrence, args.RecurrenceRule
+	}
+
+	// Check the new series before cutting the old one short
+	if _, err := recurrence.Lines(create.Recurrence, create.RecurrenceRule, create.AllDay); err != nil {
+		return "", fmt.Errorf("invalid recurrence: %w", err)
+	}
+
+	err = b.UpdateEvent(ctx, &types.UpdateEventArgs{
+		CalendarID: args.CalendarID,
+		EventID:    master.ID,
+		Recurrence: before,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	id, err := b.CreateEvent(ctx, create)
+	if err != nil {
+		// Put the series back as it was
+		restore := &types.UpdateEventArgs{
+			CalendarID: args.CalendarID,
+			EventID:    master.ID,
+			Recurrence: master.Recurrence,
+		}
+		if restoreErr := b.UpdateEvent(ctx, restore); restoreErr != nil {
+			return "", fmt.Errorf("%w (the series now ends early: %v)", err, restoreErr)
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+// shiftTime moves the series time seriesTime by as much as an update moves
+// one of its instances from instanceTime to value. It returns "" when value
+// is.
+func shiftTime(value, instanceTime, seriesTime string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", err
+	}
+	from, err := parseEventStart(instanceTime)
+	if err != nil {
+		return value, nil
+	}
+	to, err := parseEventStart(seriesTime)
+	if err != nil {
+		return value, nil
+	}
+	return to.Add(t.Sub(from)).Format(time.RFC3339), nil
+}
+
+func startOf(event *types.CalendarEvent) string {
+	if event.StartTime != "" {
+		return event.StartTime
+	}
+	return event.StartDate
+}
+
+func endOf(event *types.CalendarEvent) string {
+	if event.EndTime != "" {
+		return event.EndTime
+	}
+	return event.EndDate
+}
+
+// parseEventStart parses an RFC3339 time or, for all-day events, a date
+func parseEventStart(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
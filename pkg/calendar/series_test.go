@@ -0,0 +1,108 @@
+package calendar
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/recurrence"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+func TestUpdateEventFollowing(t *testing.T) {
+	reminders := []*types.EventReminder{{Method: "popup", Minutes: 10}}
+	at := time.Date(2025, time.January, 8, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		update         types.UpdateEventArgs
+		start, end     string
+		wantReminders  []*types.EventReminder
+		wantRecurrence []string
+	}{
+		{
+			name:           "summary only",
+			update:         types.UpdateEventArgs{Summary: "Standup (new)"},
+			start:          "2025-01-08T09:00:00Z",
+			end:            "2025-01-08T09:15:00Z",
+			wantReminders:  reminders,
+			wantRecurrence: []string{"RRULE:FREQ=DAILY;COUNT=3"},
+		},
+		{
+			name:           "start moves the end",
+			update:         types.UpdateEventArgs{StartTime: "2025-01-08T10:00:00Z"},
+			start:          "2025-01-08T10:00:00Z",
+			end:            "2025-01-08T10:15:00Z",
+			wantReminders:  reminders,
+			wantRecurrence: []string{"RRULE:FREQ=DAILY;COUNT=3"},
+		},
+		{
+			name:           "start and end",
+			update:         types.UpdateEventArgs{StartTime: "2025-01-08T10:00:00Z", EndTime: "2025-01-08T11:00:00Z"},
+			start:          "2025-01-08T10:00:00Z",
+			end:            "2025-01-08T11:00:00Z",
+			wantReminders:  reminders,
+			wantRecurrence: []string{"RRULE:FREQ=DAILY;COUNT=3"},
+		},
+		{
+			name:           "new reminders",
+			update:         types.UpdateEventArgs{Reminders: []*types.EventReminder{{Method: "email", Minutes: 60}}},
+			start:          "2025-01-08T09:00:00Z",
+			end:            "2025-01-08T09:15:00Z",
+			wantReminders:  []*types.EventReminder{{Method: "email", Minutes: 60}},
+			wantRecurrence: []string{"RRULE:FREQ=DAILY;COUNT=3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			b := NewMemoryBackend("")
+			master, err := b.CreateEvent(ctx, &types.CreateEventArgs{
+				Summary:    "Standup",
+				StartTime:  "2025-01-06T09:00:00Z",
+				EndTime:    "2025-01-06T09:15:00Z",
+				TimeZone:   "UTC",
+				Reminders:  reminders,
+				Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=5"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			update := tt.update
+			update.EventID = recurrence.InstanceID(master, at, false)
+			update.Scope = ScopeFollowing
+			id, err := UpdateEventInScope(ctx, b, &update)
+			if err != nil {
+				t.Fatalf("UpdateEventInScope() error = %v", err)
+			}
+			if id == master {
+				t.Fatal("UpdateEventInScope() updated the series instead of splitting it")
+			}
+
+			event, err := b.GetEvent(ctx, "primary", id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if event.StartTime != tt.start || event.EndTime != tt.end {
+				t.Errorf("new series runs %s to %s, want %s to %s", event.StartTime, event.EndTime, tt.start, tt.end)
+			}
+			if !reflect.DeepEqual(event.Reminders, tt.wantReminders) {
+				t.Errorf("new series reminders = %v, want %v", event.Reminders, tt.wantReminders)
+			}
+			if !reflect.DeepEqual(event.Recurrence, tt.wantRecurrence) {
+				t.Errorf("new series recurrence = %q, want %q", event.Recurrence, tt.wantRecurrence)
+			}
+
+			old, err := b.GetEvent(ctx, "primary", master)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"RRULE:FREQ=DAILY;UNTIL=20250108T085959Z"}; !reflect.DeepEqual(old.Recurrence, want) {
+				t.Errorf("old series recurrence = %q, want %q", old.Recurrence, want)
+			}
+		})
+	}
+}
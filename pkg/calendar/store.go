@@ -0,0 +1,178 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// DiskStore keeps synced calendars in a directory of JSON files, so that a
+// CachedBackend can pick up where it left off after a restart and an
+// OfflineBackend can answer reads without Google. Each calendar's events
+// are one file, written whole to a temporary file and renamed into place,
+// so a crash leaves either the old copy or the new one.
+type DiskStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// calendarSnapshot is a synced calendar as stored on disk
+type calendarSnapshot struct {
+	Calendar  *types.Calendar        `json:"calendar"`
+	SyncToken string                 `json:"syncToken,omitempty"`
+	SyncedAt  time.Time              `json:"syncedAt"`
+	Events    []*types.CalendarEvent `json:"events"`
+}
+
+// calendarListSnapshot is the calendar list as stored on disk
+type calendarListSnapshot struct {
+	SyncedAt  time.Time         `json:"syncedAt"`
+	Calendars []*types.Calendar `json:"calendars"`
+}
+
+const (
+	calendarListFile = "calendars.json"
+	eventsDir        = "events"
+)
+
+// NewDiskStore opens the store in dir, creating the directory if needed
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, eventsDir), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	return &DiskStore{dir: dir}, nil
+}
+
+// Dir returns the directory the store keeps its files in
+func (s *DiskStore) Dir() string {
+	return s.dir
+}
+
+// saveCalendar stores the snapshot of the calendar cached under key
+func (s *DiskStore) saveCalendar(key string, snapshot *calendarSnapshot) error {
+	return s.write(s.calendarPath(key), snapshot)
+}
+
+// loadCalendar returns the snapshot of the calendar cached under key, or
+// nil if there is none
+func (s *DiskStore) loadCalendar(key string) (*calendarSnapshot, error) {
+	var snapshot calendarSnapshot
+	if ok, err := s.read(s.calendarPath(key), &snapshot); !ok {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// loadCalendars returns every calendar snapshot by the key it was cached
+// under
+func (s *DiskStore) loadCalendars() (map[string]*calendarSnapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, eventsDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+
+	snapshots := make(map[string]*calendarSnapshot)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		key, err := url.PathUnescape(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		snapshot, err := s.loadCalendar(key)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			snapshots[key] = snapshot
+		}
+	}
+	return snapshots, nil
+}
+
+// removeCalendar deletes the snapshot of the calendar cached under key
+func (s *DiskStore) removeCalendar(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.calendarPath(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s from store: %w", key, err)
+	}
+	return nil
+}
+
+// saveCalendarList stores the calendar list
+func (s *DiskStore) saveCalendarList(snapshot *calendarListSnapshot) error {
+	return s.write(filepath.Join(s.dir, calendarListFile), snapshot)
+}
+
+// loadCalendarList returns the stored calendar list, or nil if there is none
+func (s *DiskStore) loadCalendarList() (*calendarListSnapshot, error) {
+	var snapshot calendarListSnapshot
+	if ok, err := s.read(filepath.Join(s.dir, calendarListFile), &snapshot); !ok {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// calendarPath returns the file of the calendar cached under key. Calendar
+// IDs are email addresses, which only need their slashes escaped to be file
+// names, but escaping keeps any ID safe.
+func (s *DiskStore) calendarPath(key string) string {
+	return filepath.Join(s.dir, eventsDir, url.PathEscape(key)+".json")
+}
+
+func (s *DiskStore) write(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	return nil
+}
+
+// read decodes the file at path into v, reporting false if it does not
+// exist
+func (s *DiskStore) read(path string, v interface{}) (bool, error) {
+	s.mu.Lock()
+	data, err := os.ReadFile(path)
+	s.mu.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read store: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+	return true, nil
+}
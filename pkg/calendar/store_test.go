@@ -0,0 +1,132 @@
+package calendar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+func TestDiskStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+	disk, err := NewDiskStore(dir)
+	if err != nil {
+		t.Fatalf("NewDiskStore() error = %v", err)
+	}
+
+	if snapshot, err := disk.loadCalendar("primary"); snapshot != nil || err != nil {
+		t.Errorf("loadCalendar() of an empty store = %+v, %v; want nil, nil", snapshot, err)
+	}
+	if snapshot, err := disk.loadCalendarList(); snapshot != nil || err != nil {
+		t.Errorf("loadCalendarList() of an empty store = %+v, %v; want nil, nil", snapshot, err)
+	}
+
+	synced := time.Date(2025, time.January, 6, 8, 0, 0, 0, time.UTC)
+	saved := map[string]*calendarSnapshot{
+		"primary": {
+			Calendar:  &types.Calendar{ID: "me@example.com", TimeZone: "UTC"},
+			SyncToken: "token-1",
+			SyncedAt:  synced,
+			Events:    []*types.CalendarEvent{{ID: "standup", Summary: "Standup"}},
+		},
+		// Slashes and other characters file names cannot hold are escaped
+		"team/a b@group.calendar.google.com": {
+			Calendar: &types.Calendar{ID: "team/a b@group.calendar.google.com"},
+			SyncedAt: synced,
+			Events:   []*types.CalendarEvent{},
+		},
+	}
+	for key, snapshot := range saved {
+		if err := disk.saveCalendar(key, snapshot); err != nil {
+			t.Fatalf("saveCalendar(%s) error = %v", key, err)
+		}
+	}
+	list := &calendarListSnapshot{SyncedAt: synced, Calendars: []*types.Calendar{{ID: "me@example.com", Primary: true}}}
+	if err := disk.saveCalendarList(list); err != nil {
+		t.Fatalf("saveCalendarList() error = %v", err)
+	}
+	// Files left behind by an interrupted write are skipped
+	if err := os.WriteFile(filepath.Join(dir, eventsDir, ".tmp-123"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A store opened again on the directory reads what was saved
+	reopened, err := NewDiskStore(dir)
+	if err != nil {
+		t.Fatalf("NewDiskStore() error = %v", err)
+	}
+	loaded, err := reopened.loadCalendars()
+	if err != nil {
+		t.Fatalf("loadCalendars() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("loadCalendars() = %+v, want %+v", loaded, saved)
+	}
+	if got, err := reopened.loadCalendarList(); err != nil || !reflect.DeepEqual(got, list) {
+		t.Errorf("loadCalendarList() = %+v, %v; want %+v", got, err, list)
+	}
+
+	if err := reopened.removeCalendar("team/a b@group.calendar.google.com"); err != nil {
+		t.Fatalf("removeCalendar() error = %v", err)
+	}
+	if err := reopened.removeCalendar("never-saved@example.com"); err != nil {
+		t.Errorf("removeCalendar() of a calendar never saved error = %v", err)
+	}
+	if loaded, err := reopened.loadCalendars(); err != nil || len(loaded) != 1 || loaded["primary"] == nil {
+		t.Errorf("loadCalendars() after remove = %+v, %v; want only primary", loaded, err)
+	}
+
+	if err := os.WriteFile(reopened.calendarPath("broken"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.loadCalendars(); err == nil {
+		t.Error("loadCalendars() with a corrupt file succeeded")
+	}
+}
+
+func TestCachedBackendRestart(t *testing.T) {
+	disk, err := NewDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newCacheFixture(t, disk)
+	ctx := context.Background()
+
+	if _, err := f.cache.CreateEvent(ctx, &types.CreateEventArgs{
+		Summary:   "Planning",
+		StartTime: "2025-01-06T10:00:00Z",
+		EndTime:   "2025-01-06T11:00:00Z",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.summaries(t, "primary"); !reflect.DeepEqual(got, []string{"Planning"}) {
+		t.Fatalf("ListEvents() = %q, want [Planning]", got)
+	}
+	if got := f.reads(); !reflect.DeepEqual(got, []string{"full"}) {
+		t.Fatalf("reads = %q, want a full sync", got)
+	}
+
+	// A new backend on the same store starts from the saved copy, which
+	// is fresh until the TTL runs out and then synced incrementally
+	f.cache = NewCachedBackend(f.cache.SyncBackend, time.Minute, disk)
+	f.cache.now = func() time.Time { return f.now }
+	f.now = f.now.Add(30 * time.Second)
+	if got := f.summaries(t, "primary"); !reflect.DeepEqual(got, []string{"Planning"}) {
+		t.Errorf("ListEvents() after restart = %q, want [Planning]", got)
+	}
+	if got := f.reads(); got != nil {
+		t.Errorf("reads after restart = %q, want none", got)
+	}
+
+	f.now = f.now.Add(time.Minute)
+	if got := f.summaries(t, "primary"); !reflect.DeepEqual(got, []string{"Planning"}) {
+		t.Errorf("ListEvents() after the TTL = %q, want [Planning]", got)
+	}
+	if got := f.reads(); !reflect.DeepEqual(got, []string{"sync"}) {
+		t.Errorf("reads after the TTL = %q, want a sync", got)
+	}
+}
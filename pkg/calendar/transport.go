@@ -0,0 +1,131 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/internal/logging"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// maxAttempts bounds how often an idempotent API call is tried
+	maxAttempts = 3
+
+	// retryBackoff is the delay before the first retry; it doubles after
+	// every further failure
+	retryBackoff = 500 * time.Millisecond
+)
+
+// apiTransport authorizes Calendar API requests and logs them to the logger
+// of the request's context. It retries reads that fail with a rate limit or
+// server error, and saves the OAuth token whenever it is refreshed.
+type apiTransport struct {
+	client *Client
+	source oauth2.TokenSource // nil when requests are not authorized
+	base   http.RoundTripper
+
+	mu          sync.Mutex
+	accessToken string
+}
+
+// newHTTPClient returns the client API requests are sent with. Requests
+// are authorized with token unless it is nil.
+func (c *Client) newHTTPClient(token *oauth2.Token) *http.Client {
+	transport := &apiTransport{
+		client: c,
+		base:   http.DefaultTransport,
+	}
+	if c.httpClient != nil && c.httpClient.Transport != nil {
+		transport.base = c.httpClient.Transport
+	}
+	if token != nil {
+		transport.source = c.oauth.TokenSource(context.Background(), token)
+		transport.accessToken = token.AccessToken
+	}
+	return &http.Client{Transport: transport}
+}
+
+func (t *apiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := logging.FromContext(req.Context()).WithFields(map[string]interface{}{
+		"http_method": req.Method,
+		"path":        req.URL.Path,
+	})
+
+	var token *oauth2.Token
+	if t.source != nil {
+		var err error
+		if token, err = t.token(req); err != nil {
+			log.WithError(err).Error("Failed to obtain OAuth token")
+			return nil, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		// RoundTrippers must not modify the caller's request
+		authorized := req.Clone(req.Context())
+		if token != nil {
+			token.SetAuthHeader(authorized)
+		}
+
+		start := time.Now()
+		resp, err := t.base.RoundTrip(authorized)
+		elapsed := time.Since(start)
+
+		retryable := req.Method == http.MethodGet && attempt < maxAttempts &&
+			(err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500)
+		if !retryable {
+			if err != nil {
+				log.WithError(err).Warn("Calendar API call failed")
+			} else {
+				log.WithFields(map[string]interface{}{
+					"status":      resp.StatusCode,
+					"duration_ms": elapsed.Milliseconds(),
+				}).Debug("Calendar API call")
+			}
+			return resp, err
+		}
+
+		delay := retryBackoff << (attempt - 1)
+		entry := log.WithFields(map[string]interface{}{
+			"attempt":  attempt,
+			"retry_in": delay.String(),
+		})
+		if err != nil {
+			entry = entry.WithError(err)
+		} else {
+			entry = entry.WithField("status", resp.StatusCode)
+			resp.Body.Close()
+		}
+		entry.Warn("Retrying Calendar API call")
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// token returns a valid access token, logging and saving it when the OAuth
+// library had to refresh it
+func (t *apiTransport) token(req *http.Request) (*oauth2.Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token.AccessToken != t.accessToken {
+		t.accessToken = token.AccessToken
+		log := logging.FromContext(req.Context()).WithField("expiry", token.Expiry.Format(time.RFC3339))
+		log.Info("Refreshed OAuth token")
+		if err := t.client.saveToken(token); err != nil {
+			log.WithError(err).Warn("Failed to save refreshed OAuth token")
+		}
+	}
+	return token, nil
+}
@@ -0,0 +1,149 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+	"google.golang.org/api/calendar/v3"
+)
+
+// Headers Google sets on every push notification
+const (
+	headerChannelID         = "X-Goog-Channel-ID"
+	headerChannelToken      = "X-Goog-Channel-Token"
+	headerChannelExpiration = "X-Goog-Channel-Expiration"
+	headerResourceID        = "X-Goog-Resource-ID"
+	headerResourceURI       = "X-Goog-Resource-URI"
+	headerResourceState     = "X-Goog-Resource-State"
+	headerMessageNumber     = "X-Goog-Message-Number"
+)
+
+// WatchEvents registers a push notification channel that makes Google POST
+// to address whenever events on the calendar change
+func (c *Client) WatchEvents(ctx context.Context, calendarID, channelID, address, token string, ttl time.Duration) (*types.WatchChannel, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	channel := &calendar.Channel{
+		Id:      channelID,
+		Type:    "web_hook",
+		Address: address,
+		Token:   token,
+		Params: map[string]string{
+			"ttl": strconv.FormatInt(int64(ttl/time.Second), 10),
+		},
+	}
+
+	result, err := c.service.Events.Watch(calendarID, channel).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch events: %w", err)
+	}
+
+	return &types.WatchChannel{
+		ID:         result.Id,
+		ResourceID: result.ResourceId,
+		CalendarID: calendarID,
+		Token:      token,
+		Expiration: time.UnixMilli(result.Expiration),
+	}, nil
+}
+
+// StopChannel stops a push notification channel
+func (c *Client) StopChannel(ctx context.Context, channel *types.WatchChannel) error {
+	err := c.service.Channels.Stop(&calendar.Channel{
+		Id:         channel.ID,
+		ResourceId: channel.ResourceID,
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to stop channel: %w", err)
+	}
+
+	return nil
+}
+
+// ParseNotification reads a push notification from the headers Google sets
+// on its webhook POSTs
+func ParseNotification(r *http.Request) (*types.WatchNotification, error) {
+	n := &types.WatchNotification{
+		ChannelID:     r.Header.Get(headerChannelID),
+		Token:         r.Header.Get(headerChannelToken),
+		ResourceID:    r.Header.Get(headerResourceID),
+		ResourceURI:   r.Header.Get(headerResourceURI),
+		ResourceState: r.Header.Get(headerResourceState),
+	}
+	if n.ChannelID == "" || n.ResourceState == "" {
+		return nil, fmt.Errorf("missing %s or %s header", headerChannelID, headerResourceState)
+	}
+
+	if number := r.Header.Get(headerMessageNumber); number != "" {
+		value, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s header: %w", headerMessageNumber, err)
+		}
+		n.MessageNumber = value
+	}
+
+	if expiration := r.Header.Get(headerChannelExpiration); expiration != "" {
+		value, err := http.ParseTime(expiration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s header: %w", headerChannelExpiration, err)
+		}
+		n.Expiration = value
+	}
+
+	return n, nil
+}
+
+// WebhookSender delivers push notifications exactly as Google does. It
+// stands in for Google when exercising a webhook endpoint locally.
+type WebhookSender struct {
+	URL    string
+	Client *http.Client
+
+	messageNumber int64
+}
+
+// Send POSTs a notification for channel with the given resource state
+// ("sync", "exists" or "not_exists")
+func (s *WebhookSender) Send(ctx context.Context, channel *types.WatchChannel, state string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(headerChannelID, channel.ID)
+	req.Header.Set(headerResourceID, channel.ResourceID)
+	req.Header.Set(headerResourceState, state)
+	req.Header.Set(headerMessageNumber, strconv.FormatInt(atomic.AddInt64(&s.messageNumber, 1), 10))
+	req.Header.Set(headerResourceURI, fmt.Sprintf("https://www.googleapis.com/calendar/v3/calendars/%s/events", url.PathEscape(channel.CalendarID)))
+	if channel.Token != "" {
+		req.Header.Set(headerChannelToken, channel.Token)
+	}
+	if !channel.Expiration.IsZero() {
+		req.Header.Set(headerChannelExpiration, channel.Expiration.UTC().Format(http.TimeFormat))
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
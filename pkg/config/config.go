@@ -0,0 +1,260 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultToolTimeout bounds tool calls unless configured otherwise
+const DefaultToolTimeout = time.Minute
+
+// DefaultListLimit bounds how many events or calendars one listing returns
+// unless configured otherwise
+const DefaultListLimit = 100
+
+// DefaultRedactFields are the tool arguments kept out of logs by default
+var DefaultRedactFields = []string{"description", "location", "attendees"}
+
+type Config struct {
+	OAuth struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		RedirectURL  string `json:"redirect_url"`
+	} `json:"oauth"`
+	
+	// ConfigDir holds credentials, OAuth keys and prompt templates
+	ConfigDir string `json:"-"`
+
+	CredentialsPath string `json:"credentials_path,omitempty"`
+	OAuthPath       string `json:"oauth_path,omitempty"`
+
+	// WebhookURL is the public HTTPS address of the /webhooks/calendar
+	// route. Resource subscriptions are only available when it is set.
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// ReadOnly hides and rejects every tool that modifies calendars,
+	// requests only the read-only OAuth scopes and stores their token
+	// apart from the read-write one
+	ReadOnly bool `json:"read_only,omitempty"`
+
+	// ToolTimeout bounds every tool call and ToolTimeouts overrides it for
+	// individual tools by name. Zero or less disables the bound.
+	ToolTimeout  time.Duration            `json:"-"`
+	ToolTimeouts map[string]time.Duration `json:"-"`
+
+	// RedactFields names tool arguments whose values are replaced in logs
+	RedactFields []string `json:"redact_fields,omitempty"`
+
+	// ListLimit bounds how many events or calendars list_events and
+	// list_calendars return per call; longer listings continue by cursor
+	ListLimit int `json:"list_limit,omitempty"`
+
+	// CacheTTL is how long events synced from Google are served before the
+	// next incremental sync. The event cache is off unless it is positive.
+	CacheTTL time.Duration `json:"-"`
+
+	// Store saves the event cache's copies under StoreDir, so that they
+	// survive restarts and can be served offline
+	Store bool `json:"store,omitempty"`
+
+	// APIEndpoint overrides the Google Calendar API base URL, for proxies
+	// and fake servers
+	APIEndpoint string `json:"api_endpoint,omitempty"`
+}
+
+// Load reads the configuration, including the OAuth keys needed to talk to
+// Google
+func Load() (*Config, error) {
+	cfg, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.loadOAuthKeys(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadSettings reads everything but the OAuth keys, for backends that do not
+// talk to Google
+func LoadSettings() (*Config, error) {
+	cfg := &Config{
+		ToolTimeout:  DefaultToolTimeout,
+		RedactFields: DefaultRedactFields,
+		ListLimit:    DefaultListLimit,
+	}
+	
+	// Default paths
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	
+	configDir := filepath.Join(homeDir, ".gmail-mcp")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	
+	cfg.ConfigDir = configDir
+	cfg.CredentialsPath = filepath.Join(configDir, "credentials.json")
+	cfg.OAuthPath = filepath.Join(configDir, "gcp-oauth.keys.json")
+	
+	// Override with environment variables if set
+	if path := os.Getenv("GMAIL_CREDENTIALS_PATH"); path != "" {
+		cfg.CredentialsPath = path
+	}
+	if path := os.Getenv("GMAIL_OAUTH_PATH"); path != "" {
+		cfg.OAuthPath = path
+	}
+	if url := os.Getenv("CALENDAR_WEBHOOK_URL"); url != "" {
+		cfg.WebhookURL = url
+	}
+	if readOnly := os.Getenv("CALENDAR_READ_ONLY"); readOnly == "1" || readOnly == "true" {
+		cfg.ReadOnly = true
+	}
+	if timeout := os.Getenv("CALENDAR_TOOL_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CALENDAR_TOOL_TIMEOUT: %w", err)
+		}
+		cfg.ToolTimeout = d
+	}
+	if timeouts := os.Getenv("CALENDAR_TOOL_TIMEOUTS"); timeouts != "" {
+		if cfg.ToolTimeouts, err = parseToolTimeouts(timeouts); err != nil {
+			return nil, fmt.Errorf("invalid CALENDAR_TOOL_TIMEOUTS: %w", err)
+		}
+	}
+	if limit := os.Getenv("CALENDAR_LIST_LIMIT"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid CALENDAR_LIST_LIMIT %q: expected a positive number", limit)
+		}
+		cfg.ListLimit = n
+	}
+	if ttl := os.Getenv("CALENDAR_CACHE_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CALENDAR_CACHE_TTL: %w", err)
+		}
+		cfg.CacheTTL = d
+	}
+	if store := os.Getenv("CALENDAR_STORE"); store == "1" || store == "true" {
+		cfg.Store = true
+	}
+	if endpoint := os.Getenv("CALENDAR_API_ENDPOINT"); endpoint != "" {
+		cfg.APIEndpoint = endpoint
+	}
+	if fields, ok := os.LookupEnv("CALENDAR_REDACT_FIELDS"); ok {
+		cfg.RedactFields = splitList(fields)
+	}
+
+	return cfg, nil
+}
+
+// loadOAuthKeys reads the OAuth client credentials, copying a keys file
+// found in the current directory into the config directory
+func (cfg *Config) loadOAuthKeys() error {
+	oauthData, err := os.ReadFile(cfg.OAuthPath)
+	if err != nil {
+		// Try local directory as fallback
+		localOAuthPath := "gcp-oauth.keys.json"
+		if oauthData, err = os.ReadFile(localOAuthPath); err != nil {
+			return fmt.Errorf("OAuth keys file not found. Please place gcp-oauth.keys.json in current directory or %s", cfg.ConfigDir)
+		}
+		// Copy to config directory for future use
+		if err := os.WriteFile(cfg.OAuthPath, oauthData, 0600); err != nil {
+			return fmt.Errorf("failed to copy OAuth keys to config directory: %w", err)
+		}
+	}
+	
+	var oauthFile struct {
+		Installed *struct {
+			ClientID     string `json:"client_id"`
+			ClientSecret string `json:"client_secret"`
+		} `json:"installed"`
+		Web *struct {
+			ClientID     string `json:"client_id"`
+			ClientSecret string `json:"client_secret"`
+		} `json:"web"`
+	}
+	
+	if err := json.Unmarshal(oauthData, &oauthFile); err != nil {
+		return fmt.Errorf("failed to parse OAuth keys file: %w", err)
+	}
+	
+	if oauthFile.Installed != nil {
+		cfg.OAuth.ClientID = oauthFile.Installed.ClientID
+		cfg.OAuth.ClientSecret = oauthFile.Installed.ClientSecret
+	} else if oauthFile.Web != nil {
+		cfg.OAuth.ClientID = oauthFile.Web.ClientID
+		cfg.OAuth.ClientSecret = oauthFile.Web.ClientSecret
+	} else {
+		return fmt.Errorf("invalid OAuth keys file format. File should contain either 'installed' or 'web' credentials")
+	}
+	
+	cfg.OAuth.RedirectURL = "http://localhost:3000/oauth2callback"
+	
+	return nil
+}
+
+// PromptsDir returns the directory custom prompt templates are loaded from
+func (c *Config) PromptsDir() string {
+	if c.ConfigDir == "" {
+		return ""
+	}
+	return filepath.Join(c.ConfigDir, "prompts")
+}
+
+// StoreDir returns the directory the event cache saves synced calendars in
+// when Store is set
+func (c *Config) StoreDir() string {
+	if c.ConfigDir == "" {
+		return ""
+	}
+	return filepath.Join(c.ConfigDir, "store")
+}
+
+// TokenPath returns the file the OAuth token is stored in. Read-only mode
+// keeps its token in a file of its own next to CredentialsPath, so that it
+// never loads a token that was granted write access.
+func (c *Config) TokenPath() string {
+	if !c.ReadOnly {
+		return c.CredentialsPath
+	}
+	ext := filepath.Ext(c.CredentialsPath)
+	return strings.TrimSuffix(c.CredentialsPath, ext) + ".readonly" + ext
+}
+
+// parseToolTimeouts parses per-tool timeouts written as
+// "list_events=2m,get_freebusy=90s"
+func parseToolTimeouts(s string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range splitList(s) {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected tool=duration, got %q", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", strings.TrimSpace(name), err)
+		}
+		timeouts[strings.TrimSpace(name)] = d
+	}
+	return timeouts, nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
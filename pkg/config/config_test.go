@@ -0,0 +1,21 @@
+package config
+
+import "testing"
+
+func TestTokenPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		readOnly bool
+		want     string
+	}{
+		{path: "/home/me/.gmail-mcp/credentials.json", want: "/home/me/.gmail-mcp/credentials.json"},
+		{path: "/home/me/.gmail-mcp/credentials.json", readOnly: true, want: "/home/me/.gmail-mcp/credentials.readonly.json"},
+		{path: "/etc/calendar/token", readOnly: true, want: "/etc/calendar/token.readonly"},
+	}
+	for _, tt := range tests {
+		cfg := &Config{CredentialsPath: tt.path, ReadOnly: tt.readOnly}
+		if got := cfg.TokenPath(); got != tt.want {
+			t.Errorf("TokenPath() of %s with ReadOnly %v = %s, want %s", tt.path, tt.readOnly, got, tt.want)
+		}
+	}
+}
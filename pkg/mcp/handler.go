@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/phildougherty/mcp-google-calendar-go/internal/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	errCodeParse          = -32700
+	errCodeInvalidRequest = -32600
+	errCodeMethodNotFound = -32601
+	errCodeInvalidParams  = -32602
+	errCodeInternal       = -32603
+
+	// MCP-specific codes
+	errCodeResourceNotFound = -32002
+)
+
+// handleRequest dispatches a single JSON-RPC message to the MCP method it
+// names. It is shared by every transport and returns nil for notifications,
+// which must never be answered, and for requests the client cancelled.
+func (s *Server) handleRequest(ctx context.Context, req *JSONRPCRequest) *JSONRPCResponse {
+	if req.IsNotification() {
+		s.handleNotification(ctx, req)
+		return nil
+	}
+
+	if sess := sessionFromContext(ctx); sess != nil && !sess.Initialized() && !allowedBeforeInitialize(req.Method) {
+		return newErrorResponse(req.ID, errCodeInvalidRequest, fmt.Sprintf("Invalid Request: %s sent before initialize", req.Method))
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if sess := sessionFromContext(ctx); sess != nil {
+		defer sess.track(req.ID, cancel)()
+	}
+	ctx = withRequestLogger(ctx, req)
+	if token := progressToken(req.Params); token != nil {
+		ctx = withProgress(ctx, token)
+	}
+
+	response := s.handleMethod(ctx, req)
+	if errors.Is(context.Cause(ctx), errRequestCancelled) {
+		logrus.Debugf("Request %v (%s) was cancelled by the client", req.ID, req.Method)
+		return nil
+	}
+	return response
+}
+
+// handleMethod runs the MCP method named by a request
+func (s *Server) handleMethod(ctx context.Context, req *JSONRPCRequest) *JSONRPCResponse {
+	var result interface{}
+
+	switch req.Method {
+	case "initialize":
+		return s.handleInitialize(ctx, req)
+	case "ping":
+		result = map[string]interface{}{}
+	case "tools/list":
+		tools := s.tools.ListTools()
+		result = map[string]interface{}{
+			"tools": tools,
+		}
+	case "tools/call":
+		var params ToolsCallParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newErrorResponse(req.ID, errCodeInvalidParams, fmt.Sprintf("Invalid params: %v", err))
+		}
+		// Convert arguments to json.RawMessage
+		argsBytes, err := json.Marshal(params.Arguments)
+		if err != nil {
+			return newErrorResponse(req.ID, errCodeInvalidParams, fmt.Sprintf("Invalid arguments: %v", err))
+		}
+		var toolErr error
+		result, toolErr = s.tools.CallTool(ctx, params.Name, json.RawMessage(argsBytes))
+		if toolErr != nil {
+			logging.FromContext(ctx).WithField("tool", params.Name).WithError(toolErr).Warn("Tool call failed")
+			return newErrorResponse(req.ID, errCodeInternal, fmt.Sprintf("Tool call failed: %v", toolErr))
+		}
+	case "resources/list":
+		resources, err := s.resources.ListResources(ctx)
+		if err != nil {
+			return newErrorResponse(req.ID, errCodeInternal, fmt.Sprintf("Failed to list resources: %v", err))
+		}
+		result = map[string]interface{}{
+			"resources": resources,
+		}
+	case "resources/templates/list":
+		result = map[string]interface{}{
+			"resourceTemplates": s.resources.ListTemplates(),
+		}
+	case "resources/read":
+		var params ResourcesReadParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+			return newErrorResponse(req.ID, errCodeInvalidParams, "Invalid params: uri is required")
+		}
+		contents, err := s.resources.ReadResource(ctx, params.URI)
+		if errors.Is(err, errResourceNotFound) {
+			return newErrorResponse(req.ID, errCodeResourceNotFound, err.Error())
+		}
+		if err != nil {
+			return newErrorResponse(req.ID, errCodeInternal, fmt.Sprintf("Failed to read resource: %v", err))
+		}
+		result = map[string]interface{}{
+			"contents": []*ResourceContents{contents},
+		}
+	case "prompts/list":
+		result = map[string]interface{}{
+			"prompts": s.prompts.ListPrompts(),
+		}
+	case "prompts/get":
+		var params PromptsGetParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return newErrorResponse(req.ID, errCodeInvalidParams, "Invalid params: name is required")
+		}
+		prompt, err := s.prompts.GetPrompt(ctx, params.Name, params.Arguments)
+		if errors.Is(err, errPromptArguments) {
+			return newErrorResponse(req.ID, errCodeInvalidParams, err.Error())
+		}
+		if err != nil {
+			return newErrorResponse(req.ID, errCodeInternal, err.Error())
+		}
+		result = prompt
+	case "logging/setLevel":
+		sess := sessionFromContext(ctx)
+		if sess == nil {
+			return newErrorResponse(req.ID, errCodeInvalidRequest, "Setting the log level requires a session")
+		}
+		var params LoggingSetLevelParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newErrorResponse(req.ID, errCodeInvalidParams, fmt.Sprintf("Invalid params: %v", err))
+		}
+		level, ok := clientLogLevels[params.Level]
+		if !ok {
+			return newErrorResponse(req.ID, errCodeInvalidParams, fmt.Sprintf("Invalid params: unknown log level %q", params.Level))
+		}
+		sess.setLogLevel(level)
+		result = map[string]interface{}{}
+	case "resources/subscribe", "resources/unsubscribe":
+		if s.subscriptions == nil {
+			return newErrorResponse(req.ID, errCodeMethodNotFound, "Resource subscriptions are not enabled: no webhook URL is configured")
+		}
+		sess := sessionFromContext(ctx)
+		if sess == nil {
+			return newErrorResponse(req.ID, errCodeInvalidRequest, "Resource subscriptions require a session")
+		}
+		var params ResourcesSubscribeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+			return newErrorResponse(req.ID, errCodeInvalidParams, "Invalid params: uri is required")
+		}
+		var err error
+		if req.Method == "resources/subscribe" {
+			err = s.subscriptions.Subscribe(ctx, sess, params.URI)
+		} else {
+			err = s.subscriptions.Unsubscribe(sess, params.URI)
+		}
+		if errors.Is(err, errResourceNotFound) {
+			return newErrorResponse(req.ID, errCodeResourceNotFound, err.Error())
+		}
+		if err != nil {
+			return newErrorResponse(req.ID, errCodeInternal, err.Error())
+		}
+		result = map[string]interface{}{}
+	default:
+		return newErrorResponse(req.ID, errCodeMethodNotFound, fmt.Sprintf("Method not found: %s", req.Method))
+	}
+
+	return &JSONRPCResponse{
+		JsonRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+// handleNotification acts on a message that has no id
+func (s *Server) handleNotification(ctx context.Context, req *JSONRPCRequest) {
+	switch req.Method {
+	case "notifications/initialized":
+		// Nothing to do; the session is usable as soon as initialize returns
+	case "notifications/cancelled":
+		var params struct {
+			RequestID interface{} `json:"requestId"`
+			Reason    string      `json:"reason"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.RequestID == nil {
+			logrus.Debugf("Ignoring malformed cancellation: %s", req.Params)
+			return
+		}
+		sess := sessionFromContext(ctx)
+		if sess == nil || !sess.cancelRequest(params.RequestID) {
+			// The request has most likely finished already
+			logrus.Debugf("Ignoring cancellation of unknown request %v", params.RequestID)
+			return
+		}
+		logrus.Debugf("Cancelled request %v: %s", params.RequestID, params.Reason)
+	default:
+		logrus.Debugf("Ignoring notification %s", req.Method)
+	}
+}
+
+func newErrorResponse(id interface{}, code int, message string) *JSONRPCResponse {
+	return &JSONRPCResponse{
+		JsonRPC: "2.0",
+		ID:      id,
+		Error: map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// supportedProtocolVersions lists the MCP revisions the server speaks, newest
+// first
+var supportedProtocolVersions = []string{
+	"2025-06-18",
+	"2025-03-26",
+	"2024-11-05",
+}
+
+const (
+	serverName    = "google-calendar-mcp-server"
+	serverVersion = "1.0.0"
+)
+
+// negotiateProtocolVersion picks the version to answer initialize with: the
+// client's own version if the server supports it, otherwise the newest older
+// revision the server supports. Clients older than every supported revision
+// are offered the latest one, which they may decline by disconnecting.
+func negotiateProtocolVersion(requested string) string {
+	if isSupportedProtocolVersion(requested) {
+		return requested
+	}
+	for _, version := range supportedProtocolVersions {
+		// Revisions are dates, so they order lexically
+		if version <= requested {
+			return version
+		}
+	}
+	return supportedProtocolVersions[0]
+}
+
+// isSupportedProtocolVersion reports whether version is one the server speaks
+func isSupportedProtocolVersion(version string) bool {
+	for _, supported := range supportedProtocolVersions {
+		if version == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// capabilities returns what the server advertises in its initialize result.
+// Only features that are actually served are listed.
+func (s *Server) capabilities() map[string]interface{} {
+	return map[string]interface{}{
+		"tools": map[string]interface{}{},
+		"resources": map[string]interface{}{
+			"subscribe": s.subscriptions != nil,
+		},
+		"prompts": map[string]interface{}{},
+		"logging": map[string]interface{}{},
+	}
+}
+
+// handleInitialize negotiates the protocol version and records what was
+// agreed on the session
+func (s *Server) handleInitialize(ctx context.Context, req *JSONRPCRequest) *JSONRPCResponse {
+	var params InitializeParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return newErrorResponse(req.ID, errCodeInvalidParams, fmt.Sprintf("Invalid params: %v", err))
+	}
+	if params.ProtocolVersion == "" {
+		return newErrorResponse(req.ID, errCodeInvalidParams, "Invalid params: protocolVersion is required")
+	}
+
+	version := negotiateProtocolVersion(params.ProtocolVersion)
+	capabilities := s.capabilities()
+
+	if sess := sessionFromContext(ctx); sess != nil {
+		if !sess.initialize(version, params.ClientInfo) {
+			return newErrorResponse(req.ID, errCodeInvalidRequest, "Invalid Request: session is already initialized")
+		}
+	}
+
+	return &JSONRPCResponse{
+		JsonRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    capabilities,
+			"serverInfo": map[string]interface{}{
+				"name":    serverName,
+				"version": serverVersion,
+			},
+		},
+	}
+}
+
+// allowedBeforeInitialize reports whether a method may be called on a session
+// that has not completed initialize
+func allowedBeforeInitialize(method string) bool {
+	return method == "initialize" || method == "ping"
+}
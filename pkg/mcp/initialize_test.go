@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+)
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      string
+	}{
+		{"2025-06-18", "2025-06-18"},
+		{"2025-03-26", "2025-03-26"},
+		{"2024-11-05", "2024-11-05"},
+		// Between supported revisions, the older one is offered
+		{"2025-05-01", "2025-03-26"},
+		// Newer than the server, which offers what it has
+		{"2026-01-01", "2025-06-18"},
+		// Older than everything the server speaks
+		{"2024-10-07", "2025-06-18"},
+		{"not a date", "2025-06-18"},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateProtocolVersion(tt.requested); got != tt.want {
+			t.Errorf("negotiateProtocolVersion(%q) = %q, want %q", tt.requested, got, tt.want)
+		}
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{}, 0)
+	sess := newSession(newRandomID())
+	ctx := withSession(context.Background(), sess)
+
+	// Only ping may come before initialize
+	call(t, ctx, s, "ping", "")
+	if code, msg := callError(t, ctx, s, "tools/list", ""); code != errCodeInvalidRequest || !strings.Contains(msg, "before initialize") {
+		t.Errorf("tools/list before initialize error = %d %q", code, msg)
+	}
+	if code, _ := callError(t, ctx, s, "initialize", `{"capabilities":{}}`); code != errCodeInvalidParams {
+		t.Errorf("initialize without a protocol version error = %d, want %d", code, errCodeInvalidParams)
+	}
+	if sess.Initialized() {
+		t.Fatal("session initialized by a failed initialize")
+	}
+
+	result := call(t, ctx, s, "initialize", `{"protocolVersion":"2025-03-26","capabilities":{"roots":{}},"clientInfo":{"name":"test","version":"1.0"}}`)
+	want := map[string]interface{}{
+		"protocolVersion": "2025-03-26",
+		"capabilities": map[string]interface{}{
+			"tools":     map[string]interface{}{},
+			"resources": map[string]interface{}{"subscribe": false},
+			"prompts":   map[string]interface{}{},
+			"logging":   map[string]interface{}{},
+		},
+		"serverInfo": map[string]interface{}{"name": serverName, "version": serverVersion},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("initialize = %v, want %v", result, want)
+	}
+	if got := sess.ProtocolVersion(); got != "2025-03-26" {
+		t.Errorf("ProtocolVersion() = %q, want 2025-03-26", got)
+	}
+
+	call(t, ctx, s, "tools/list", "")
+	if code, msg := callError(t, ctx, s, "initialize", `{"protocolVersion":"2025-06-18"}`); code != errCodeInvalidRequest || !strings.Contains(msg, "already initialized") {
+		t.Errorf("second initialize error = %d %q", code, msg)
+	}
+	if got := sess.ProtocolVersion(); got != "2025-03-26" {
+		t.Errorf("ProtocolVersion() after a second initialize = %q, want 2025-03-26", got)
+	}
+}
+
+func TestInitializeSubscribeCapability(t *testing.T) {
+	s := NewServer(newWatchingBackend(), &config.Config{WebhookURL: "https://example.com" + webhookPath}, 0)
+	result := call(t, context.Background(), s, "initialize", `{"protocolVersion":"2025-06-18"}`)
+	capabilities, _ := result["capabilities"].(map[string]interface{})
+	if got := capabilities["resources"]; !reflect.DeepEqual(got, map[string]interface{}{"subscribe": true}) {
+		t.Errorf("resources capability with a webhook = %v, want subscribe", got)
+	}
+}
@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// maxBatchConcurrency bounds how many calls from a single batch run at once
+const maxBatchConcurrency = 4
+
+// payloadItem is one entry of a decoded payload: either a message to
+// dispatch or the error response for an entry that could not be decoded.
+// Both are nil for JSON-RPC responses sent by the client, which are ignored.
+type payloadItem struct {
+	req     *JSONRPCRequest
+	invalid *JSONRPCResponse
+}
+
+// payload is a decoded JSON-RPC message or batch of messages
+type payload struct {
+	items []payloadItem
+	batch bool
+}
+
+// parsePayload decodes a JSON-RPC message or batch. It returns an error
+// response instead when the payload as a whole is unusable.
+func parsePayload(data []byte) (*payload, *JSONRPCResponse) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, newErrorResponse(nil, errCodeInvalidRequest, "Invalid Request: empty payload")
+	}
+
+	if data[0] != '[' {
+		if !json.Valid(data) {
+			return nil, newErrorResponse(nil, errCodeParse, "Parse error: payload is not valid JSON")
+		}
+		return &payload{items: []payloadItem{parseMessage(data)}}, nil
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, newErrorResponse(nil, errCodeParse, fmt.Sprintf("Parse error: %v", err))
+	}
+	if len(raws) == 0 {
+		return nil, newErrorResponse(nil, errCodeInvalidRequest, "Invalid Request: empty batch")
+	}
+
+	p := &payload{batch: true, items: make([]payloadItem, len(raws))}
+	for i, raw := range raws {
+		item := parseMessage(raw)
+		if item.req != nil && item.req.Method == "initialize" {
+			item = payloadItem{invalid: newErrorResponse(item.req.ID, errCodeInvalidRequest, "Invalid Request: initialize must not be part of a batch")}
+		}
+		p.items[i] = item
+	}
+	return p, nil
+}
+
+// parseMessage decodes a single JSON-RPC message that is known to be valid
+// JSON
+func parseMessage(data []byte) payloadItem {
+	var msg struct {
+		JsonRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  *string         `json:"method"`
+		Params  json.RawMessage `json:"params"`
+		Result  json.RawMessage `json:"result"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return payloadItem{invalid: newErrorResponse(nil, errCodeInvalidRequest, "Invalid Request: message must be an object")}
+	}
+
+	var id interface{}
+	if len(msg.ID) > 0 {
+		if err := json.Unmarshal(msg.ID, &id); err != nil {
+			return payloadItem{invalid: newErrorResponse(nil, errCodeInvalidRequest, "Invalid Request: malformed id")}
+		}
+		switch id.(type) {
+		case string, float64, nil:
+		default:
+			return payloadItem{invalid: newErrorResponse(nil, errCodeInvalidRequest, "Invalid Request: id must be a string or number")}
+		}
+	}
+
+	if msg.JsonRPC != "2.0" {
+		return payloadItem{invalid: newErrorResponse(id, errCodeInvalidRequest, `Invalid Request: jsonrpc must be "2.0"`)}
+	}
+
+	if msg.Method == nil {
+		// Responses to server-initiated requests carry no method; the server
+		// never sends requests, so they are dropped
+		if len(msg.ID) > 0 && (len(msg.Result) > 0 || len(msg.Error) > 0) {
+			return payloadItem{}
+		}
+		return payloadItem{invalid: newErrorResponse(id, errCodeInvalidRequest, "Invalid Request: missing method")}
+	}
+	if *msg.Method == "" {
+		return payloadItem{invalid: newErrorResponse(id, errCodeInvalidRequest, "Invalid Request: empty method")}
+	}
+
+	return payloadItem{req: &JSONRPCRequest{
+		JsonRPC:      msg.JsonRPC,
+		ID:           id,
+		Method:       *msg.Method,
+		Params:       msg.Params,
+		notification: len(msg.ID) == 0,
+	}}
+}
+
+// requests returns the dispatchable messages of the payload
+func (p *payload) requests() []*JSONRPCRequest {
+	var reqs []*JSONRPCRequest
+	for _, item := range p.items {
+		if item.req != nil {
+			reqs = append(reqs, item.req)
+		}
+	}
+	return reqs
+}
+
+// expectsResponse reports whether anything in the payload must be answered
+func (p *payload) expectsResponse() bool {
+	for _, item := range p.items {
+		if item.invalid != nil || (item.req != nil && !item.req.IsNotification()) {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch runs every message of the payload and returns what must be
+// written back: a *JSONRPCResponse, a []*JSONRPCResponse for batches, or nil
+// when nothing is to be sent. Batched messages run concurrently, but their
+// responses keep the order of the batch.
+func (s *Server) dispatch(ctx context.Context, p *payload) interface{} {
+	if !p.batch {
+		item := p.items[0]
+		if item.invalid != nil {
+			return item.invalid
+		}
+		if item.req == nil {
+			return nil
+		}
+		if response := s.handleRequest(ctx, item.req); response != nil {
+			return response
+		}
+		return nil
+	}
+
+	results := make([]*JSONRPCResponse, len(p.items))
+	sem := make(chan struct{}, maxBatchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range p.items {
+		if item.req == nil {
+			results[i] = item.invalid
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, req *JSONRPCRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.handleRequest(ctx, req)
+		}(i, item.req)
+	}
+	wg.Wait()
+
+	responses := make([]*JSONRPCResponse, 0, len(results))
+	for _, response := range results {
+		if response != nil {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		// A batch of notifications gets no reply at all
+		return nil
+	}
+	return responses
+}
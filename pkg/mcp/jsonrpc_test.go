@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+)
+
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string // "" when nothing is sent back
+	}{
+		{
+			name:    "request",
+			payload: `{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":1,"result":{}}`,
+		},
+		{
+			name:    "string id",
+			payload: `{"jsonrpc":"2.0","id":"a","method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":"a","result":{}}`,
+		},
+		{
+			name:    "notification",
+			payload: `{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		},
+		{
+			name:    "unknown notification",
+			payload: `{"jsonrpc":"2.0","method":"notifications/unknown"}`,
+		},
+		{
+			name:    "client response",
+			payload: `{"jsonrpc":"2.0","id":9,"result":{}}`,
+		},
+		{
+			name:    "unknown method",
+			payload: `{"jsonrpc":"2.0","id":1,"method":"nope"}`,
+			want:    `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found: nope"}}`,
+		},
+		{
+			name:    "not JSON",
+			payload: `{"jsonrpc":`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error: payload is not valid JSON"}}`,
+		},
+		{
+			name:    "empty",
+			payload: " \n",
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request: empty payload"}}`,
+		},
+		{
+			name:    "not an object",
+			payload: `"ping"`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request: message must be an object"}}`,
+		},
+		{
+			name:    "wrong version",
+			payload: `{"jsonrpc":"1.0","id":1,"method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"Invalid Request: jsonrpc must be \"2.0\""}}`,
+		},
+		{
+			name:    "object id",
+			payload: `{"jsonrpc":"2.0","id":{},"method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request: id must be a string or number"}}`,
+		},
+		{
+			name:    "missing method",
+			payload: `{"jsonrpc":"2.0","id":1}`,
+			want:    `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"Invalid Request: missing method"}}`,
+		},
+		{
+			name:    "empty method",
+			payload: `{"jsonrpc":"2.0","id":1,"method":""}`,
+			want:    `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"Invalid Request: empty method"}}`,
+		},
+		{
+			name:    "batch",
+			payload: `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"nope"},7,{"jsonrpc":"2.0","id":3,"method":"ping"}]`,
+			want:    `[{"jsonrpc":"2.0","id":1,"result":{}},{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"Method not found: nope"}},{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request: message must be an object"}},{"jsonrpc":"2.0","id":3,"result":{}}]`,
+		},
+		{
+			name:    "batch of notifications",
+			payload: `[{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":9,"result":{}}]`,
+		},
+		{
+			name:    "initialize in a batch",
+			payload: `[{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}},{"jsonrpc":"2.0","id":2,"method":"ping"}]`,
+			want:    `[{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"Invalid Request: initialize must not be part of a batch"}},{"jsonrpc":"2.0","id":2,"result":{}}]`,
+		},
+		{
+			name:    "empty batch",
+			payload: `[]`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request: empty batch"}}`,
+		},
+		{
+			name:    "malformed batch",
+			payload: `[{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error: unexpected end of JSON input"}}`,
+		},
+	}
+
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{}, 0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rpc(context.Background(), s, tt.payload); got != tt.want {
+				t.Errorf("dispatch(%s) = %s, want %s", tt.payload, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatchBatchOrder(t *testing.T) {
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{}, 0)
+	// The first call finishes last, but its response still comes first
+	delays := map[string]time.Duration{"first": 50 * time.Millisecond, "second": 0}
+	if err := s.Register(NewTool(Tool{Name: "wait"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+		var params struct{ Name string }
+		json.Unmarshal(args, &params)
+		time.Sleep(delays[params.Name])
+		return textResult(params.Name, nil), nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	got := rpc(context.Background(), s, `[{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"wait","arguments":{"name":"first"}}},{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wait","arguments":{"name":"second"}}}]`)
+	want := `[{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"first"}]}},{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"second"}]}}]`
+	if got != want {
+		t.Errorf("batch reply = %s, want %s", got, want)
+	}
+}
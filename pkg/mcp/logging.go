@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"context"
+	"io"
+
+	"github.com/phildougherty/mcp-google-calendar-go/internal/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultClientLogLevel is what a session receives until it calls
+// logging/setLevel
+const defaultClientLogLevel = logrus.WarnLevel
+
+// clientLogLevels maps the syslog severities MCP uses to the least severe
+// logrus level a client asking for them receives. The server never logs at
+// notice, alert or emergency, so those thresholds fall to the next level
+// that it does use.
+var clientLogLevels = map[string]logrus.Level{
+	"debug":     logrus.DebugLevel,
+	"info":      logrus.InfoLevel,
+	"notice":    logrus.WarnLevel,
+	"warning":   logrus.WarnLevel,
+	"error":     logrus.ErrorLevel,
+	"critical":  logrus.FatalLevel,
+	"alert":     logrus.FatalLevel,
+	"emergency": logrus.PanicLevel,
+}
+
+// clientLevelName returns the MCP severity a logrus level is reported as
+func clientLevelName(level logrus.Level) string {
+	switch level {
+	case logrus.PanicLevel:
+		return "emergency"
+	case logrus.FatalLevel:
+		return "critical"
+	case logrus.ErrorLevel:
+		return "error"
+	case logrus.WarnLevel:
+		return "warning"
+	case logrus.InfoLevel:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+// newSessionLogger builds the logger for a session's requests. Records are
+// copied to the operator's standard logger as before and, if severe enough,
+// sent to the session's client as notifications/message.
+func newSessionLogger(sess *Session) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(sessionLoggerLevel(defaultClientLogLevel))
+	logger.AddHook(operatorHook{})
+	logger.AddHook(&clientHook{sess: sess})
+	return logger
+}
+
+// sessionLoggerLevel is the most verbose of the operator's and the client's
+// levels, so that each hook sees every record it may want
+func sessionLoggerLevel(clientLevel logrus.Level) logrus.Level {
+	if operatorLevel := logrus.GetLevel(); operatorLevel > clientLevel {
+		return operatorLevel
+	}
+	return clientLevel
+}
+
+// withRequestLogger gives the request in ctx a logger that reaches both the
+// operator and, through its session, the client
+func withRequestLogger(ctx context.Context, req *JSONRPCRequest) context.Context {
+	sess := sessionFromContext(ctx)
+	if sess == nil {
+		return ctx
+	}
+	entry := sess.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"session":    sess.ID(),
+		"method":     req.Method,
+		"request_id": req.ID,
+	})
+	if client := sess.clientName(); client != "" {
+		entry = entry.WithField("client", client)
+	}
+	return logging.WithLogger(ctx, entry)
+}
+
+// operatorHook forwards session records to the standard logger, honouring
+// the level set with -debug
+type operatorHook struct{}
+
+func (operatorHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (operatorHook) Fire(entry *logrus.Entry) error {
+	if !logrus.IsLevelEnabled(entry.Level) {
+		return nil
+	}
+	logrus.WithFields(entry.Data).WithTime(entry.Time).Log(entry.Level, entry.Message)
+	return nil
+}
+
+// clientHook sends session records to the client as notifications/message
+type clientHook struct {
+	sess *Session
+}
+
+func (h *clientHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *clientHook) Fire(entry *logrus.Entry) error {
+	if entry.Level > h.sess.LogLevel() {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"message": entry.Message,
+	}
+	for key, value := range entry.Data {
+		switch key {
+		case "session", "request_id":
+			// Only meaningful to the operator
+			continue
+		}
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		data[key] = value
+	}
+
+	params := map[string]interface{}{
+		"level":  clientLevelName(entry.Level),
+		"logger": serverName,
+		"data":   data,
+	}
+	if entry.Context != nil {
+		notifyClient(entry.Context, "notifications/message", params)
+	} else {
+		h.sess.notify("notifications/message", params)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/phildougherty/mcp-google-calendar-go/internal/logging"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// loggedMessages returns the data of the notifications/message a session
+// received from the "chatty" tool, by level
+func loggedMessages(sink *recordingSink) map[string]map[string]interface{} {
+	out := make(map[string]map[string]interface{})
+	for _, msg := range sink.received("notifications/message") {
+		params, _ := msg["params"].(map[string]interface{})
+		data, _ := params["data"].(map[string]interface{})
+		if data["step"] != nil && params["logger"] == serverName {
+			out[params["level"].(string)] = data
+		}
+	}
+	return out
+}
+
+func TestSessionLogLevels(t *testing.T) {
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{}, 0)
+	if err := s.Register(NewTool(Tool{Name: "chatty"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+		logger := logging.FromContext(ctx).WithField("step", 1)
+		logger.Debug("looking up the calendar")
+		logger.Info("checking the calendar")
+		logger.Warn("calendar is slow")
+		logger.WithError(errors.New("boom")).Error("lookup failed")
+		return textResult("done", nil), nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each session receives what it asked for, starting at warning
+	verbose, _, verboseSink := newSessionContext(t)
+	quiet, _, quietSink := newSessionContext(t)
+	call(t, verbose, s, "logging/setLevel", `{"level":"info"}`)
+	call(t, verbose, s, "tools/call", `{"name":"chatty","arguments":{}}`)
+	call(t, quiet, s, "tools/call", `{"name":"chatty","arguments":{}}`)
+
+	got := loggedMessages(verboseSink)
+	if levels := sortedKeys(got); !reflect.DeepEqual(levels, []string{"error", "info", "warning"}) {
+		t.Errorf("info session received %q, want error, info and warning", levels)
+	}
+	if levels := sortedKeys(loggedMessages(quietSink)); !reflect.DeepEqual(levels, []string{"error", "warning"}) {
+		t.Errorf("default session received %q, want error and warning", levels)
+	}
+
+	// Records keep their fields, minus those only the operator needs
+	want := map[string]interface{}{
+		"message": "lookup failed",
+		"step":    float64(1),
+		"error":   "boom",
+		"method":  "tools/call",
+		"client":  "test/1.0",
+	}
+	for key, value := range want {
+		if got["error"][key] != value {
+			t.Errorf("error record %s = %v, want %v", key, got["error"][key], value)
+		}
+	}
+	for _, key := range []string{"session", "request_id"} {
+		if _, ok := got["error"][key]; ok {
+			t.Errorf("error record has %s: %v", key, got["error"])
+		}
+	}
+}
+
+func sortedKeys(m map[string]map[string]interface{}) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestSetLogLevel(t *testing.T) {
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{}, 0)
+	ctx, sess, _ := newSessionContext(t)
+
+	if got := sess.LogLevel(); got != logrus.WarnLevel {
+		t.Errorf("LogLevel() before setLevel = %v, want warning", got)
+	}
+	tests := []struct {
+		level string
+		want  logrus.Level
+	}{
+		{"debug", logrus.DebugLevel},
+		{"info", logrus.InfoLevel},
+		{"notice", logrus.WarnLevel},
+		{"warning", logrus.WarnLevel},
+		{"error", logrus.ErrorLevel},
+		{"critical", logrus.FatalLevel},
+		{"alert", logrus.FatalLevel},
+		{"emergency", logrus.PanicLevel},
+	}
+	for _, tt := range tests {
+		call(t, ctx, s, "logging/setLevel", fmt.Sprintf(`{"level":%q}`, tt.level))
+		if got := sess.LogLevel(); got != tt.want {
+			t.Errorf("LogLevel() after setLevel %s = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+
+	if code, _ := callError(t, ctx, s, "logging/setLevel", `{"level":"verbose"}`); code != errCodeInvalidParams {
+		t.Errorf("setLevel verbose error = %d, want %d", code, errCodeInvalidParams)
+	}
+	if code, _ := callError(t, context.Background(), s, "logging/setLevel", `{"level":"debug"}`); code != errCodeInvalidRequest {
+		t.Errorf("setLevel without a session error = %d, want %d", code, errCodeInvalidRequest)
+	}
+}
+
+func TestClientLevelName(t *testing.T) {
+	for level, want := range map[logrus.Level]string{
+		logrus.PanicLevel: "emergency",
+		logrus.FatalLevel: "critical",
+		logrus.ErrorLevel: "error",
+		logrus.WarnLevel:  "warning",
+		logrus.InfoLevel:  "info",
+		logrus.DebugLevel: "debug",
+		logrus.TraceLevel: "debug",
+	} {
+		if got := clientLevelName(level); got != want {
+			t.Errorf("clientLevelName(%v) = %q, want %q", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/internal/logging"
+)
+
+// ToolRequest is a tool call on its way through the middleware chain
+type ToolRequest struct {
+	Name      string
+	Arguments json.RawMessage
+	Tool      ToolHandler
+}
+
+// Handler handles a tool call
+type Handler func(ctx context.Context, req *ToolRequest) (*ToolResult, error)
+
+// Middleware wraps a Handler to add behaviour around every tool call, such as
+// auditing or policy checks
+type Middleware func(next Handler) Handler
+
+// chain wraps h in middleware so that the first middleware runs outermost
+func chain(h Handler, middleware ...Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// redactedValue replaces redacted argument values in logs
+const redactedValue = "[REDACTED]"
+
+// LogMiddleware logs every tool call and its outcome. Argument values of the
+// named fields are replaced at any depth, so that event contents and
+// attendee addresses stay out of the operator's logs.
+func LogMiddleware(redactFields []string) Middleware {
+	redact := make(map[string]bool, len(redactFields))
+	for _, field := range redactFields {
+		redact[strings.ToLower(field)] = true
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *ToolRequest) (*ToolResult, error) {
+			log := logging.FromContext(ctx).WithField("tool", req.Name)
+			log.WithField("arguments", redactArguments(req.Arguments, redact)).Debug("Calling tool")
+
+			start := time.Now()
+			result, err := next(ctx, req)
+			log = log.WithField("duration_ms", time.Since(start).Milliseconds())
+			switch {
+			case err != nil:
+				// Reported by the caller along with the JSON-RPC error
+			case result != nil && result.IsError:
+				log.Info("Tool returned an error result")
+			default:
+				log.Debug("Tool call completed")
+			}
+			return result, err
+		}
+	}
+}
+
+// redactArguments renders tool arguments for logging with redacted fields
+// replaced
+func redactArguments(args json.RawMessage, redact map[string]bool) string {
+	if len(args) == 0 {
+		return "{}"
+	}
+	var value interface{}
+	if err := json.Unmarshal(args, &value); err != nil {
+		return fmt.Sprintf("<%d bytes of invalid JSON>", len(args))
+	}
+	data, _ := json.Marshal(redactValue(value, redact))
+	return string(data)
+}
+
+func redactValue(value interface{}, redact map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redact[strings.ToLower(key)] {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(field, redact)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, redact)
+		}
+	}
+	return value
+}
+
+// RecoverMiddleware turns a panicking tool into a tool error, so that one
+// broken handler cannot take the server down with it
+func RecoverMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *ToolRequest) (result *ToolResult, err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					logging.FromContext(ctx).WithField("tool", req.Name).
+						WithField("stack", string(debug.Stack())).
+						Errorf("Tool panicked: %v", p)
+					result, err = errorResult(fmt.Sprintf("Tool %s failed with an internal error", req.Name)), nil
+				}
+			}()
+			return next(ctx, req)
+		}
+	}
+}
+
+// TimeoutMiddleware bounds every tool call by timeout, or by its entry in
+// perTool when there is one. A timeout of zero or less disables the bound.
+func TimeoutMiddleware(timeout time.Duration, perTool map[string]time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *ToolRequest) (*ToolResult, error) {
+			limit := timeout
+			if d, ok := perTool[req.Name]; ok {
+				limit = d
+			}
+			if limit <= 0 {
+				return next(ctx, req)
+			}
+
+			callCtx, cancel := context.WithTimeout(ctx, limit)
+			defer cancel()
+
+			result, err := next(callCtx, req)
+			if errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+				return errorResult(fmt.Sprintf("Tool %s timed out after %s", req.Name, limit)), nil
+			}
+			return result, err
+		}
+	}
+}
+
+// ToolStats summarises the calls to one tool
+type ToolStats struct {
+	Calls   int64   `json:"calls"`
+	Errors  int64   `json:"errors"`
+	TotalMs float64 `json:"totalMs"`
+	MaxMs   float64 `json:"maxMs"`
+}
+
+// ToolMetrics collects call counts and latencies per tool
+type ToolMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*ToolStats
+}
+
+func NewToolMetrics() *ToolMetrics {
+	return &ToolMetrics{stats: make(map[string]*ToolStats)}
+}
+
+func (m *ToolMetrics) record(name string, elapsed time.Duration, failed bool) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.stats[name]
+	if !ok {
+		stats = &ToolStats{}
+		m.stats[name] = stats
+	}
+	stats.Calls++
+	if failed {
+		stats.Errors++
+	}
+	stats.TotalMs += ms
+	if ms > stats.MaxMs {
+		stats.MaxMs = ms
+	}
+}
+
+// Snapshot returns a copy of the statistics of every tool called so far
+func (m *ToolMetrics) Snapshot() map[string]ToolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]ToolStats, len(m.stats))
+	for name, stats := range m.stats {
+		snapshot[name] = *stats
+	}
+	return snapshot
+}
+
+// TimingMiddleware records the duration and outcome of every tool call in
+// metrics
+func TimingMiddleware(metrics *ToolMetrics) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *ToolRequest) (*ToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, req)
+			elapsed := time.Since(start)
+
+			metrics.record(req.Name, elapsed, err != nil || (result != nil && result.IsError))
+			return result, err
+		}
+	}
+}
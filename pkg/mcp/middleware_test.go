@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+)
+
+// middlewareRegistry returns a registry with tools that panic, block until
+// cancelled, sleep, succeed and fail, wrapped in middleware
+func middlewareRegistry(t *testing.T, middleware ...Middleware) *ToolRegistry {
+	t.Helper()
+	r := NewToolRegistry(calendar.NewMemoryBackend(""), false)
+	r.Use(middleware...)
+	err := r.Register(
+		NewTool(Tool{Name: "panic"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+			panic("broken handler")
+		}),
+		NewTool(Tool{Name: "block"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}),
+		NewTool(Tool{Name: "sleep"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return textResult("slept", nil), nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}),
+		NewTool(Tool{Name: "ok"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+			return textResult("ok", nil), nil
+		}),
+		NewTool(Tool{Name: "fail"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+			return errorResult("failed"), nil
+		}),
+		NewTool(Tool{Name: "error"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+			return nil, errors.New("protocol error")
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	r := middlewareRegistry(t, RecoverMiddleware())
+
+	result, err := r.CallTool(context.Background(), "panic", nil)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v, want a tool error", err)
+	}
+	if !result.IsError {
+		t.Error("IsError = false, want true")
+	}
+	if want := "Tool panic failed with an internal error"; result.Content[0].Text != want {
+		t.Errorf("result = %q, want %q", result.Content[0].Text, want)
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		tool    string
+		perTool map[string]time.Duration
+		isError bool
+		want    string
+	}{
+		{name: "timed out", tool: "block", isError: true, want: "Tool block timed out after 10ms"},
+		{name: "within timeout", tool: "ok", want: "ok"},
+		{
+			name:    "per-tool timeout",
+			tool:    "sleep",
+			perTool: map[string]time.Duration{"sleep": time.Second},
+			want:    "slept",
+		},
+		{
+			name:    "per-tool timeout disabled",
+			tool:    "sleep",
+			perTool: map[string]time.Duration{"sleep": 0},
+			want:    "slept",
+		},
+		{
+			name:    "other tool's override",
+			tool:    "sleep",
+			perTool: map[string]time.Duration{"ok": 0},
+			isError: true,
+			want:    "Tool sleep timed out after 10ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := middlewareRegistry(t, TimeoutMiddleware(10*time.Millisecond, tt.perTool))
+			result, err := r.CallTool(context.Background(), tt.tool, nil)
+			if err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			if result.IsError != tt.isError {
+				t.Errorf("IsError = %v, want %v", result.IsError, tt.isError)
+			}
+			if got := result.Content[0].Text; got != tt.want {
+				t.Errorf("result = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareCancelled(t *testing.T) {
+	r := middlewareRegistry(t, TimeoutMiddleware(time.Second, nil))
+
+	// A call cancelled by the client is not reported as a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.CallTool(ctx, "block", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("CallTool() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRedactArguments(t *testing.T) {
+	redact := map[string]bool{"description": true, "attendees": true}
+
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{name: "no arguments", args: ``, want: `{}`},
+		{name: "top level", args: `{"description":"secret","summary":"Lunch"}`, want: `{"description":"[REDACTED]","summary":"Lunch"}`},
+		{name: "any case", args: `{"Description":"secret","ATTENDEES":["a@example.com"]}`, want: `{"ATTENDEES":"[REDACTED]","Description":"[REDACTED]"}`},
+		{
+			name: "nested",
+			args: `{"event":{"description":"secret","recurrence":[{"attendees":"x","count":2}]}}`,
+			want: `{"event":{"description":"[REDACTED]","recurrence":[{"attendees":"[REDACTED]","count":2}]}}`,
+		},
+		{name: "invalid JSON", args: `{"description":`, want: `<15 bytes of invalid JSON>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactArguments(json.RawMessage(tt.args), redact); got != tt.want {
+				t.Errorf("redactArguments(%s) = %s, want %s", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimingMiddleware(t *testing.T) {
+	metrics := NewToolMetrics()
+	r := middlewareRegistry(t, TimingMiddleware(metrics), RecoverMiddleware())
+
+	for _, name := range []string{"ok", "ok", "ok", "fail", "panic", "error", "sleep"} {
+		r.CallTool(context.Background(), name, nil)
+	}
+
+	want := map[string]ToolStats{
+		"ok":    {Calls: 3},
+		"fail":  {Calls: 1, Errors: 1},
+		"panic": {Calls: 1, Errors: 1},
+		"error": {Calls: 1, Errors: 1},
+		"sleep": {Calls: 1},
+	}
+	got := metrics.Snapshot()
+	if len(got) != len(want) {
+		t.Errorf("Snapshot() has %d tools, want %d: %v", len(got), len(want), got)
+	}
+	for name, w := range want {
+		g := got[name]
+		if g.Calls != w.Calls || g.Errors != w.Errors {
+			t.Errorf("%s: %d calls, %d errors; want %d calls, %d errors", name, g.Calls, g.Errors, w.Calls, w.Errors)
+		}
+		if g.MaxMs > g.TotalMs {
+			t.Errorf("%s: max %vms exceeds total %vms", name, g.MaxMs, g.TotalMs)
+		}
+	}
+	if sleep := got["sleep"]; sleep.MaxMs < 50 {
+		t.Errorf("sleep: max %vms, want at least 50ms", sleep.MaxMs)
+	}
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+)
+
+// requestMeta is the _meta object a client may attach to any request's
+// params
+type requestMeta struct {
+	Meta struct {
+		ProgressToken interface{} `json:"progressToken"`
+	} `json:"_meta"`
+}
+
+// progressToken returns the token the client asked progress to be reported
+// under, or nil if it did not ask for progress
+func progressToken(params json.RawMessage) interface{} {
+	var meta requestMeta
+	if len(params) == 0 || json.Unmarshal(params, &meta) != nil {
+		return nil
+	}
+	switch token := meta.Meta.ProgressToken.(type) {
+	case string, float64:
+		return token
+	}
+	return nil
+}
+
+// progressReporter turns calendar progress callbacks into
+// notifications/progress messages. A request may run several operations
+// that each count from zero, so later operations are offset past earlier
+// ones to keep the reported progress increasing.
+type progressReporter struct {
+	ctx   context.Context
+	token interface{}
+
+	mu   sync.Mutex
+	base float64
+	last float64
+}
+
+// withProgress arranges for calendar operations run under ctx to report
+// progress to the client under token
+func withProgress(ctx context.Context, token interface{}) context.Context {
+	p := &progressReporter{ctx: ctx, token: token}
+	return calendar.WithProgress(ctx, p.report)
+}
+
+func (p *progressReporter) report(progress, total float64, message string) {
+	p.mu.Lock()
+	if p.base+progress <= p.last {
+		p.base = p.last
+	}
+	progress += p.base
+	if total > 0 {
+		total += p.base
+	}
+	p.last = progress
+	p.mu.Unlock()
+
+	params := map[string]interface{}{
+		"progressToken": p.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	notifyClient(p.ctx, "notifications/progress", params)
+}
@@ -0,0 +1,192 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressToken(t *testing.T) {
+	tests := []struct {
+		params string
+		want   interface{}
+	}{
+		{`{"_meta":{"progressToken":"abc"}}`, "abc"},
+		{`{"_meta":{"progressToken":7}}`, float64(7)},
+		{`{"name":"list_events","_meta":{}}`, nil},
+		{`{"_meta":{"progressToken":{"id":1}}}`, nil},
+		{`{"_meta":"abc"}`, nil},
+		{``, nil},
+	}
+
+	for _, tt := range tests {
+		if got := progressToken(json.RawMessage(tt.params)); got != tt.want {
+			t.Errorf("progressToken(%s) = %v, want %v", tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestProgressReporter(t *testing.T) {
+	sink := &recordingSink{}
+	p := &progressReporter{ctx: withRequestSink(context.Background(), sink), token: "tok"}
+
+	// The second operation counts from zero again
+	p.report(1, 3, "first page")
+	p.report(3, 3, "")
+	p.report(1, 2, "second operation")
+	p.report(2, 2, "")
+
+	var got []map[string]interface{}
+	for _, msg := range sink.received("notifications/progress") {
+		got = append(got, msg["params"].(map[string]interface{}))
+	}
+	want := []map[string]interface{}{
+		{"progressToken": "tok", "progress": float64(1), "total": float64(3), "message": "first page"},
+		{"progressToken": "tok", "progress": float64(3), "total": float64(3)},
+		{"progressToken": "tok", "progress": float64(4), "total": float64(5), "message": "second operation"},
+		{"progressToken": "tok", "progress": float64(5), "total": float64(5)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("progress notifications = %v, want %v", got, want)
+	}
+}
+
+func TestProgressNotifications(t *testing.T) {
+	s, ts := newTestServer(t, nil, nil)
+	ctx, _, stream := newSessionContext(t)
+	listEvents := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_events","arguments":{},"_meta":{"progressToken":"p1"}}}`
+	want := map[string]interface{}{"progressToken": "p1", "progress": float64(1), "message": "Fetched 0 events (page 1)"}
+
+	// Progress goes out with the response when it has a stream of its own,
+	// and on the session's stream otherwise
+	response := &recordingSink{}
+	rpc(withRequestSink(ctx, response), s, listEvents)
+	if got := response.received("notifications/progress"); len(got) != 1 || !reflect.DeepEqual(got[0]["params"], want) {
+		t.Errorf("progress on the response stream = %v, want %v", got, want)
+	}
+	rpc(ctx, s, listEvents)
+	if got := stream.received("notifications/progress"); len(got) != 1 || !reflect.DeepEqual(got[0]["params"], want) {
+		t.Errorf("progress on the session stream = %v, want %v", got, want)
+	}
+
+	// Without a token there is nothing to report under
+	rpc(withRequestSink(ctx, response), s, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"list_events","arguments":{}}}`)
+	if got := len(response.received("notifications/progress")); got != 1 {
+		t.Errorf("got %d progress notifications after a call without a token, want 1", got)
+	}
+
+	// Over HTTP, the event stream carries progress before the result
+	id := initializeSession(t, ts)
+	resp := do(t, ts, http.MethodPost, listEvents, map[string]string{sessionHeader: id, "Accept": "application/json, text/event-stream"})
+	events := bufio.NewReader(resp.Body)
+	if msg := readEvent(t, events); msg["method"] != "notifications/progress" || !reflect.DeepEqual(msg["params"], want) {
+		t.Errorf("first event = %v, want progress", msg)
+	}
+	if msg := readEvent(t, events); msg["id"] != float64(2) || msg["result"] == nil {
+		t.Errorf("second event = %v, want the result", msg)
+	}
+}
+
+// blockingTool returns a tool that runs until its context is done, after
+// signalling on started, and records why it stopped on cause
+func blockingTool(started chan<- struct{}, cause chan<- error) ToolHandler {
+	return NewTool(Tool{Name: "block"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		cause <- context.Cause(ctx)
+		return nil, ctx.Err()
+	})
+}
+
+func TestCancelRequest(t *testing.T) {
+	s, _ := newTestServer(t, nil, nil)
+	started, cause := make(chan struct{}), make(chan error, 1)
+	if err := s.Register(blockingTool(started, cause)); err != nil {
+		t.Fatal(err)
+	}
+	ctx, _, _ := newSessionContext(t)
+
+	reply := make(chan string, 1)
+	go func() {
+		reply <- rpc(ctx, s, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+	}()
+	<-started
+
+	// Request IDs of different types are different requests
+	for _, payload := range []string{
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"7"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":8}}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{}}`,
+	} {
+		if got := rpc(ctx, s, payload); got != "" {
+			t.Errorf("%s got reply %s", payload, got)
+		}
+	}
+	select {
+	case got := <-reply:
+		t.Fatalf("request finished before it was cancelled: %s", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	rpc(ctx, s, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user gave up"}}`)
+	select {
+	case got := <-reply:
+		if got != "" {
+			t.Errorf("cancelled request got reply %s, want none", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("request was not cancelled")
+	}
+	if err := <-cause; !errors.Is(err, errRequestCancelled) {
+		t.Errorf("tool stopped because of %v, want errRequestCancelled", err)
+	}
+}
+
+func TestCancelRequestHTTP(t *testing.T) {
+	s, ts := newTestServer(t, nil, nil)
+	started, cause := make(chan struct{}), make(chan error, 1)
+	if err := s.Register(blockingTool(started, cause)); err != nil {
+		t.Fatal(err)
+	}
+	id := initializeSession(t, ts)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/", strings.NewReader(`{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"block","arguments":{}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(sessionHeader, id)
+	replies := make(chan string, 1)
+	go func() {
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			replies <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		replies <- fmt.Sprintf("%d %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}()
+	<-started
+
+	if resp := post(t, ts, id, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1"}}`); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notifications/cancelled status = %d, want 202", resp.StatusCode)
+	}
+	select {
+	case reply := <-replies:
+		if reply != "202 " {
+			t.Errorf("cancelled request got %q, want 202 without a body", reply)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("request was not cancelled")
+	}
+	<-cause
+}
@@ -0,0 +1,452 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// errPromptArguments is returned when a prompt is requested with missing or
+// malformed arguments
+var errPromptArguments = errors.New("invalid prompt arguments")
+
+// PromptTemplate is a prompt definition. Template is a text/template whose
+// dot is the map of string arguments; the functions in funcs give it
+// access to the calendar. Teams can add their own as JSON files in the
+// prompts directory of the config directory.
+type PromptTemplate struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+	Template    string           `json:"template"`
+
+	tmpl *template.Template
+}
+
+type PromptRegistry struct {
+	backend calendar.Backend
+	prompts map[string]*PromptTemplate
+}
+
+func NewPromptRegistry(backend calendar.Backend, promptsDir string) *PromptRegistry {
+	registry := &PromptRegistry{
+		backend: backend,
+		prompts: make(map[string]*PromptTemplate),
+	}
+
+	registry.registerPrompts()
+	if promptsDir != "" {
+		registry.loadPrompts(promptsDir)
+	}
+	return registry
+}
+
+func (r *PromptRegistry) registerPrompts() {
+	for _, prompt := range builtinPrompts {
+		p := prompt
+		if err := r.register(&p); err != nil {
+			// Built-in templates are fixed at compile time
+			panic(err)
+		}
+	}
+}
+
+// loadPrompts registers every *.json prompt template in dir. A template with
+// the name of a built-in replaces it.
+func (r *PromptRegistry) loadPrompts(dir string) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(paths) == 0 {
+		return
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logrus.Warnf("Failed to read prompt template %s: %v", path, err)
+			continue
+		}
+
+		var prompt PromptTemplate
+		if err := json.Unmarshal(data, &prompt); err != nil {
+			logrus.Warnf("Failed to parse prompt template %s: %v", path, err)
+			continue
+		}
+		if err := r.register(&prompt); err != nil {
+			logrus.Warnf("Invalid prompt template %s: %v", path, err)
+			continue
+		}
+		logrus.Debugf("Loaded prompt template %s from %s", prompt.Name, path)
+	}
+}
+
+func (r *PromptRegistry) register(prompt *PromptTemplate) error {
+	if prompt.Name == "" {
+		return fmt.Errorf("prompt name is required")
+	}
+
+	// Optional arguments the client leaves out render as empty strings. The
+	// calendar functions are rebound to the caller's context on every render.
+	tmpl, err := template.New(prompt.Name).Option("missingkey=zero").Funcs(r.funcs(context.Background())).Parse(prompt.Template)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	prompt.tmpl = tmpl
+
+	r.prompts[prompt.Name] = prompt
+	return nil
+}
+
+func (r *PromptRegistry) ListPrompts() []Prompt {
+	prompts := make([]Prompt, 0, len(r.prompts))
+	for _, prompt := range r.prompts {
+		prompts = append(prompts, Prompt{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			Arguments:   prompt.Arguments,
+		})
+	}
+	sort.Slice(prompts, func(i, j int) bool {
+		return prompts[i].Name < prompts[j].Name
+	})
+	return prompts
+}
+
+// GetPrompt renders the named prompt with the given arguments, pulling
+// whatever calendar data its template asks for
+func (r *PromptRegistry) GetPrompt(ctx context.Context, name string, args map[string]string) (*GetPromptResult, error) {
+	prompt, exists := r.prompts[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: unknown prompt: %s", errPromptArguments, name)
+	}
+
+	if args == nil {
+		args = make(map[string]string)
+	}
+	for _, arg := range prompt.Arguments {
+		if arg.Required && args[arg.Name] == "" {
+			return nil, fmt.Errorf("%w: missing required argument %q", errPromptArguments, arg.Name)
+		}
+	}
+
+	tmpl, err := prompt.tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", name, err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Funcs(r.funcs(ctx)).Execute(&b, args); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", name, err)
+	}
+
+	return &GetPromptResult{
+		Description: prompt.Description,
+		Messages: []PromptMessage{{
+			Role: "user",
+			Content: Content{
+				Type: "text",
+				Text: strings.TrimSpace(b.String()),
+			},
+		}},
+	}, nil
+}
+
+// funcs returns the template functions available to prompt templates.
+// Dates are YYYY-MM-DD and interpreted in the calendar's time zone; date
+// ranges include their last day. Calendar lookups run under ctx.
+func (r *PromptRegistry) funcs(ctx context.Context) template.FuncMap {
+	return template.FuncMap{
+		"today": func() string {
+			return time.Now().Format("2006-01-02")
+		},
+		"addDays": func(date string, days int) (string, error) {
+			t, err := parseDate(date)
+			if err != nil {
+				return "", err
+			}
+			return t.AddDate(0, 0, days).Format("2006-01-02"), nil
+		},
+		"default": func(def, value string) string {
+			if value == "" {
+				return def
+			}
+			return value
+		},
+		"split": func(value string) []string {
+			return splitList(value)
+		},
+		"json": func(v interface{}) (string, error) {
+			data, err := json.MarshalIndent(v, "", "  ")
+			return string(data), err
+		},
+		"events": func(calendarID, first, last string) ([]*types.CalendarEvent, error) {
+			return r.templateEvents(ctx, calendarID, first, last)
+		},
+		"upcoming": func(calendarID string, n int) ([]*types.CalendarEvent, error) {
+			return r.templateUpcoming(ctx, calendarID, n)
+		},
+		"freebusy": func(first, last string, calendarIDs ...string) (*types.FreeBusyResponse, error) {
+			return r.templateFreeBusy(ctx, first, last, calendarIDs...)
+		},
+		"agenda": func(calendarID, first, last string) (string, error) {
+			return r.templateAgenda(ctx, calendarID, first, last)
+		},
+	}
+}
+
+// templateEvents lists a calendar's events from the start of first to the end
+// of last, in start order
+func (r *PromptRegistry) templateEvents(ctx context.Context, calendarID, first, last string) ([]*types.CalendarEvent, error) {
+	cal, err := r.backend.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	timeMin, timeMax, err := dateRange(first, last, calendarLocation(cal))
+	if err != nil {
+		return nil, err
+	}
+
+	events, _, err := r.backend.ListEvents(ctx, &types.ListEventsArgs{
+		CalendarID: calendarID,
+		TimeMin:    timeMin.Format(time.RFC3339),
+		TimeMax:    timeMax.Format(time.RFC3339),
+		MaxResults: 250,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventStart(events[i]) < eventStart(events[j])
+	})
+	return events, nil
+}
+
+// templateUpcoming returns up to n events starting within the next week
+func (r *PromptRegistry) templateUpcoming(ctx context.Context, calendarID string, n int) ([]*types.CalendarEvent, error) {
+	now := time.Now()
+	events, _, err := r.backend.ListEvents(ctx, &types.ListEventsArgs{
+		CalendarID: calendarID,
+		TimeMin:    now.Format(time.RFC3339),
+		TimeMax:    now.AddDate(0, 0, 7).Format(time.RFC3339),
+		MaxResults: 250,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The time range also matches events already in progress
+	upcoming := make([]*types.CalendarEvent, 0, len(events))
+	for _, event := range events {
+		if event.AllDay {
+			continue
+		}
+		if start, err := time.Parse(time.RFC3339, event.StartTime); err == nil && start.After(now) {
+			upcoming = append(upcoming, event)
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return eventStart(upcoming[i]) < eventStart(upcoming[j])
+	})
+	if len(upcoming) > n {
+		upcoming = upcoming[:n]
+	}
+	return upcoming, nil
+}
+
+// templateFreeBusy returns the busy intervals of the given calendars or
+// attendees between the start of first and the end of last
+func (r *PromptRegistry) templateFreeBusy(ctx context.Context, first, last string, calendarIDs ...string) (*types.FreeBusyResponse, error) {
+	timeMin, timeMax, err := dateRange(first, last, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, id := range calendarIDs {
+		ids = append(ids, splitList(id)...)
+	}
+	if len(ids) == 0 {
+		ids = []string{"primary"}
+	}
+
+	return r.backend.GetFreeBusy(ctx, &types.FreeBusyArgs{
+		TimeMin:     timeMin.Format(time.RFC3339),
+		TimeMax:     timeMax.Format(time.RFC3339),
+		CalendarIDs: ids,
+	})
+}
+
+// templateAgenda renders a calendar's events between two dates as a
+// markdown list, one heading per day
+func (r *PromptRegistry) templateAgenda(ctx context.Context, calendarID, first, last string) (string, error) {
+	cal, err := r.backend.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return "", err
+	}
+	loc := calendarLocation(cal)
+
+	start, end, err := dateRange(first, last, loc)
+	if err != nil {
+		return "", err
+	}
+	events, err := r.templateEvents(ctx, calendarID, first, last)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		var dayEvents []*types.CalendarEvent
+		for _, event := range events {
+			if eventOnDay(event, day, loc) {
+				dayEvents = append(dayEvents, event)
+			}
+		}
+		b.WriteString(renderAgenda(cal, day, loc, dayEvents))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// eventOnDay reports whether event overlaps the day starting at day
+func eventOnDay(event *types.CalendarEvent, day time.Time, loc *time.Location) bool {
+	next := day.AddDate(0, 0, 1)
+	if event.AllDay {
+		start, err := time.ParseInLocation("2006-01-02", event.StartDate, loc)
+		if err != nil {
+			return false
+		}
+		end, err := time.ParseInLocation("2006-01-02", event.EndDate, loc)
+		if err != nil {
+			end = start.AddDate(0, 0, 1)
+		}
+		return start.Before(next) && end.After(day)
+	}
+
+	start, err := time.Parse(time.RFC3339, event.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(time.RFC3339, event.EndTime)
+	if err != nil || !end.After(start) {
+		return !start.Before(day) && start.Before(next)
+	}
+	return start.Before(next) && end.After(day)
+}
+
+func parseDate(date string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q, expected YYYY-MM-DD", errPromptArguments, date)
+	}
+	return t, nil
+}
+
+// dateRange converts an inclusive range of dates into the instants bounding
+// it in loc
+func dateRange(first, last string, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02", first, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid date %q, expected YYYY-MM-DD", errPromptArguments, first)
+	}
+	end, err := time.ParseInLocation("2006-01-02", last, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid date %q, expected YYYY-MM-DD", errPromptArguments, last)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %s is before %s", errPromptArguments, last, first)
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// splitList splits a comma-separated argument into its trimmed, non-empty
+// parts
+func splitList(value string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// builtinPrompts are always available; prompt files can override them by
+// name
+var builtinPrompts = []PromptTemplate{
+	{
+		Name:        "plan_my_week",
+		Description: "Review a week of the calendar and suggest how to plan it",
+		Arguments: []PromptArgument{
+			{Name: "startDate", Description: "First day of the week (YYYY-MM-DD, defaults to today)"},
+			{Name: "endDate", Description: "Last day to plan (YYYY-MM-DD, defaults to six days after startDate)"},
+			{Name: "calendarId", Description: "Calendar ID (defaults to primary calendar)"},
+		},
+		Template: `{{- $calendar := default "primary" .calendarId -}}
+{{- $start := default today .startDate -}}
+{{- $end := .endDate -}}{{- if not $end -}}{{- $end = addDays $start 6 -}}{{- end -}}
+Help me plan my week from {{$start}} to {{$end}}.
+
+Here is what is already on my calendar:
+
+{{agenda $calendar $start $end}}
+Please:
+1. Summarise the main commitments and any days that look overloaded.
+2. Point out conflicts, back-to-back meetings without breaks, and events missing a location or agenda.
+3. Suggest focus blocks for deep work in the gaps, and anything I should move or decline.`,
+	},
+	{
+		Name:        "prepare_for_next_meeting",
+		Description: "Brief me on my next meeting so I can prepare for it",
+		Arguments: []PromptArgument{
+			{Name: "calendarId", Description: "Calendar ID (defaults to primary calendar)"},
+		},
+		Template: `{{- $calendar := default "primary" .calendarId -}}
+{{- $next := upcoming $calendar 1 -}}
+{{- if not $next -}}
+I have no meetings in the next seven days. Tell me so, and suggest how to use the open time.
+{{- else -}}
+{{- $event := index $next 0 -}}
+Help me prepare for my next meeting:
+
+{{json $event}}
+
+Please:
+1. Summarise what the meeting is about and who is attending.
+2. List questions I should be ready to answer and anything I should read beforehand.
+3. Draft a short agenda if the description does not include one.
+{{- end}}`,
+	},
+	{
+		Name:        "find_slot",
+		Description: "Find a meeting slot that works for a group of people",
+		Arguments: []PromptArgument{
+			{Name: "attendees", Description: "Comma-separated attendee email addresses", Required: true},
+			{Name: "duration", Description: "Meeting length in minutes (defaults to 30)"},
+			{Name: "startDate", Description: "First day to consider (YYYY-MM-DD, defaults to today)"},
+			{Name: "endDate", Description: "Last day to consider (YYYY-MM-DD, defaults to four days after startDate)"},
+			{Name: "calendarId", Description: "My calendar ID (defaults to primary calendar)"},
+		},
+		Template: `{{- $calendar := default "primary" .calendarId -}}
+{{- $start := default today .startDate -}}
+{{- $end := .endDate -}}{{- if not $end -}}{{- $end = addDays $start 4 -}}{{- end -}}
+Find a {{default "30" .duration}}-minute slot between {{$start}} and {{$end}} when I and these people are all free: {{.attendees}}.
+
+Busy intervals for each calendar (RFC3339, UTC unless noted):
+
+{{json (freebusy $start $end $calendar .attendees)}}
+
+Please propose the three best slots during normal working hours, avoiding early mornings, late afternoons and back-to-back stretches. If a calendar reports errors or no data, say so rather than assuming that person is free.`,
+	},
+}
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// newPromptServer returns a server over a calendar with one event on
+// 2025-01-06, loading prompt templates from files, if any
+func newPromptServer(t *testing.T, files map[string]string) *Server {
+	t.Helper()
+	b := calendar.NewMemoryBackend("")
+	if _, err := b.CreateEvent(context.Background(), &types.CreateEventArgs{
+		Summary:   "Planning",
+		StartTime: "2025-01-06T10:00:00Z",
+		EndTime:   "2025-01-06T11:00:00Z",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	if files != nil {
+		cfg.ConfigDir = t.TempDir()
+		if err := os.MkdirAll(cfg.PromptsDir(), 0700); err != nil {
+			t.Fatal(err)
+		}
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(cfg.PromptsDir(), name), []byte(data), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return NewServer(b, cfg, 0)
+}
+
+// promptText renders a prompt and returns the text of its only message
+func promptText(t *testing.T, s *Server, name, arguments string) string {
+	t.Helper()
+	result := call(t, context.Background(), s, "prompts/get", fmt.Sprintf(`{"name":%q,"arguments":%s}`, name, arguments))
+	messages, _ := result["messages"].([]interface{})
+	if len(messages) != 1 {
+		t.Fatalf("prompts/get %s = %v, want one message", name, result)
+	}
+	message, _ := messages[0].(map[string]interface{})
+	content, _ := message["content"].(map[string]interface{})
+	if message["role"] != "user" || content["type"] != "text" {
+		t.Errorf("prompts/get %s message = %v, want user text", name, message)
+	}
+	text, _ := content["text"].(string)
+	return text
+}
+
+func promptNames(t *testing.T, s *Server) []string {
+	t.Helper()
+	var names []string
+	for _, prompt := range call(t, context.Background(), s, "prompts/list", "")["prompts"].([]interface{}) {
+		names = append(names, prompt.(map[string]interface{})["name"].(string))
+	}
+	return names
+}
+
+func TestBuiltinPrompts(t *testing.T) {
+	s := newPromptServer(t, nil)
+	if got, want := promptNames(t, s), []string{"find_slot", "plan_my_week", "prepare_for_next_meeting"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("prompts/list = %q, want %q", got, want)
+	}
+
+	text := promptText(t, s, "plan_my_week", `{"startDate":"2025-01-06","endDate":"2025-01-07"}`)
+	if !strings.HasPrefix(text, "Help me plan my week from 2025-01-06 to 2025-01-07.") || !strings.Contains(text, "Planning") {
+		t.Errorf("plan_my_week = %q, want the week with Planning", text)
+	}
+
+	text = promptText(t, s, "find_slot", `{"attendees":"bob@example.com, carol@example.com","duration":"45","startDate":"2025-01-06"}`)
+	if !strings.HasPrefix(text, "Find a 45-minute slot between 2025-01-06 and 2025-01-10 when I and these people are all free: bob@example.com, carol@example.com.") {
+		t.Errorf("find_slot = %q", text)
+	}
+	for _, calendarID := range []string{"primary", "bob@example.com", "carol@example.com"} {
+		if !strings.Contains(text, fmt.Sprintf("%q", calendarID)) {
+			t.Errorf("find_slot free/busy is missing %s: %q", calendarID, text)
+		}
+	}
+
+	// The only event is long past
+	text = promptText(t, s, "prepare_for_next_meeting", `{}`)
+	if !strings.HasPrefix(text, "I have no meetings in the next seven days.") {
+		t.Errorf("prepare_for_next_meeting = %q, want no meetings", text)
+	}
+}
+
+func TestGetPromptErrors(t *testing.T) {
+	s := newPromptServer(t, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		params  string
+		wantMsg string
+	}{
+		{"missing argument", `{"name":"find_slot","arguments":{"duration":"30"}}`, `missing required argument "attendees"`},
+		{"unknown prompt", `{"name":"nope"}`, "unknown prompt: nope"},
+		{"no name", `{}`, "name is required"},
+		{"bad date", `{"name":"plan_my_week","arguments":{"startDate":"next monday"}}`, `invalid date "next monday"`},
+		{"backwards range", `{"name":"plan_my_week","arguments":{"startDate":"2025-01-06","endDate":"2025-01-01"}}`, "2025-01-01 is before 2025-01-06"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, msg := callError(t, ctx, s, "prompts/get", tt.params)
+			if code != errCodeInvalidParams || !strings.Contains(msg, tt.wantMsg) {
+				t.Errorf("prompts/get error = %d %q, want %d containing %q", code, msg, errCodeInvalidParams, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestCustomPrompts(t *testing.T) {
+	s := newPromptServer(t, map[string]string{
+		"standup.json": `{
+			"name": "standup",
+			"description": "Run the team standup",
+			"arguments": [{"name": "team", "required": true}],
+			"template": "Standup for {{.team}}{{if .focus}} on {{.focus}}{{end}}:\n{{agenda \"primary\" \"2025-01-06\" \"2025-01-06\"}}"
+		}`,
+		"plan_my_week.json": `{"name": "plan_my_week", "template": "My own plan from {{.startDate}}"}`,
+		"unparsable.json":   `{"name": "unparsable", "template": "{{if}}"}`,
+		"nameless.json":     `{"template": "Hello"}`,
+		"invalid.json":      `not JSON`,
+		"ignored.txt":       `{"name": "ignored", "template": "Hello"}`,
+	})
+
+	if got, want := promptNames(t, s), []string{"find_slot", "plan_my_week", "prepare_for_next_meeting", "standup"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("prompts/list = %q, want %q", got, want)
+	}
+
+	text := promptText(t, s, "standup", `{"team":"Platform"}`)
+	if !strings.HasPrefix(text, "Standup for Platform:\n") || !strings.Contains(text, "Planning") {
+		t.Errorf("standup = %q, want the team and the day's agenda", text)
+	}
+	if text := promptText(t, s, "plan_my_week", `{"startDate":"2025-01-06"}`); text != "My own plan from 2025-01-06" {
+		t.Errorf("overridden plan_my_week = %q", text)
+	}
+}
@@ -0,0 +1,235 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// resourceScheme prefixes every resource URI the server hands out
+const resourceScheme = "gcal://"
+
+// errResourceNotFound is returned for URIs that do not name a resource
+var errResourceNotFound = errors.New("resource not found")
+
+type ResourceRegistry struct {
+	backend   calendar.Backend
+	templates []ResourceTemplate
+}
+
+func NewResourceRegistry(backend calendar.Backend) *ResourceRegistry {
+	return &ResourceRegistry{
+		backend: backend,
+		templates: []ResourceTemplate{
+			{
+				URITemplate: "gcal://calendars/{calendarId}",
+				Name:        "Calendar",
+				Description: "Metadata for a calendar",
+				MimeType:    "application/json",
+			},
+			{
+				URITemplate: "gcal://calendars/{calendarId}/events/{eventId}",
+				Name:        "Calendar event",
+				Description: "A single event from a calendar",
+				MimeType:    "application/json",
+			},
+			{
+				URITemplate: "gcal://calendars/{calendarId}/agenda/{date}",
+				Name:        "Daily agenda",
+				Description: "Events of one day (YYYY-MM-DD or 'today') in the calendar's time zone",
+				MimeType:    "text/markdown",
+			},
+		},
+	}
+}
+
+// ListResources exposes every calendar, along with its agenda for today
+func (r *ResourceRegistry) ListResources(ctx context.Context) ([]Resource, error) {
+	calendars, _, err := r.backend.ListCalendars(ctx, &types.ListCalendarsArgs{})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]Resource, 0, 2*len(calendars))
+	for _, cal := range calendars {
+		uri := calendarURI(cal.ID)
+		resources = append(resources, Resource{
+			URI:         uri,
+			Name:        cal.Summary,
+			Description: cal.Description,
+			MimeType:    "application/json",
+		}, Resource{
+			URI:         uri + "/agenda/today",
+			Name:        fmt.Sprintf("%s: today's agenda", cal.Summary),
+			Description: fmt.Sprintf("Today's events on %s", cal.Summary),
+			MimeType:    "text/markdown",
+		})
+	}
+	return resources, nil
+}
+
+func (r *ResourceRegistry) ListTemplates() []ResourceTemplate {
+	return r.templates
+}
+
+// ReadResource renders the resource named by uri
+func (r *ResourceRegistry) ReadResource(ctx context.Context, uri string) (*ResourceContents, error) {
+	calendarID, rest, err := parseResourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(rest) == 0:
+		cal, err := r.backend.GetCalendar(ctx, calendarID)
+		if err != nil {
+			return nil, err
+		}
+		return jsonContents(uri, cal)
+
+	case len(rest) == 2 && rest[0] == "events":
+		event, err := r.backend.GetEvent(ctx, calendarID, rest[1])
+		if err != nil {
+			return nil, err
+		}
+		return jsonContents(uri, event)
+
+	case len(rest) == 2 && rest[0] == "agenda":
+		return r.readAgenda(ctx, uri, calendarID, rest[1])
+	}
+
+	return nil, fmt.Errorf("%w: %s", errResourceNotFound, uri)
+}
+
+func (r *ResourceRegistry) readAgenda(ctx context.Context, uri, calendarID, date string) (*ResourceContents, error) {
+	cal, err := r.backend.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	loc := calendarLocation(cal)
+
+	var day time.Time
+	if date == "today" {
+		now := time.Now().In(loc)
+		day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	} else if day, err = time.ParseInLocation("2006-01-02", date, loc); err != nil {
+		return nil, fmt.Errorf("%w: invalid agenda date %q, expected YYYY-MM-DD or 'today'", errResourceNotFound, date)
+	}
+
+	events, _, err := r.backend.ListEvents(ctx, &types.ListEventsArgs{
+		CalendarID: calendarID,
+		TimeMin:    day.Format(time.RFC3339),
+		TimeMax:    day.AddDate(0, 0, 1).Format(time.RFC3339),
+		MaxResults: 250,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceContents{
+		URI:      uri,
+		MimeType: "text/markdown",
+		Text:     renderAgenda(cal, day, loc, events),
+	}, nil
+}
+
+// calendarLocation returns the calendar's time zone, falling back to UTC
+func calendarLocation(cal *types.Calendar) *time.Location {
+	if cal.TimeZone != "" {
+		if loc, err := time.LoadLocation(cal.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// renderAgenda formats a day's events as a markdown list in start order
+func renderAgenda(cal *types.Calendar, day time.Time, loc *time.Location, events []*types.CalendarEvent) string {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventStart(events[i]) < eventStart(events[j])
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: %s\n\n", cal.Summary, day.Format("Monday, January 2, 2006"))
+	if len(events) == 0 {
+		b.WriteString("No events.\n")
+		return b.String()
+	}
+
+	for _, event := range events {
+		when := "All day"
+		if !event.AllDay {
+			when = formatClock(event.StartTime, loc) + "–" + formatClock(event.EndTime, loc)
+		}
+		fmt.Fprintf(&b, "- **%s** %s", when, event.Summary)
+		if event.Location != "" {
+			fmt.Fprintf(&b, " (%s)", event.Location)
+		}
+		fmt.Fprintf(&b, " `%s`\n", event.ID)
+	}
+	return b.String()
+}
+
+// eventStart returns a sortable start key; all-day events sort first
+func eventStart(event *types.CalendarEvent) string {
+	if event.AllDay {
+		return event.StartDate
+	}
+	if t, err := time.Parse(time.RFC3339, event.StartTime); err == nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return event.StartTime
+}
+
+func formatClock(value string, loc *time.Location) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.In(loc).Format("15:04")
+}
+
+func jsonContents(uri string, v interface{}) (*ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &ResourceContents{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(data),
+	}, nil
+}
+
+// calendarURI builds the resource URI for a calendar. Calendar IDs routinely
+// contain '@' and '#', so they are path-escaped.
+func calendarURI(calendarID string) string {
+	return resourceScheme + "calendars/" + url.PathEscape(calendarID)
+}
+
+// parseResourceURI splits a gcal:// URI into its calendar ID and the
+// unescaped path segments that follow it
+func parseResourceURI(uri string) (string, []string, error) {
+	path, ok := strings.CutPrefix(uri, resourceScheme+"calendars/")
+	if !ok || path == "" {
+		return "", nil, fmt.Errorf("%w: %s", errResourceNotFound, uri)
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil || unescaped == "" {
+			return "", nil, fmt.Errorf("%w: %s", errResourceNotFound, uri)
+		}
+		segments[i] = unescaped
+	}
+	return segments[0], segments[1:], nil
+}
@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// newResourceServer returns a server over a primary calendar with two events
+// on 2025-01-06, created out of order, and a Team calendar. It returns the
+// Team calendar's ID and the events' IDs in start order.
+func newResourceServer(t *testing.T) (*Server, string, []string) {
+	t.Helper()
+	b := calendar.NewMemoryBackend("")
+	ctx := context.Background()
+	team, err := b.CreateCalendar(ctx, &types.CreateCalendarArgs{Summary: "Team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	planning, err := b.CreateEvent(ctx, &types.CreateEventArgs{
+		Summary:   "Planning",
+		Location:  "Room 1",
+		StartTime: "2025-01-06T10:00:00Z",
+		EndTime:   "2025-01-06T11:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	standup, err := b.CreateEvent(ctx, &types.CreateEventArgs{
+		Summary:   "Standup",
+		StartTime: "2025-01-06T09:00:00+01:00",
+		EndTime:   "2025-01-06T09:15:00+01:00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewServer(b, &config.Config{}, 0), team, []string{standup, planning}
+}
+
+// readResource reads uri and returns its only contents
+func readResource(t *testing.T, s *Server, uri string) map[string]interface{} {
+	t.Helper()
+	result := call(t, context.Background(), s, "resources/read", fmt.Sprintf(`{"uri":%q}`, uri))
+	contents, _ := result["contents"].([]interface{})
+	if len(contents) != 1 {
+		t.Fatalf("resources/read %s = %v, want one item", uri, result)
+	}
+	item, _ := contents[0].(map[string]interface{})
+	if item["uri"] != uri {
+		t.Errorf("resources/read %s uri = %v", uri, item["uri"])
+	}
+	return item
+}
+
+func TestListResources(t *testing.T) {
+	s, team, _ := newResourceServer(t)
+	ctx := context.Background()
+
+	result := call(t, ctx, s, "resources/list", "")
+	var uris []string
+	for _, resource := range result["resources"].([]interface{}) {
+		uris = append(uris, resource.(map[string]interface{})["uri"].(string))
+	}
+	want := []string{
+		"gcal://calendars/me@example.com",
+		"gcal://calendars/me@example.com/agenda/today",
+		calendarURI(team),
+		calendarURI(team) + "/agenda/today",
+	}
+	if !reflect.DeepEqual(uris, want) {
+		t.Errorf("resources/list = %q, want %q", uris, want)
+	}
+
+	result = call(t, ctx, s, "resources/templates/list", "")
+	var templates []string
+	for _, template := range result["resourceTemplates"].([]interface{}) {
+		templates = append(templates, template.(map[string]interface{})["uriTemplate"].(string))
+	}
+	want = []string{
+		"gcal://calendars/{calendarId}",
+		"gcal://calendars/{calendarId}/events/{eventId}",
+		"gcal://calendars/{calendarId}/agenda/{date}",
+	}
+	if !reflect.DeepEqual(templates, want) {
+		t.Errorf("resources/templates/list = %q, want %q", templates, want)
+	}
+}
+
+func TestReadResource(t *testing.T) {
+	s, team, events := newResourceServer(t)
+
+	var cal types.Calendar
+	item := readResource(t, s, calendarURI(team))
+	if err := json.Unmarshal([]byte(item["text"].(string)), &cal); err != nil || cal.Summary != "Team" || item["mimeType"] != "application/json" {
+		t.Errorf("calendar resource = %v, want Team as JSON", item)
+	}
+
+	var event types.CalendarEvent
+	item = readResource(t, s, "gcal://calendars/primary/events/"+events[1])
+	if err := json.Unmarshal([]byte(item["text"].(string)), &event); err != nil || event.Summary != "Planning" {
+		t.Errorf("event resource = %v, want Planning", item)
+	}
+
+	// Agendas list the day in the calendar's time zone, in start order
+	item = readResource(t, s, "gcal://calendars/me%40example.com/agenda/2025-01-06")
+	want := "# me@example.com: Monday, January 6, 2025\n\n" +
+		"- **08:00–08:15** Standup `" + events[0] + "`\n" +
+		"- **10:00–11:00** Planning (Room 1) `" + events[1] + "`\n"
+	if item["text"] != want || item["mimeType"] != "text/markdown" {
+		t.Errorf("agenda = %v, want text %q", item, want)
+	}
+
+	item = readResource(t, s, calendarURI(team)+"/agenda/2025-01-06")
+	if want := "# Team: Monday, January 6, 2025\n\nNo events.\n"; item["text"] != want {
+		t.Errorf("empty agenda = %q, want %q", item["text"], want)
+	}
+}
+
+func TestReadResourceErrors(t *testing.T) {
+	s, team, _ := newResourceServer(t)
+	ctx := context.Background()
+
+	for _, uri := range []string{
+		"https://example.com/calendar",
+		"gcal://calendars/",
+		"gcal://calendars/primary/agenda/tomorrow",
+		"gcal://calendars/primary/events",
+		calendarURI(team) + "/settings/x",
+	} {
+		if code, msg := callError(t, ctx, s, "resources/read", fmt.Sprintf(`{"uri":%q}`, uri)); code != errCodeResourceNotFound {
+			t.Errorf("resources/read %s error = %d %s, want %d", uri, code, msg, errCodeResourceNotFound)
+		}
+	}
+	if code, _ := callError(t, ctx, s, "resources/read", `{}`); code != errCodeInvalidParams {
+		t.Errorf("resources/read without a uri error = %d, want %d", code, errCodeInvalidParams)
+	}
+}
+
+func TestResourceURI(t *testing.T) {
+	for _, calendarID := range []string{"me@example.com", "en.usa#holiday@group.v.calendar.google.com", "team/a b"} {
+		uri := calendarURI(calendarID) + "/events/" + url.PathEscape("ev/1")
+		gotID, rest, err := parseResourceURI(uri)
+		if err != nil || gotID != calendarID || !reflect.DeepEqual(rest, []string{"events", "ev/1"}) {
+			t.Errorf("parseResourceURI(%s) = %q, %q, %v; want %q and the event", uri, gotID, rest, err, calendarID)
+		}
+	}
+}
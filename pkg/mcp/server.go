@@ -0,0 +1,414 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+type Server struct {
+	backend       calendar.Backend
+	router        *mux.Router
+	httpServer    *http.Server
+	tools         *ToolRegistry
+	resources     *ResourceRegistry
+	prompts       *PromptRegistry
+	subscriptions *SubscriptionManager
+	sessions      *sessionStore
+	metrics       *ToolMetrics
+}
+
+// sessionHeader carries the session ID issued by initialize on every later
+// Streamable HTTP request
+const sessionHeader = "Mcp-Session-Id"
+
+// protocolVersionHeader repeats the negotiated protocol version on every
+// request after initialize
+const protocolVersionHeader = "Mcp-Protocol-Version"
+
+// webhookPath receives Google Calendar push notifications
+const webhookPath = "/webhooks/calendar"
+
+// maxRequestSize bounds the body of a single POST
+const maxRequestSize = 10 * 1024 * 1024
+
+// NewServer serves the calendars in backend over MCP
+func NewServer(backend calendar.Backend, cfg *config.Config, port int) *Server {
+	s := &Server{
+		backend:  backend,
+		router:   mux.NewRouter(),
+		sessions: newSessionStore(),
+		metrics:  NewToolMetrics(),
+	}
+
+	s.tools = NewToolRegistry(backend, cfg.ReadOnly)
+	s.tools.SetListLimit(cfg.ListLimit)
+	// Recovery sits inside logging and timing so that a panicking tool is
+	// still logged and counted as a failure
+	s.tools.Use(
+		LogMiddleware(cfg.RedactFields),
+		TimingMiddleware(s.metrics),
+		RecoverMiddleware(),
+		TimeoutMiddleware(cfg.ToolTimeout, cfg.ToolTimeouts),
+	)
+	s.resources = NewResourceRegistry(backend)
+	s.prompts = NewPromptRegistry(backend, cfg.PromptsDir())
+	if cfg.WebhookURL != "" {
+		if watcher, ok := backend.(calendar.Watcher); ok {
+			s.subscriptions = NewSubscriptionManager(watcher, cfg.WebhookURL)
+		} else {
+			logrus.Warn("Calendar backend cannot push notifications; resource subscriptions are disabled")
+		}
+	}
+	s.setupRoutes()
+	
+	s.httpServer = &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      s.router,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	// Event streams only end when their session does
+	s.httpServer.RegisterOnShutdown(s.sessions.closeAll)
+
+	return s
+}
+
+// Register adds tools to the server, typically from extension packages.
+// Tools should be registered before the server starts serving.
+func (s *Server) Register(handlers ...ToolHandler) error {
+	return s.tools.Register(handlers...)
+}
+
+// Use adds middleware around every tool call, inside the built-in logging,
+// timing, recovery and timeout middleware
+func (s *Server) Use(middleware ...Middleware) {
+	s.tools.Use(middleware...)
+}
+
+func (s *Server) setupRoutes() {
+	// MCP Streamable HTTP endpoint
+	s.router.HandleFunc("/", s.handleMCPRequest).Methods("POST")
+	s.router.HandleFunc("/", s.handleEventStream).Methods("GET")
+	s.router.HandleFunc("/", s.handleDeleteSession).Methods("DELETE")
+	
+	// Google Calendar push notifications
+	if s.subscriptions != nil {
+		s.router.HandleFunc(webhookPath, s.subscriptions.HandleWebhook).Methods("POST")
+	}
+
+	// Health check
+	s.router.HandleFunc("/health", s.handleHealth).Methods("GET")
+
+	// Tool call counts and latencies
+	s.router.HandleFunc("/metrics", s.handleMetrics).Methods("GET")
+	
+	// CORS middleware
+	s.router.Use(corsMiddleware)
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	// Check authentication
+	if err := s.checkAuthenticated(); err != nil {
+		return err
+	}
+
+	go s.sessions.expireIdle(ctx)
+
+	// Start server in goroutine
+	go func() {
+		logrus.Infof("Server listening on %s", s.httpServer.Addr)
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.Errorf("Server error: %v", err)
+		}
+	}()
+
+	// Wait for context cancellation
+	<-ctx.Done()
+
+	// Graceful shutdown
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := s.httpServer.Shutdown(shutdownCtx)
+	if s.subscriptions != nil {
+		s.subscriptions.Close()
+	}
+	return err
+}
+
+func (s *Server) checkAuthenticated() error {
+	if !s.authenticated() {
+		if s.tools.readOnly {
+			// Read-only mode has a token of its own
+			return fmt.Errorf("Calendar client not authenticated. Run with -auth -read-only first")
+		}
+		return fmt.Errorf("Calendar client not authenticated. Run with -auth flag first")
+	}
+	return nil
+}
+
+// authenticated reports whether the backend has the credentials it needs.
+// Backends without credentials always do.
+func (s *Server) authenticated() bool {
+	if auth, ok := s.backend.(calendar.Authenticator); ok {
+		return auth.IsAuthenticated()
+	}
+	return true
+}
+
+type JSONRPCRequest struct {
+	JsonRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+
+	// notification is set for messages without an id, which must never be
+	// answered
+	notification bool
+}
+
+// IsNotification reports whether the message carried no id
+func (r *JSONRPCRequest) IsNotification() bool {
+	return r.notification
+}
+
+type JSONRPCResponse struct {
+	JsonRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   interface{} `json:"error,omitempty"`
+}
+
+type InitializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ClientInfo      map[string]interface{} `json:"clientInfo"`
+}
+
+type ToolsListParams struct{}
+
+type ResourcesReadParams struct {
+	URI string `json:"uri"`
+}
+
+type ResourcesSubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+type PromptsGetParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+type LoggingSetLevelParams struct {
+	Level string `json:"level"`
+}
+
+type ToolsCallParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+func (s *Server) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, newErrorResponse(nil, errCodeInvalidRequest, fmt.Sprintf("Invalid Request: %v", err)))
+		return
+	}
+
+	p, errResponse := parsePayload(data)
+	if errResponse != nil {
+		writeJSON(w, http.StatusBadRequest, errResponse)
+		return
+	}
+
+	initialize := false
+	streamable := false
+	for _, req := range p.requests() {
+		initialize = initialize || req.Method == "initialize"
+		streamable = streamable || req.Method == "tools/call"
+	}
+
+	var sess *Session
+	if initialize {
+		sess = s.sessions.create()
+		w.Header().Set(sessionHeader, sess.ID())
+	} else if sess = s.lookupSession(w, r); sess == nil {
+		return
+	}
+	ctx := withSession(r.Context(), sess)
+
+	if !p.expectsResponse() {
+		// Notifications and responses are acknowledged without a body
+		s.dispatch(ctx, p)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// Tool calls may emit notifications while they run, so answer them on an
+	// event stream when the client accepts one
+	if streamable && acceptsEventStream(r) {
+		stream := newSSEWriter(w)
+		stream.open()
+		defer stream.close()
+
+		if response := s.dispatch(withRequestSink(ctx, stream), p); response != nil {
+			stream.send(response)
+		}
+		return
+	}
+
+	if streamable {
+		// Tool calls are bounded by their own timeouts, which may well be
+		// longer than the server-wide write timeout
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			logrus.Debugf("Failed to clear write deadline for tool call: %v", err)
+		}
+	}
+
+	response := s.dispatch(ctx, p)
+	if response == nil {
+		// Every request in the payload was cancelled by the client
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if single, ok := response.(*JSONRPCResponse); ok && initialize && single.Error != nil {
+		s.sessions.remove(sess.ID())
+		w.Header().Del(sessionHeader)
+	}
+
+	writeJSON(w, http.StatusOK, response) // JSON-RPC errors still return 200
+}
+
+// handleEventStream opens the standalone stream the server uses for messages
+// that are not tied to a client request
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r) {
+		http.Error(w, "Client must accept text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+
+	sess := s.lookupSession(w, r)
+	if sess == nil {
+		return
+	}
+
+	stream := newSSEWriter(w)
+	if !sess.attach(stream) {
+		http.Error(w, "An event stream is already open for this session", http.StatusConflict)
+		return
+	}
+	defer sess.detach(stream)
+	defer stream.close()
+
+	stream.open()
+	logrus.Debugf("Opened event stream for session %s", sess.ID())
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sess.Done():
+			return
+		case <-ticker.C:
+			if !stream.keepAlive() {
+				return
+			}
+		}
+	}
+}
+
+// handleDeleteSession terminates the session named by the request
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(sessionHeader)
+	if id == "" {
+		http.Error(w, "Missing "+sessionHeader+" header", http.StatusBadRequest)
+		return
+	}
+
+	if !s.sessions.remove(id) {
+		http.Error(w, "Unknown session", http.StatusNotFound)
+		return
+	}
+
+	logrus.Debugf("Terminated session %s", id)
+	w.WriteHeader(http.StatusOK)
+}
+
+// lookupSession resolves the request's Mcp-Session-Id header, writing the
+// appropriate HTTP error and returning nil if it is missing or unknown or the
+// request's protocol version does not match the session's
+func (s *Server) lookupSession(w http.ResponseWriter, r *http.Request) *Session {
+	id := r.Header.Get(sessionHeader)
+	if id == "" {
+		http.Error(w, "Missing "+sessionHeader+" header", http.StatusBadRequest)
+		return nil
+	}
+
+	sess, ok := s.sessions.get(id)
+	if !ok {
+		// 404 tells the client to start over with a new initialize
+		http.Error(w, "Unknown or expired session", http.StatusNotFound)
+		return nil
+	}
+
+	// Clients repeat the negotiated version on every request; those that
+	// predate the header send none and are assumed to be on the negotiated one
+	if version := r.Header.Get(protocolVersionHeader); version != "" && sess.Initialized() && version != sess.ProtocolVersion() {
+		http.Error(w, fmt.Sprintf("Unsupported %s %q: session negotiated %q", protocolVersionHeader, version, sess.ProtocolVersion()), http.StatusBadRequest)
+		return nil
+	}
+	return sess
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	status := map[string]interface{}{
+		"status":        "healthy",
+		"authenticated": s.authenticated(),
+		"timestamp":     time.Now().UTC(),
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tools": s.metrics.Snapshot(),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+		
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		
+		next.ServeHTTP(w, r)
+	})
+}
\ No newline at end of file
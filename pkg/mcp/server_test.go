@@ -0,0 +1,508 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// newTestServer returns a Server over backend, or an empty MemoryBackend if
+// nil, and an HTTP server serving it with the server's own timeouts
+func newTestServer(t *testing.T, backend calendar.Backend, cfg *config.Config) (*Server, *httptest.Server) {
+	t.Helper()
+	if backend == nil {
+		backend = calendar.NewMemoryBackend("")
+	}
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	s := NewServer(backend, cfg, 0)
+	ts := httptest.NewUnstartedServer(s.router)
+	ts.Config.ReadTimeout = s.httpServer.ReadTimeout
+	ts.Config.WriteTimeout = s.httpServer.WriteTimeout
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+// post sends body to the MCP endpoint on the session, if any
+func post(t *testing.T, ts *httptest.Server, sessionID, body string) *http.Response {
+	t.Helper()
+	header := map[string]string{"Content-Type": "application/json", "Accept": "application/json"}
+	if sessionID != "" {
+		header[sessionHeader] = sessionID
+	}
+	return do(t, ts, http.MethodPost, body, header)
+}
+
+// initializeSession opens an initialized session and returns its ID
+func initializeSession(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+	resp := post(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test"}}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize status = %d, want 200", resp.StatusCode)
+	}
+	id := resp.Header.Get(sessionHeader)
+	if id == "" {
+		t.Fatalf("initialize returned no %s header", sessionHeader)
+	}
+	if resp := post(t, ts, id, `{"jsonrpc":"2.0","method":"notifications/initialized"}`); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("notifications/initialized status = %d, want 202", resp.StatusCode)
+	}
+	return id
+}
+
+// recordingSink stands in for a client's event stream, keeping every
+// message sent on it
+type recordingSink struct {
+	mu       sync.Mutex
+	messages []map[string]interface{}
+}
+
+func (r *recordingSink) send(msg interface{}) bool {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return false
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return false
+	}
+
+	r.mu.Lock()
+	r.messages = append(r.messages, decoded)
+	r.mu.Unlock()
+	return true
+}
+
+// received returns the messages sent so far with the given method
+func (r *recordingSink) received(method string) []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []map[string]interface{}
+	for _, msg := range r.messages {
+		if msg["method"] == method {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+// waitFor waits until n messages with the given method have been sent and
+// returns them
+func (r *recordingSink) waitFor(t *testing.T, method string, n int) []map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got := r.received(method)
+		if len(got) >= n {
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d %s messages, want %d", len(got), method, n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// newSessionContext returns a context carrying an initialized session whose
+// standalone stream is recorded
+func newSessionContext(t *testing.T) (context.Context, *Session, *recordingSink) {
+	t.Helper()
+	sess := newSession(newRandomID())
+	sess.initialize(supportedProtocolVersions[0], map[string]interface{}{"name": "test", "version": "1.0"})
+	sink := &recordingSink{}
+	sess.attach(sink)
+	t.Cleanup(sess.close)
+	return withSession(context.Background(), sess), sess, sink
+}
+
+// rpc dispatches payload the way the transports do and returns the reply as
+// JSON, or "" when nothing is sent back
+func rpc(ctx context.Context, s *Server, payload string) string {
+	p, errResponse := parsePayload([]byte(payload))
+	var response interface{} = errResponse
+	if errResponse == nil {
+		response = s.dispatch(ctx, p)
+	}
+	if response == nil {
+		return ""
+	}
+	data, _ := json.Marshal(response)
+	return string(data)
+}
+
+// request encodes a request with id 1, leaving out params if empty
+func request(method, params string) string {
+	if params == "" {
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q}`, method)
+	}
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":%s}`, method, params)
+}
+
+// call sends a request and returns its result, failing the test if it gets
+// an error
+func call(t *testing.T, ctx context.Context, s *Server, method, params string) map[string]interface{} {
+	t.Helper()
+	var response struct {
+		Result map[string]interface{} `json:"result"`
+		Error  map[string]interface{} `json:"error"`
+	}
+	reply := rpc(ctx, s, request(method, params))
+	if err := json.Unmarshal([]byte(reply), &response); err != nil {
+		t.Fatalf("%s reply %q: %v", method, reply, err)
+	}
+	if response.Error != nil {
+		t.Fatalf("%s error = %v", method, response.Error)
+	}
+	return response.Result
+}
+
+// callError sends a request that must fail and returns its error code and
+// message
+func callError(t *testing.T, ctx context.Context, s *Server, method, params string) (int, string) {
+	t.Helper()
+	var response struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	reply := rpc(ctx, s, request(method, params))
+	if err := json.Unmarshal([]byte(reply), &response); err != nil {
+		t.Fatalf("%s reply %q: %v", method, reply, err)
+	}
+	if response.Error == nil {
+		t.Fatalf("%s = %s, want an error", method, reply)
+	}
+	return response.Error.Code, response.Error.Message
+}
+
+func TestToolCallOutlastsWriteTimeout(t *testing.T) {
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{ToolTimeout: time.Second}, 0)
+	ts := httptest.NewUnstartedServer(s.router)
+	ts.Config.WriteTimeout = 50 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+	if err := s.Register(NewTool(Tool{Name: "slow"}, func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			return textResult("done", nil), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})); err != nil {
+		t.Fatal(err)
+	}
+	id := initializeSession(t, ts)
+
+	resp := post(t, ts, id, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"slow","arguments":{}}}`)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading tools/call response error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"text":"done"`) {
+		t.Errorf("tools/call = %d %s, want the tool's result", resp.StatusCode, body)
+	}
+}
+
+// do sends an HTTP request to the MCP endpoint with the given headers
+func do(t *testing.T, ts *httptest.Server, method, body string, header map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+"/", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s error = %v", method, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// readEvent returns the next message event of an event stream
+func readEvent(t *testing.T, r *bufio.Reader) map[string]interface{} {
+	t.Helper()
+	data := make(chan string, 1)
+	go func() {
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				close(data)
+				return
+			}
+			if strings.HasPrefix(line, "data: ") {
+				data <- strings.TrimPrefix(line, "data: ")
+				return
+			}
+		}
+	}()
+
+	select {
+	case event, ok := <-data:
+		if !ok {
+			t.Fatal("event stream ended")
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(event), &msg); err != nil {
+			t.Fatalf("event %q: %v", event, err)
+		}
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return nil
+	}
+}
+
+func TestStreamableHTTPSessions(t *testing.T) {
+	_, ts := newTestServer(t, nil, nil)
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+
+	// A failed initialize leaves no session behind
+	resp := post(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	if id := resp.Header.Get(sessionHeader); resp.StatusCode != http.StatusOK || id != "" {
+		t.Errorf("failed initialize = %d with session %q, want 200 and none", resp.StatusCode, id)
+	}
+
+	id := initializeSession(t, ts)
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		header     map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "request",
+			method:     http.MethodPost,
+			body:       ping,
+			header:     map[string]string{sessionHeader: id},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"jsonrpc":"2.0","id":2,"result":{}}`,
+		},
+		{
+			name:       "negotiated protocol version",
+			method:     http.MethodPost,
+			body:       ping,
+			header:     map[string]string{sessionHeader: id, protocolVersionHeader: "2025-06-18"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "other protocol version",
+			method:     http.MethodPost,
+			body:       ping,
+			header:     map[string]string{sessionHeader: id, protocolVersionHeader: "2025-03-26"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "notification",
+			method:     http.MethodPost,
+			body:       `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":9}}`,
+			header:     map[string]string{sessionHeader: id},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "malformed",
+			method:     http.MethodPost,
+			body:       `{"jsonrpc":`,
+			header:     map[string]string{sessionHeader: id},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error: payload is not valid JSON"}}`,
+		},
+		{
+			name:       "no session",
+			method:     http.MethodPost,
+			body:       ping,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown session",
+			method:     http.MethodPost,
+			body:       ping,
+			header:     map[string]string{sessionHeader: "unknown"},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "stream without accepting one",
+			method:     http.MethodGet,
+			header:     map[string]string{sessionHeader: id},
+			wantStatus: http.StatusNotAcceptable,
+		},
+		{
+			name:       "delete without session",
+			method:     http.MethodDelete,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "delete",
+			method:     http.MethodDelete,
+			header:     map[string]string{sessionHeader: id},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "request after delete",
+			method:     http.MethodPost,
+			body:       ping,
+			header:     map[string]string{sessionHeader: id},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "delete again",
+			method:     http.MethodDelete,
+			header:     map[string]string{sessionHeader: id},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		resp := do(t, ts, tt.method, tt.body, tt.header)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("%s: status = %d %s, want %d", tt.name, resp.StatusCode, body, tt.wantStatus)
+		}
+		if tt.wantBody != "" && strings.TrimSpace(string(body)) != tt.wantBody {
+			t.Errorf("%s: body = %s, want %s", tt.name, body, tt.wantBody)
+		}
+	}
+}
+
+func TestStreamableHTTPEventStream(t *testing.T) {
+	_, ts := newTestServer(t, nil, nil)
+	id := initializeSession(t, ts)
+	accept := "application/json, text/event-stream"
+
+	// Tool calls are answered on an event stream when the client takes one
+	resp := do(t, ts, http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_events","arguments":{}}}`,
+		map[string]string{sessionHeader: id, "Accept": accept})
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "text/event-stream" {
+		t.Fatalf("tools/call = %d %s, want an event stream", resp.StatusCode, ct)
+	}
+	if msg := readEvent(t, bufio.NewReader(resp.Body)); msg["id"] != float64(2) || msg["result"] == nil {
+		t.Errorf("tools/call event = %v, want its result", msg)
+	}
+
+	// Other requests are answered with JSON all the same
+	resp = do(t, ts, http.MethodPost, `{"jsonrpc":"2.0","id":3,"method":"ping"}`, map[string]string{sessionHeader: id, "Accept": accept})
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("ping Content-Type = %s, want application/json", ct)
+	}
+
+	// The standalone stream stays open until the session ends, and only
+	// one may be open at a time
+	stream := do(t, ts, http.MethodGet, "", map[string]string{sessionHeader: id, "Accept": "text/event-stream"})
+	if stream.StatusCode != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200", stream.StatusCode)
+	}
+	if resp := do(t, ts, http.MethodGet, "", map[string]string{sessionHeader: id, "Accept": "text/event-stream"}); resp.StatusCode != http.StatusConflict {
+		t.Errorf("second GET status = %d, want 409", resp.StatusCode)
+	}
+	if resp := do(t, ts, http.MethodDelete, "", map[string]string{sessionHeader: id}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want 200", resp.StatusCode)
+	}
+	ended := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(stream.Body)
+		ended <- err
+	}()
+	select {
+	case err := <-ended:
+		if err != nil {
+			t.Errorf("reading stream after DELETE error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("stream still open after DELETE")
+	}
+}
+
+// recordingBackend is a Backend that records the calendars whose events are
+// listed, and may lack credentials
+type recordingBackend struct {
+	calendar.Backend
+	authenticated bool
+
+	mu     sync.Mutex
+	listed []string
+}
+
+func (b *recordingBackend) ListEvents(ctx context.Context, args *types.ListEventsArgs) ([]*types.CalendarEvent, string, error) {
+	b.mu.Lock()
+	b.listed = append(b.listed, args.CalendarID)
+	b.mu.Unlock()
+	return b.Backend.ListEvents(ctx, args)
+}
+
+func (b *recordingBackend) IsAuthenticated() bool {
+	return b.authenticated
+}
+
+func TestCustomBackend(t *testing.T) {
+	b := &recordingBackend{Backend: calendar.NewMemoryBackend("team@example.com"), authenticated: true}
+	s, ts := newTestServer(t, b, nil)
+	ctx := context.Background()
+
+	// Tools, resources and prompts all read through the server's backend
+	call(t, ctx, s, "tools/call", `{"name":"list_events","arguments":{"calendarId":"primary"}}`)
+	call(t, ctx, s, "resources/read", `{"uri":"gcal://calendars/team@example.com/agenda/2025-01-06"}`)
+	call(t, ctx, s, "prompts/get", `{"name":"plan_my_week","arguments":{"startDate":"2025-01-06","calendarId":"team@example.com"}}`)
+	if want := []string{"primary", "team@example.com", "team@example.com"}; !reflect.DeepEqual(b.listed, want) {
+		t.Errorf("listed events of %q, want %q", b.listed, want)
+	}
+	result := call(t, ctx, s, "resources/list", "")
+	if resources, _ := result["resources"].([]interface{}); len(resources) == 0 || resources[0].(map[string]interface{})["uri"] != "gcal://calendars/team@example.com" {
+		t.Errorf("resources/list = %v, want the backend's calendar", result)
+	}
+
+	var health map[string]interface{}
+	resp, err := ts.Client().Get(ts.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil || health["authenticated"] != true {
+		t.Errorf("/health = %v, %v; want authenticated", health, err)
+	}
+}
+
+func TestUnauthenticatedBackend(t *testing.T) {
+	b := &recordingBackend{Backend: calendar.NewMemoryBackend("")}
+	s, ts := newTestServer(t, b, nil)
+
+	if err := s.Start(context.Background()); err == nil {
+		t.Error("Start() without credentials succeeded")
+	}
+	if err := s.ServeStdio(context.Background(), strings.NewReader(""), io.Discard); err == nil {
+		t.Error("ServeStdio() without credentials succeeded")
+	}
+
+	var health map[string]interface{}
+	resp, err := ts.Client().Get(ts.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil || health["authenticated"] != false {
+		t.Errorf("/health = %v, %v; want not authenticated", health, err)
+	}
+}
@@ -0,0 +1,375 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sessionIdleTimeout is how long an HTTP session may go unused before it is
+// discarded and its ID rejected
+const sessionIdleTimeout = time.Hour
+
+// errRequestCancelled is the cancellation cause of a request the client
+// withdrew with notifications/cancelled
+var errRequestCancelled = errors.New("request cancelled by client")
+
+// JSONRPCNotification is a server-initiated message that expects no reply
+type JSONRPCNotification struct {
+	JsonRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// messageSink delivers server-initiated JSON-RPC messages to a client. send
+// reports whether the message was handed to the transport.
+type messageSink interface {
+	send(msg interface{}) bool
+}
+
+// Session is the state the server keeps for one connected MCP client. HTTP
+// clients get one per initialize call, identified by the Mcp-Session-Id
+// header; the stdio transport serves exactly one.
+type Session struct {
+	id string
+
+	mu       sync.Mutex
+	out      messageSink
+	lastSeen time.Time
+	done     chan struct{}
+	closed   bool
+	inflight map[string]context.CancelCauseFunc // by request ID
+
+	// logger carries the session's request logs; logLevel is the least
+	// severe level its client receives
+	logger   *logrus.Logger
+	logLevel logrus.Level
+
+	// Set by initialize
+	initialized     bool
+	protocolVersion string
+	clientInfo      map[string]interface{}
+}
+
+func newSession(id string) *Session {
+	sess := &Session{
+		id:       id,
+		lastSeen: time.Now(),
+		done:     make(chan struct{}),
+		inflight: make(map[string]context.CancelCauseFunc),
+		logLevel: defaultClientLogLevel,
+	}
+	sess.logger = newSessionLogger(sess)
+	return sess
+}
+
+// ID returns the session identifier
+func (s *Session) ID() string {
+	return s.id
+}
+
+// initialize records the outcome of the initialize handshake. It fails if
+// the session was initialized before.
+func (s *Session) initialize(version string, clientInfo map[string]interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.initialized {
+		return false
+	}
+	s.initialized = true
+	s.protocolVersion = version
+	s.clientInfo = clientInfo
+	return true
+}
+
+// Initialized reports whether the session has completed initialize
+func (s *Session) Initialized() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.initialized
+}
+
+// ProtocolVersion returns the negotiated protocol version
+func (s *Session) ProtocolVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.protocolVersion
+}
+
+// clientName returns the name and version the client gave in clientInfo,
+// or "" before initialize
+func (s *Session) clientName() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name, _ := s.clientInfo["name"].(string)
+	if version, _ := s.clientInfo["version"].(string); name != "" && version != "" {
+		return name + "/" + version
+	}
+	return name
+}
+
+// LogLevel returns the least severe level the client receives log messages
+// at
+func (s *Session) LogLevel() logrus.Level {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logLevel
+}
+
+func (s *Session) setLogLevel(level logrus.Level) {
+	s.mu.Lock()
+	s.logLevel = level
+	s.mu.Unlock()
+
+	s.logger.SetLevel(sessionLoggerLevel(level))
+}
+
+// notify sends a notification on the session's standalone stream
+func (s *Session) notify(method string, params interface{}) bool {
+	return s.send(&JSONRPCNotification{
+		JsonRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+}
+
+// send delivers a message on the session's standalone stream, if one is open
+func (s *Session) send(msg interface{}) bool {
+	s.mu.Lock()
+	out := s.out
+	s.mu.Unlock()
+
+	if out == nil {
+		return false
+	}
+	return out.send(msg)
+}
+
+// attach makes sink the session's standalone stream. It fails if another
+// stream is already attached.
+func (s *Session) attach(sink messageSink) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.out != nil || s.closed {
+		return false
+	}
+	s.out = sink
+	return true
+}
+
+// detach removes sink if it is still the session's standalone stream
+func (s *Session) detach(sink messageSink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.out == sink {
+		s.out = nil
+	}
+}
+
+// track registers a request as in flight so the client can cancel it. The
+// returned function must be called once the request completes.
+func (s *Session) track(id interface{}, cancel context.CancelCauseFunc) func() {
+	key := requestKey(id)
+
+	s.mu.Lock()
+	s.inflight[key] = cancel
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+	}
+}
+
+// cancelRequest cancels the in-flight request with the given ID. Unknown IDs
+// are ignored, since the request may already have finished.
+func (s *Session) cancelRequest(id interface{}) bool {
+	s.mu.Lock()
+	cancel, ok := s.inflight[requestKey(id)]
+	s.mu.Unlock()
+
+	if ok {
+		cancel(errRequestCancelled)
+	}
+	return ok
+}
+
+// requestKey maps a JSON-RPC id to a map key that keeps 1 and "1" apart
+func requestKey(id interface{}) string {
+	data, _ := json.Marshal(id)
+	return string(data)
+}
+
+func (s *Session) touch() {
+	s.mu.Lock()
+	s.lastSeen = time.Now()
+	s.mu.Unlock()
+}
+
+func (s *Session) idleSince(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return now.Sub(s.lastSeen)
+}
+
+func (s *Session) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		s.out = nil
+		close(s.done)
+		for _, cancel := range s.inflight {
+			cancel(context.Canceled)
+		}
+	}
+}
+
+// Done is closed when the session is terminated
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// sessionStore tracks the live HTTP sessions
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*Session),
+	}
+}
+
+func (st *sessionStore) create() *Session {
+	sess := newSession(newRandomID())
+
+	st.mu.Lock()
+	st.sessions[sess.id] = sess
+	st.mu.Unlock()
+
+	return sess
+}
+
+func (st *sessionStore) get(id string) (*Session, bool) {
+	st.mu.Lock()
+	sess, ok := st.sessions[id]
+	st.mu.Unlock()
+
+	if ok {
+		sess.touch()
+	}
+	return sess, ok
+}
+
+func (st *sessionStore) remove(id string) bool {
+	st.mu.Lock()
+	sess, ok := st.sessions[id]
+	delete(st.sessions, id)
+	st.mu.Unlock()
+
+	if ok {
+		sess.close()
+	}
+	return ok
+}
+
+func (st *sessionStore) closeAll() {
+	st.mu.Lock()
+	sessions := st.sessions
+	st.sessions = make(map[string]*Session)
+	st.mu.Unlock()
+
+	for _, sess := range sessions {
+		sess.close()
+	}
+}
+
+// expireIdle periodically drops sessions that have not been used within
+// sessionIdleTimeout until ctx is done
+func (st *sessionStore) expireIdle(ctx context.Context) {
+	ticker := time.NewTicker(sessionIdleTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			st.mu.Lock()
+			var expired []string
+			for id, sess := range st.sessions {
+				if sess.idleSince(now) > sessionIdleTimeout {
+					expired = append(expired, id)
+				}
+			}
+			st.mu.Unlock()
+
+			for _, id := range expired {
+				logrus.Debugf("Expiring idle session %s", id)
+				st.remove(id)
+			}
+		}
+	}
+}
+
+// newRandomID returns an unguessable identifier
+func newRandomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+type sessionKey struct{}
+type requestSinkKey struct{}
+
+func withSession(ctx context.Context, sess *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// sessionFromContext returns the session the current request belongs to
+func sessionFromContext(ctx context.Context) *Session {
+	sess, _ := ctx.Value(sessionKey{}).(*Session)
+	return sess
+}
+
+// withRequestSink routes notifications raised while handling a request onto
+// the stream that will carry that request's response
+func withRequestSink(ctx context.Context, sink messageSink) context.Context {
+	return context.WithValue(ctx, requestSinkKey{}, sink)
+}
+
+// notifyClient sends a notification to the client that issued the request in
+// ctx. It prefers the request's own response stream and falls back to the
+// session's standalone stream; it reports whether the message was delivered.
+func notifyClient(ctx context.Context, method string, params interface{}) bool {
+	msg := &JSONRPCNotification{
+		JsonRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+
+	if sink, ok := ctx.Value(requestSinkKey{}).(messageSink); ok && sink.send(msg) {
+		return true
+	}
+	if sess := sessionFromContext(ctx); sess != nil {
+		return sess.send(msg)
+	}
+	return false
+}
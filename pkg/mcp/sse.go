@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sseKeepAlive is how often an idle event stream gets a comment line so
+// proxies do not close it
+const sseKeepAlive = 30 * time.Second
+
+// sseWriter writes JSON-RPC messages as Server-Sent Events
+type sseWriter struct {
+	mu     sync.Mutex
+	w      http.ResponseWriter
+	rc     *http.ResponseController
+	opened bool
+	closed bool
+}
+
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	return &sseWriter{w: w, rc: http.NewResponseController(w)}
+}
+
+// open switches the response to an event stream. The server-wide write
+// timeout is lifted because streams outlive ordinary requests.
+func (s *sseWriter) open() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.rc.SetWriteDeadline(time.Time{}); err != nil {
+		logrus.Debugf("Failed to clear write deadline for event stream: %v", err)
+	}
+
+	s.w.Header().Set("Content-Type", "text/event-stream")
+	s.w.Header().Set("Cache-Control", "no-cache")
+	s.w.Header().Set("Connection", "keep-alive")
+	s.w.WriteHeader(http.StatusOK)
+	s.rc.Flush()
+	s.opened = true
+}
+
+func (s *sseWriter) send(msg interface{}) bool {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logrus.Errorf("Failed to marshal event: %v", err)
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || !s.opened {
+		return false
+	}
+	if _, err := fmt.Fprintf(s.w, "event: message\ndata: %s\n\n", data); err != nil {
+		s.closed = true
+		return false
+	}
+	return s.rc.Flush() == nil
+}
+
+func (s *sseWriter) keepAlive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || !s.opened {
+		return false
+	}
+	if _, err := fmt.Fprint(s.w, ": keep-alive\n\n"); err != nil {
+		s.closed = true
+		return false
+	}
+	return s.rc.Flush() == nil
+}
+
+// close stops further writes; the handler returning ends the response
+func (s *sseWriter) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+}
+
+// acceptsEventStream reports whether the client is willing to receive an SSE
+// response
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxStdioMessageSize bounds a single newline-delimited JSON-RPC message
+const maxStdioMessageSize = 10 * 1024 * 1024
+
+// ServeStdio serves MCP over newline-delimited JSON-RPC, reading requests
+// from in and writing responses to out until in is closed or ctx is done.
+// Nothing but protocol messages may be written to out, so callers must send
+// logs elsewhere.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	if err := s.checkAuthenticated(); err != nil {
+		return err
+	}
+
+	w := &stdioWriter{enc: json.NewEncoder(out)}
+
+	// A stdio connection is a single session whose standalone stream is
+	// stdout itself
+	sess := newSession(newRandomID())
+	sess.attach(w)
+	defer sess.close()
+	ctx = withSession(ctx, sess)
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 64*1024), maxStdioMessageSize)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	logrus.Info("Serving MCP over stdio")
+
+	// Messages are handled concurrently so that a notifications/cancelled
+	// can reach a request that is still running
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			if err != nil {
+				return fmt.Errorf("failed to read from stdin: %w", err)
+			}
+			return nil
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.handleStdioMessage(ctx, w, line)
+			}()
+		}
+	}
+}
+
+func (s *Server) handleStdioMessage(ctx context.Context, w *stdioWriter, line []byte) {
+	p, errResponse := parsePayload(line)
+	if errResponse != nil {
+		w.send(errResponse)
+		return
+	}
+
+	if response := s.dispatch(ctx, p); response != nil {
+		w.send(response)
+	}
+}
+
+// stdioWriter serialises messages onto stdout, one JSON document per line
+type stdioWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (w *stdioWriter) send(msg interface{}) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.enc.Encode(msg); err != nil {
+		logrus.Errorf("Failed to write stdio message: %v", err)
+		return false
+	}
+	return true
+}
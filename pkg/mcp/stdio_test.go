@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+)
+
+// stdioClient drives ServeStdio the way an MCP client process would
+type stdioClient struct {
+	in   *io.PipeWriter
+	out  *bufio.Reader
+	done chan error
+}
+
+func startStdio(t *testing.T, s *Server) *stdioClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &stdioClient{in: inW, out: bufio.NewReader(outR), done: make(chan error, 1)}
+
+	go func() {
+		c.done <- s.ServeStdio(context.Background(), inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() {
+		inW.Close()
+		go io.Copy(io.Discard, outR)
+		<-c.done
+	})
+	return c
+}
+
+func (c *stdioClient) send(t *testing.T, line string) {
+	t.Helper()
+	if _, err := io.WriteString(c.in, line+"\n"); err != nil {
+		t.Fatalf("writing %s: %v", line, err)
+	}
+}
+
+// receive decodes the next line the server writes into v
+func (c *stdioClient) receive(t *testing.T, v interface{}) {
+	t.Helper()
+	lines := make(chan string, 1)
+	go func() {
+		line, _ := c.out.ReadString('\n')
+		lines <- line
+	}()
+
+	select {
+	case line := <-lines:
+		if err := json.Unmarshal([]byte(line), v); err != nil {
+			t.Fatalf("server wrote %q: %v", line, err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the server")
+	}
+}
+
+// receiveMessage reads the next single message the server writes
+func (c *stdioClient) receiveMessage(t *testing.T) map[string]interface{} {
+	t.Helper()
+	var msg map[string]interface{}
+	c.receive(t, &msg)
+	return msg
+}
+
+func TestServeStdio(t *testing.T) {
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{}, 0)
+	c := startStdio(t, s)
+
+	c.send(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test"}}}`)
+	msg := c.receiveMessage(t)
+	result, _ := msg["result"].(map[string]interface{})
+	if msg["id"] != float64(1) || result["protocolVersion"] != "2025-06-18" {
+		t.Fatalf("initialize = %v", msg)
+	}
+
+	// Blank lines and notifications get no reply, so the next message out
+	// answers the request after them
+	c.send(t, "")
+	c.send(t, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	c.send(t, `{"jsonrpc":"2.0","id":"two","method":"tools/list"}`)
+	msg = c.receiveMessage(t)
+	result, _ = msg["result"].(map[string]interface{})
+	if tools, _ := result["tools"].([]interface{}); msg["id"] != "two" || len(tools) != len(builtinTools) {
+		t.Fatalf("tools/list = %v", msg)
+	}
+
+	c.send(t, `{"jsonrpc":"2.0","id":3,`)
+	msg = c.receiveMessage(t)
+	if rpcErr, _ := msg["error"].(map[string]interface{}); msg["id"] != nil || rpcErr["code"] != float64(errCodeParse) {
+		t.Fatalf("malformed line = %v, want a parse error without an id", msg)
+	}
+}
+
+func TestServeStdioBatch(t *testing.T) {
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{}, 0)
+	c := startStdio(t, s)
+
+	c.send(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	c.receiveMessage(t)
+	c.send(t, `[{"jsonrpc":"2.0","id":2,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":3,"method":"ping"}]`)
+
+	var batch []map[string]interface{}
+	c.receive(t, &batch)
+	if len(batch) != 2 || batch[0]["id"] != float64(2) || batch[1]["id"] != float64(3) {
+		t.Errorf("batch reply = %v, want the two pings in order", batch)
+	}
+}
+
+func TestServeStdioEOF(t *testing.T) {
+	s := NewServer(calendar.NewMemoryBackend(""), &config.Config{}, 0)
+	err := s.ServeStdio(context.Background(), strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n"), io.Discard)
+	if err != nil {
+		t.Errorf("ServeStdio() at end of input error = %v", err)
+	}
+}
+
+func TestServeStdioUnauthenticated(t *testing.T) {
+	client, err := calendar.NewClient(&config.Config{CredentialsPath: t.TempDir() + "/credentials.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(client, &config.Config{}, 0)
+	err = s.ServeStdio(context.Background(), strings.NewReader(""), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "-auth") {
+		t.Errorf("ServeStdio() without a token error = %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// channelTTL is the lifetime requested for each push channel
+	channelTTL = 24 * time.Hour
+
+	// channelRenewMargin is how long before expiry a channel is replaced
+	channelRenewMargin = time.Hour
+
+	// channelRetryDelay is how long to wait after a failed renewal
+	channelRetryDelay = 5 * time.Minute
+
+	// channelCallTimeout bounds the background calls that renew and stop
+	// channels, which run outside of any client request
+	channelCallTimeout = 30 * time.Second
+)
+
+// SubscriptionManager turns Google Calendar push notifications into
+// notifications/resources/updated messages. Each watched calendar gets one
+// push channel, shared by every session subscribed to any of its resources.
+type SubscriptionManager struct {
+	watcher    calendar.Watcher
+	webhookURL string
+
+	mu       sync.Mutex
+	watches  map[string]*calendarWatch // by calendar ID
+	channels map[string]*calendarWatch // by channel ID
+	sessions map[*Session]bool         // sessions being watched for termination
+}
+
+// calendarWatch is the push channel for one calendar and its subscribers
+type calendarWatch struct {
+	calendarID  string
+	channel     *types.WatchChannel
+	subscribers map[*Session]map[string]bool // session -> resource URIs
+	renewTimer  *time.Timer
+}
+
+func NewSubscriptionManager(watcher calendar.Watcher, webhookURL string) *SubscriptionManager {
+	return &SubscriptionManager{
+		watcher:    watcher,
+		webhookURL: webhookURL,
+		watches:    make(map[string]*calendarWatch),
+		channels:   make(map[string]*calendarWatch),
+		sessions:   make(map[*Session]bool),
+	}
+}
+
+// Subscribe registers sess for updates to uri, opening a push channel for the
+// resource's calendar if none exists yet
+func (m *SubscriptionManager) Subscribe(ctx context.Context, sess *Session, uri string) error {
+	calendarID, _, err := parseResourceURI(uri)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	watch, ok := m.watches[calendarID]
+	if !ok {
+		channel, err := m.openChannel(ctx, calendarID)
+		if err != nil {
+			return err
+		}
+		watch = &calendarWatch{
+			calendarID:  calendarID,
+			channel:     channel,
+			subscribers: make(map[*Session]map[string]bool),
+		}
+		m.watches[calendarID] = watch
+		m.channels[channel.ID] = watch
+		m.scheduleRenewal(watch)
+	}
+
+	if watch.subscribers[sess] == nil {
+		watch.subscribers[sess] = make(map[string]bool)
+	}
+	watch.subscribers[sess][uri] = true
+
+	if !m.sessions[sess] {
+		m.sessions[sess] = true
+		go func() {
+			<-sess.Done()
+			m.unsubscribeSession(sess)
+		}()
+	}
+
+	return nil
+}
+
+// Unsubscribe removes sess's subscription to uri, closing the calendar's push
+// channel once nobody is subscribed to it
+func (m *SubscriptionManager) Unsubscribe(sess *Session, uri string) error {
+	calendarID, _, err := parseResourceURI(uri)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	watch, ok := m.watches[calendarID]
+	if !ok {
+		return nil
+	}
+
+	delete(watch.subscribers[sess], uri)
+	if len(watch.subscribers[sess]) == 0 {
+		delete(watch.subscribers, sess)
+	}
+	if len(watch.subscribers) == 0 {
+		m.closeWatch(watch)
+	}
+	return nil
+}
+
+func (m *SubscriptionManager) unsubscribeSession(sess *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, sess)
+	for _, watch := range m.watches {
+		delete(watch.subscribers, sess)
+		if len(watch.subscribers) == 0 {
+			m.closeWatch(watch)
+		}
+	}
+}
+
+// Close stops every push channel, waiting for Google to acknowledge
+func (m *SubscriptionManager) Close() {
+	m.mu.Lock()
+	var channels []*types.WatchChannel
+	for _, watch := range m.watches {
+		if watch.renewTimer != nil {
+			watch.renewTimer.Stop()
+		}
+		channels = append(channels, watch.channel)
+	}
+	m.watches = make(map[string]*calendarWatch)
+	m.channels = make(map[string]*calendarWatch)
+	m.mu.Unlock()
+
+	for _, channel := range channels {
+		m.stopChannel(channel)
+	}
+}
+
+// HandleWebhook receives Google's push notifications
+func (m *SubscriptionManager) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	n, err := calendar.ParseNotification(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	watch, ok := m.channels[n.ChannelID]
+	if !ok {
+		m.mu.Unlock()
+		// Usually a channel that was just replaced or stopped; acknowledging
+		// keeps Google from retrying
+		logrus.Debugf("Ignoring notification for unknown channel %s", n.ChannelID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if n.Token != watch.channel.Token {
+		m.mu.Unlock()
+		logrus.Warnf("Rejecting notification with bad token for channel %s", n.ChannelID)
+		http.Error(w, "invalid channel token", http.StatusForbidden)
+		return
+	}
+
+	type delivery struct {
+		sess *Session
+		uri  string
+	}
+	var deliveries []delivery
+	if n.ResourceState != "sync" {
+		// Subscribers read the calendar again next, so cached copies must
+		// not hide the change
+		if cache, ok := m.watcher.(*calendar.CachedBackend); ok {
+			cache.Invalidate()
+		}
+		for sess, uris := range watch.subscribers {
+			for uri := range uris {
+				deliveries = append(deliveries, delivery{sess, uri})
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	logrus.Debugf("Calendar %s changed (%s), notifying %d subscriptions", watch.calendarID, n.ResourceState, len(deliveries))
+	for _, d := range deliveries {
+		d.sess.notify("notifications/resources/updated", map[string]interface{}{
+			"uri": d.uri,
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// openChannel registers a fresh push channel for calendarID
+func (m *SubscriptionManager) openChannel(ctx context.Context, calendarID string) (*types.WatchChannel, error) {
+	channel, err := m.watcher.WatchEvents(ctx, calendarID, newRandomID(), m.webhookURL, newRandomID(), channelTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to calendar %s: %w", calendarID, err)
+	}
+	logrus.Debugf("Opened push channel %s for calendar %s, expires %s", channel.ID, calendarID, channel.Expiration)
+	return channel, nil
+}
+
+// scheduleRenewal arms the watch's timer to replace its channel shortly
+// before it expires. Must be called with m.mu held.
+func (m *SubscriptionManager) scheduleRenewal(watch *calendarWatch) {
+	delay := time.Until(watch.channel.Expiration) - channelRenewMargin
+	if delay < 0 {
+		delay = 0
+	}
+	watch.renewTimer = time.AfterFunc(delay, func() {
+		m.renew(watch)
+	})
+}
+
+func (m *SubscriptionManager) renew(watch *calendarWatch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.watches[watch.calendarID] != watch {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), channelCallTimeout)
+	defer cancel()
+
+	channel, err := m.openChannel(ctx, watch.calendarID)
+	if err != nil {
+		logrus.Errorf("Failed to renew push channel for calendar %s: %v", watch.calendarID, err)
+		watch.renewTimer = time.AfterFunc(channelRetryDelay, func() {
+			m.renew(watch)
+		})
+		return
+	}
+
+	old := watch.channel
+	watch.channel = channel
+	m.channels[channel.ID] = watch
+	delete(m.channels, old.ID)
+	m.scheduleRenewal(watch)
+
+	go m.stopChannel(old)
+}
+
+// closeWatch forgets a watch and stops its channel. Must be called with m.mu
+// held.
+func (m *SubscriptionManager) closeWatch(watch *calendarWatch) {
+	if watch.renewTimer != nil {
+		watch.renewTimer.Stop()
+	}
+	delete(m.watches, watch.calendarID)
+	delete(m.channels, watch.channel.ID)
+
+	go m.stopChannel(watch.channel)
+}
+
+func (m *SubscriptionManager) stopChannel(channel *types.WatchChannel) {
+	ctx, cancel := context.WithTimeout(context.Background(), channelCallTimeout)
+	defer cancel()
+
+	if err := m.watcher.StopChannel(ctx, channel); err != nil {
+		logrus.Warnf("Failed to stop push channel %s: %v", channel.ID, err)
+		return
+	}
+	logrus.Debugf("Stopped push channel %s for calendar %s", channel.ID, channel.CalendarID)
+}
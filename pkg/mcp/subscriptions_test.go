@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// watchingBackend is a MemoryBackend that can open push channels, recording
+// the ones opened and stopped
+type watchingBackend struct {
+	*calendar.MemoryBackend
+
+	mu      sync.Mutex
+	opened  []*types.WatchChannel
+	stopped chan string // channel IDs
+}
+
+func newWatchingBackend() *watchingBackend {
+	return &watchingBackend{
+		MemoryBackend: calendar.NewMemoryBackend(""),
+		stopped:       make(chan string, 10),
+	}
+}
+
+func (b *watchingBackend) WatchEvents(ctx context.Context, calendarID, channelID, address, token string, ttl time.Duration) (*types.WatchChannel, error) {
+	channel := &types.WatchChannel{
+		ID:         channelID,
+		ResourceID: "resource-" + channelID,
+		CalendarID: calendarID,
+		Token:      token,
+		Expiration: time.Now().Add(ttl),
+	}
+
+	b.mu.Lock()
+	b.opened = append(b.opened, channel)
+	b.mu.Unlock()
+	return channel, nil
+}
+
+func (b *watchingBackend) StopChannel(ctx context.Context, channel *types.WatchChannel) error {
+	b.stopped <- channel.ID
+	return nil
+}
+
+func (b *watchingBackend) channels() []*types.WatchChannel {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*types.WatchChannel(nil), b.opened...)
+}
+
+// waitStopped waits for a channel to be stopped and returns its ID
+func (b *watchingBackend) waitStopped(t *testing.T) string {
+	t.Helper()
+	select {
+	case id := <-b.stopped:
+		return id
+	case <-time.After(2 * time.Second):
+		t.Fatal("no channel was stopped")
+		return ""
+	}
+}
+
+// deliver posts a push notification for channel to the server's webhook
+func deliver(s *Server, channelID, token, state string) int {
+	req := httptest.NewRequest(http.MethodPost, webhookPath, nil)
+	req.Header.Set("X-Goog-Channel-ID", channelID)
+	req.Header.Set("X-Goog-Channel-Token", token)
+	req.Header.Set("X-Goog-Resource-State", state)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// updatedURIs returns the URIs of the resources/updated notifications a
+// session received, sorted
+func updatedURIs(sink *recordingSink) []string {
+	var uris []string
+	for _, msg := range sink.received("notifications/resources/updated") {
+		params, _ := msg["params"].(map[string]interface{})
+		uris = append(uris, fmt.Sprint(params["uri"]))
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+func TestSubscriptions(t *testing.T) {
+	b := newWatchingBackend()
+	s := NewServer(b, &config.Config{WebhookURL: "https://example.com" + webhookPath}, 0)
+	ctxA, sessA, sinkA := newSessionContext(t)
+	ctxB, sessB, sinkB := newSessionContext(t)
+	calendarRes := "gcal://calendars/me@example.com"
+	agenda := calendarRes + "/agenda/today"
+	event := calendarRes + "/events/planning"
+
+	call(t, ctxA, s, "resources/subscribe", fmt.Sprintf(`{"uri":%q}`, calendarRes))
+	call(t, ctxA, s, "resources/subscribe", fmt.Sprintf(`{"uri":%q}`, agenda))
+	call(t, ctxB, s, "resources/subscribe", fmt.Sprintf(`{"uri":%q}`, event))
+
+	// Every resource of a calendar shares one channel
+	channels := b.channels()
+	if len(channels) != 1 || channels[0].CalendarID != "me@example.com" {
+		t.Fatalf("opened channels = %+v, want one for me@example.com", channels)
+	}
+	channel := channels[0]
+
+	if code := deliver(s, channel.ID, channel.Token, "sync"); code != http.StatusOK {
+		t.Errorf("sync notification status = %d, want 200", code)
+	}
+	if code := deliver(s, channel.ID, channel.Token, "exists"); code != http.StatusOK {
+		t.Errorf("change notification status = %d, want 200", code)
+	}
+	if got, want := updatedURIs(sinkA), []string{calendarRes, agenda}; !reflect.DeepEqual(got, want) {
+		t.Errorf("session A updates = %q, want %q", got, want)
+	}
+	if got, want := updatedURIs(sinkB), []string{event}; !reflect.DeepEqual(got, want) {
+		t.Errorf("session B updates = %q, want %q", got, want)
+	}
+
+	if code := deliver(s, channel.ID, "forged", "exists"); code != http.StatusForbidden {
+		t.Errorf("notification with a bad token status = %d, want 403", code)
+	}
+	if code := deliver(s, "unknown", "token", "exists"); code != http.StatusOK {
+		t.Errorf("notification for an unknown channel status = %d, want 200", code)
+	}
+	if code := deliver(s, "", "", ""); code != http.StatusBadRequest {
+		t.Errorf("notification without headers status = %d, want 400", code)
+	}
+	if got := len(sinkA.received("notifications/resources/updated")); got != 2 {
+		t.Errorf("session A got %d updates after rejected notifications, want 2", got)
+	}
+
+	// The channel stays open while anyone is subscribed, and is stopped once
+	// the last subscriber leaves or goes away
+	call(t, ctxA, s, "resources/unsubscribe", fmt.Sprintf(`{"uri":%q}`, calendarRes))
+	call(t, ctxA, s, "resources/unsubscribe", fmt.Sprintf(`{"uri":%q}`, agenda))
+	sessA.close()
+	sessB.close()
+	if id := b.waitStopped(t); id != channel.ID {
+		t.Errorf("stopped channel %s, want %s", id, channel.ID)
+	}
+	if code := deliver(s, channel.ID, channel.Token, "exists"); code != http.StatusOK {
+		t.Errorf("notification for a stopped channel status = %d, want 200", code)
+	}
+	if got := len(sinkB.received("notifications/resources/updated")); got != 1 {
+		t.Errorf("session B got %d updates after the channel stopped, want 1", got)
+	}
+}
+
+func TestSubscriptionsClose(t *testing.T) {
+	b := newWatchingBackend()
+	s := NewServer(b, &config.Config{WebhookURL: "https://example.com" + webhookPath}, 0)
+	ctx, _, _ := newSessionContext(t)
+
+	call(t, ctx, s, "resources/subscribe", `{"uri":"gcal://calendars/primary"}`)
+	if code, _ := callError(t, ctx, s, "resources/subscribe", `{"uri":"https://example.com"}`); code != errCodeResourceNotFound {
+		t.Errorf("subscribe to an unknown URI error = %d, want %d", code, errCodeResourceNotFound)
+	}
+
+	s.subscriptions.Close()
+	channels := b.channels()
+	if id := b.waitStopped(t); len(channels) != 1 || id != channels[0].ID {
+		t.Errorf("Close() stopped %s, want the only channel of %+v", id, channels)
+	}
+}
+
+func TestSubscriptionsDisabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend calendar.Backend
+		cfg     *config.Config
+	}{
+		{"no webhook", newWatchingBackend(), &config.Config{}},
+		{"backend cannot watch", calendar.NewMemoryBackend(""), &config.Config{WebhookURL: "https://example.com" + webhookPath}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(tt.backend, tt.cfg, 0)
+			ctx, _, _ := newSessionContext(t)
+			if code, _ := callError(t, ctx, s, "resources/subscribe", `{"uri":"gcal://calendars/primary"}`); code != errCodeMethodNotFound {
+				t.Errorf("subscribe error = %d, want %d", code, errCodeMethodNotFound)
+			}
+			if code := deliver(s, "channel", "token", "exists"); code != http.StatusNotFound {
+				t.Errorf("webhook status = %d, want 404", code)
+			}
+		})
+	}
+}
@@ -0,0 +1,665 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/schema"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// ToolHandler implements one MCP tool. The built-in calendar tools are
+// ToolHandlers, and packages in other modules can add their own to a server
+// with Register.
+type ToolHandler interface {
+	Name() string
+	Description() string
+	InputSchema() interface{}
+	Annotations() *ToolAnnotations
+
+	// Handle runs the tool. Failures the model should see belong in a
+	// ToolResult with IsError set; a returned error fails the JSON-RPC call.
+	Handle(ctx context.Context, args json.RawMessage) (*ToolResult, error)
+}
+
+// ToolOutputSchema is implemented by ToolHandlers whose results carry
+// structuredContent
+type ToolOutputSchema interface {
+	OutputSchema() interface{}
+}
+
+// NewTool makes a ToolHandler from a tool definition and a function that
+// implements it
+func NewTool(tool Tool, handle func(ctx context.Context, args json.RawMessage) (*ToolResult, error)) ToolHandler {
+	return &funcTool{tool: tool, handle: handle}
+}
+
+type funcTool struct {
+	tool   Tool
+	handle func(ctx context.Context, args json.RawMessage) (*ToolResult, error)
+}
+
+func (t *funcTool) Name() string                  { return t.tool.Name }
+func (t *funcTool) Description() string           { return t.tool.Description }
+func (t *funcTool) InputSchema() interface{}      { return t.tool.InputSchema }
+func (t *funcTool) OutputSchema() interface{}     { return t.tool.OutputSchema }
+func (t *funcTool) Annotations() *ToolAnnotations { return t.tool.Annotations }
+
+func (t *funcTool) Handle(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+	return t.handle(ctx, args)
+}
+
+type ToolRegistry struct {
+	backend calendar.Backend
+
+	mu       sync.RWMutex
+	handlers map[string]ToolHandler
+	order    []string // names in registration order
+
+	// middleware wraps every call, the first entry outermost
+	middleware []Middleware
+
+	// readOnly hides and rejects tools not annotated as read-only
+	readOnly bool
+
+	// listLimit is the default and the largest maxResults of the listing
+	// tools
+	listLimit int
+}
+
+func NewToolRegistry(backend calendar.Backend, readOnly bool) *ToolRegistry {
+	registry := &ToolRegistry{
+		backend:   backend,
+		handlers:  make(map[string]ToolHandler),
+		readOnly:  readOnly,
+		listLimit: config.DefaultListLimit,
+	}
+
+	registry.registerTools()
+	return registry
+}
+
+// Register adds tools to the registry. It fails without registering
+// anything if a tool has no name or its name is already taken.
+func (r *ToolRegistry) Register(handlers ...ToolHandler) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, h := range handlers {
+		name := h.Name()
+		if name == "" {
+			return fmt.Errorf("tool name is required")
+		}
+		if _, exists := r.handlers[name]; exists || seen[name] {
+			return fmt.Errorf("tool %s is already registered", name)
+		}
+		seen[name] = true
+	}
+
+	for _, h := range handlers {
+		r.handlers[h.Name()] = h
+		r.order = append(r.order, h.Name())
+	}
+	return nil
+}
+
+// SetListLimit sets how many events or calendars one list_events,
+// list_instances or list_calendars call returns at most, and by default.
+// Longer event and calendar listings are continued with the cursor in their
+// results.
+func (r *ToolRegistry) SetListLimit(limit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit > 0 {
+		r.listLimit = limit
+	}
+}
+
+// Use appends middleware to the chain every tool call passes through
+func (r *ToolRegistry) Use(middleware ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// mustRegister registers a built-in tool, whose definition cannot be invalid
+func (r *ToolRegistry) mustRegister(h ToolHandler) {
+	if err := r.Register(h); err != nil {
+		panic(err)
+	}
+}
+
+func (r *ToolRegistry) registerTools() {
+	r.mustRegister(NewTool(Tool{
+		Name:         "create_event",
+		Description:  "Creates a new calendar event, optionally recurring",
+		InputSchema:  CreateEventSchema,
+		OutputSchema: EventIDOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Create event",
+			ReadOnlyHint:    false,
+			DestructiveHint: false,
+			IdempotentHint:  false,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleCreateEvent)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "get_event",
+		Description:  "Retrieves a specific calendar event",
+		InputSchema:  GetEventSchema,
+		OutputSchema: EventOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Get event",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleGetEvent)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "update_event",
+		Description:  "Updates an existing calendar event, including its recurrence, or one, the following or all instances of a recurring event",
+		InputSchema:  UpdateEventSchema,
+		OutputSchema: EventIDOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Update event",
+			ReadOnlyHint:    false,
+			DestructiveHint: true,
+			IdempotentHint:  false,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleUpdateEvent)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "delete_event",
+		Description:  "Deletes a calendar event, or one, the following or all instances of a recurring event",
+		InputSchema:  DeleteEventSchema,
+		OutputSchema: EventIDOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Delete event",
+			ReadOnlyHint:    false,
+			DestructiveHint: true,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleDeleteEvent)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "list_events",
+		Description:  "Lists calendar events",
+		InputSchema:  ListEventsSchema,
+		OutputSchema: ListEventsOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "List events",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleListEvents)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "list_instances",
+		Description:  "Lists the instances of a recurring event, whose IDs update_event and delete_event accept with a scope",
+		InputSchema:  ListInstancesSchema,
+		OutputSchema: ListEventsOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "List instances",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleListInstances)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "search_events",
+		Description:  "Searches events across all calendars, or the given ones, ranking matches by relevance in their summary, location, attendees and description",
+		InputSchema:  SearchEventsSchema,
+		OutputSchema: SearchEventsOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Search events",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleSearchEvents)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "list_calendars",
+		Description:  "Lists available calendars",
+		InputSchema:  ListCalendarsSchema,
+		OutputSchema: ListCalendarsOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "List calendars",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleListCalendars)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "get_calendar",
+		Description:  "Retrieves a specific calendar",
+		InputSchema:  GetCalendarSchema,
+		OutputSchema: CalendarOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Get calendar",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleGetCalendar)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "create_calendar",
+		Description:  "Creates a new calendar",
+		InputSchema:  CreateCalendarSchema,
+		OutputSchema: CalendarIDOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Create calendar",
+			ReadOnlyHint:    false,
+			DestructiveHint: false,
+			IdempotentHint:  false,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleCreateCalendar)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "delete_calendar",
+		Description:  "Deletes a calendar",
+		InputSchema:  DeleteCalendarSchema,
+		OutputSchema: CalendarIDOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Delete calendar",
+			ReadOnlyHint:    false,
+			DestructiveHint: true,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleDeleteCalendar)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "get_freebusy",
+		Description:  "Gets free/busy information for calendars",
+		InputSchema:  FreeBusySchema,
+		OutputSchema: FreeBusyOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Get free/busy",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleGetFreeBusy)))
+	
+	r.mustRegister(NewTool(Tool{
+		Name:         "find_meeting_times",
+		Description:  "Finds ranked times when all attendees are free for a meeting, within working hours in each time zone given and with optional buffers between events",
+		InputSchema:  MeetingTimesSchema,
+		OutputSchema: MeetingTimesOutputSchema,
+		Annotations: &ToolAnnotations{
+			Title:           "Find meeting times",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+			OpenWorldHint:   true,
+		},
+	}, typedTool(r.handleFindMeetingTimes)))
+}
+
+func (r *ToolRegistry) ListTools() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(r.order))
+	for _, name := range r.order {
+		if h := r.handlers[name]; r.allowed(h) {
+			tools = append(tools, toolDefinition(h))
+		}
+	}
+	return tools
+}
+
+// toolDefinition describes a handler for tools/list
+func toolDefinition(h ToolHandler) Tool {
+	tool := Tool{
+		Name:        h.Name(),
+		Description: h.Description(),
+		InputSchema: h.InputSchema(),
+		Annotations: h.Annotations(),
+	}
+	if o, ok := h.(ToolOutputSchema); ok {
+		tool.OutputSchema = o.OutputSchema()
+	}
+	return tool
+}
+
+// allowed reports whether a tool may be listed and called in the registry's
+// mode
+func (r *ToolRegistry) allowed(h ToolHandler) bool {
+	if !r.readOnly {
+		return true
+	}
+	annotations := h.Annotations()
+	return annotations != nil && annotations.ReadOnlyHint
+}
+
+func (r *ToolRegistry) CallTool(ctx context.Context, name string, args json.RawMessage) (*ToolResult, error) {
+	r.mu.RLock()
+	h, exists := r.handlers[name]
+	middleware := r.middleware
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown tool: %s", name)
+	}
+	if !r.allowed(h) {
+		return nil, fmt.Errorf("tool %s modifies calendars and is disabled in read-only mode", name)
+	}
+
+	handle := chain(callHandler, middleware...)
+	return handle(ctx, &ToolRequest{Name: name, Arguments: args, Tool: h})
+}
+
+// callHandler validates the arguments of a call and runs its tool. It is the
+// innermost Handler of every chain.
+func callHandler(ctx context.Context, req *ToolRequest) (*ToolResult, error) {
+	if inputSchema, ok := req.Tool.InputSchema().(map[string]interface{}); ok {
+		if violations := schema.ValidateJSON(inputSchema, req.Arguments); len(violations) > 0 {
+			return invalidArgumentsResult(req.Name, violations), nil
+		}
+	}
+	return req.Tool.Handle(ctx, req.Arguments)
+}
+
+// invalidArgumentsResult reports every schema violation in a tool's
+// arguments, so the caller can fix them all in one go
+func invalidArgumentsResult(name string, violations []schema.Violation) *ToolResult {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Invalid arguments for %s:", name)
+	for _, violation := range violations {
+		fmt.Fprintf(&b, "\n- %s", violation)
+	}
+	return errorResult(b.String())
+}
+
+// typedTool adapts a handler that takes decoded arguments to NewTool. Errors
+// the handler returns are reported to the model as tool errors rather than
+// failing the call.
+func typedTool[A any](handle func(ctx context.Context, args *A) (*ToolResult, error)) func(context.Context, json.RawMessage) (*ToolResult, error) {
+	return func(ctx context.Context, raw json.RawMessage) (*ToolResult, error) {
+		var args A
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, fmt.Errorf("invalid arguments: %w", err)
+			}
+		}
+
+		result, err := handle(ctx, &args)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		return result, nil
+	}
+}
+
+// errorResult is a tool result reporting a failure to the model
+func errorResult(text string) *ToolResult {
+	return &ToolResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+		IsError: true,
+	}
+}
+
+// textResult is a successful tool result with a short text rendering
+func textResult(text string, structured interface{}) *ToolResult {
+	return &ToolResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+		StructuredContent: structured,
+	}
+}
+
+// jsonResult renders v as indented JSON text next to the structured result
+func jsonResult(v interface{}, structured interface{}) *ToolResult {
+	data, _ := json.MarshalIndent(v, "", "  ")
+	return textResult(string(data), structured)
+}
+
+// pageResult is jsonResult for one page of a listing under key, telling the
+// model how to continue it when nextCursor is set
+func pageResult(tool, key string, items interface{}, nextCursor string) *ToolResult {
+	structured := map[string]interface{}{key: items}
+	result := jsonResult(items, structured)
+	if nextCursor != "" {
+		structured["nextCursor"] = nextCursor
+		result.Content = append(result.Content, Content{
+			Type: "text",
+			Text: fmt.Sprintf("More %s follow. Call %s again with the same arguments and cursor %q to continue.", key, tool, nextCursor),
+		})
+	}
+	return result
+}
+
+// eventsStaleness notes on result how old the events it was answered from
+// are, when the backend answers from copies synced earlier. The oldest of
+// the calendars that were synced counts; results about others report them
+// as failed.
+func (r *ToolRegistry) eventsStaleness(result *ToolResult, calendarIDs ...string) *ToolResult {
+	s, ok := r.backend.(calendar.Staleness)
+	if !ok {
+		return result
+	}
+	var syncedAt time.Time
+	for _, calendarID := range calendarIDs {
+		if t := s.EventsSyncedAt(calendarID); !t.IsZero() && (syncedAt.IsZero() || t.Before(syncedAt)) {
+			syncedAt = t
+		}
+	}
+	return staleResult(result, syncedAt)
+}
+
+// calendarsStaleness is eventsStaleness for the calendar list
+func (r *ToolRegistry) calendarsStaleness(result *ToolResult) *ToolResult {
+	s, ok := r.backend.(calendar.Staleness)
+	if !ok {
+		return result
+	}
+	return staleResult(result, s.CalendarsSyncedAt())
+}
+
+// staleResult tells the model that result may be out of date, and puts
+// when its data was synced in _meta. A zero syncedAt means it never was.
+func staleResult(result *ToolResult, syncedAt time.Time) *ToolResult {
+	meta := map[string]interface{}{"offline": true}
+	text := "Answered offline from data that was never synced; it may be incomplete."
+	if !syncedAt.IsZero() {
+		age := time.Since(syncedAt).Round(time.Second)
+		meta["syncedAt"] = syncedAt.UTC().Format(time.RFC3339)
+		meta["ageSeconds"] = int64(age.Seconds())
+		text = fmt.Sprintf("Answered offline from data synced at %s (%s ago); it may be out of date.", syncedAt.Format(time.RFC3339), age)
+	}
+	result.Meta = meta
+	result.Content = append(result.Content, Content{Type: "text", Text: text})
+	return result
+}
+
+// limit returns the maxResults to list with, given the requested one
+func (r *ToolRegistry) limit(maxResults int) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if maxResults <= 0 || maxResults > r.listLimit {
+		return r.listLimit
+	}
+	return maxResults
+}
+
+func (r *ToolRegistry) handleCreateEvent(ctx context.Context, args *types.CreateEventArgs) (*ToolResult, error) {
+	eventID, err := r.backend.CreateEvent(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+	return textResult(fmt.Sprintf("Event created successfully with ID: %s", eventID), map[string]interface{}{"eventId": eventID}), nil
+}
+
+func (r *ToolRegistry) handleGetEvent(ctx context.Context, args *types.GetEventArgs) (*ToolResult, error) {
+	event, err := r.backend.GetEvent(ctx, args.CalendarID, args.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return r.eventsStaleness(jsonResult(event, event), args.CalendarID), nil
+}
+
+func (r *ToolRegistry) handleUpdateEvent(ctx context.Context, args *types.UpdateEventArgs) (*ToolResult, error) {
+	eventID, err := calendar.UpdateEventInScope(ctx, r.backend, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update event: %w", err)
+	}
+	if eventID != args.EventID {
+		// The change landed on the series or on a new series split off it
+		return textResult(fmt.Sprintf("Event %s updated successfully in recurring event %s", args.EventID, eventID), map[string]interface{}{"eventId": eventID}), nil
+	}
+	return textResult(fmt.Sprintf("Event %s updated successfully", args.EventID), map[string]interface{}{"eventId": args.EventID}), nil
+}
+
+func (r *ToolRegistry) handleDeleteEvent(ctx context.Context, args *types.DeleteEventArgs) (*ToolResult, error) {
+	if err := calendar.DeleteEventInScope(ctx, r.backend, args); err != nil {
+		return nil, fmt.Errorf("failed to delete event: %w", err)
+	}
+	return textResult(fmt.Sprintf("Event %s deleted successfully", args.EventID), map[string]interface{}{"eventId": args.EventID}), nil
+}
+
+func (r *ToolRegistry) handleListEvents(ctx context.Context, args *types.ListEventsArgs) (*ToolResult, error) {
+	args.MaxResults = r.limit(args.MaxResults)
+
+	events, next, err := r.backend.ListEvents(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	if events == nil {
+		events = []*types.CalendarEvent{}
+	}
+	return r.eventsStaleness(pageResult("list_events", "events", events, next), args.CalendarID), nil
+}
+
+func (r *ToolRegistry) handleListInstances(ctx context.Context, args *types.ListInstancesArgs) (*ToolResult, error) {
+	args.MaxResults = r.limit(args.MaxResults)
+
+	instances, next, err := r.backend.ListInstances(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+	if instances == nil {
+		instances = []*types.CalendarEvent{}
+	}
+	return r.eventsStaleness(pageResult("list_instances", "events", instances, next), args.CalendarID), nil
+}
+
+func (r *ToolRegistry) handleSearchEvents(ctx context.Context, args *types.SearchEventsArgs) (*ToolResult, error) {
+	args.MaxResults = r.limit(args.MaxResults)
+
+	result, err := calendar.SearchEvents(ctx, r.backend, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	toolResult := jsonResult(result.Events, result)
+	if len(result.FailedCalendars) > 0 {
+		failed := make([]string, 0, len(result.FailedCalendars))
+		for calendarID, reason := range result.FailedCalendars {
+			failed = append(failed, fmt.Sprintf("%s (%s)", calendarID, reason))
+		}
+		sort.Strings(failed)
+		toolResult.Content = append(toolResult.Content, Content{
+			Type: "text",
+			Text: fmt.Sprintf("Could not search %d of %d calendars: %s", len(failed), len(result.SearchedCalendars), strings.Join(failed, "; ")),
+		})
+	}
+	return r.eventsStaleness(toolResult, result.SearchedCalendars...), nil
+}
+
+func (r *ToolRegistry) handleListCalendars(ctx context.Context, args *types.ListCalendarsArgs) (*ToolResult, error) {
+	args.MaxResults = r.limit(args.MaxResults)
+
+	calendars, next, err := r.backend.ListCalendars(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendars: %w", err)
+	}
+	if calendars == nil {
+		calendars = []*types.Calendar{}
+	}
+	return r.calendarsStaleness(pageResult("list_calendars", "calendars", calendars, next)), nil
+}
+
+func (r *ToolRegistry) handleGetCalendar(ctx context.Context, args *types.GetCalendarArgs) (*ToolResult, error) {
+	calendar, err := r.backend.GetCalendar(ctx, args.CalendarID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar: %w", err)
+	}
+	return r.calendarsStaleness(jsonResult(calendar, calendar)), nil
+}
+
+func (r *ToolRegistry) handleCreateCalendar(ctx context.Context, args *types.CreateCalendarArgs) (*ToolResult, error) {
+	calendarID, err := r.backend.CreateCalendar(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar: %w", err)
+	}
+	return textResult(fmt.Sprintf("Calendar created successfully with ID: %s", calendarID), map[string]interface{}{"calendarId": calendarID}), nil
+}
+
+func (r *ToolRegistry) handleDeleteCalendar(ctx context.Context, args *types.DeleteCalendarArgs) (*ToolResult, error) {
+	if err := r.backend.DeleteCalendar(ctx, args.CalendarID); err != nil {
+		return nil, fmt.Errorf("failed to delete calendar: %w", err)
+	}
+	return textResult(fmt.Sprintf("Calendar %s deleted successfully", args.CalendarID), map[string]interface{}{"calendarId": args.CalendarID}), nil
+}
+
+func (r *ToolRegistry) handleGetFreeBusy(ctx context.Context, args *types.FreeBusyArgs) (*ToolResult, error) {
+	response, err := r.backend.GetFreeBusy(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get free/busy: %w", err)
+	}
+	return r.eventsStaleness(jsonResult(response, response), args.CalendarIDs...), nil
+}
+
+func (r *ToolRegistry) handleFindMeetingTimes(ctx context.Context, args *types.FindMeetingTimesArgs) (*ToolResult, error) {
+	result, err := calendar.FindMeetingTimes(ctx, r.backend, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find meeting times: %w", err)
+	}
+
+	toolResult := jsonResult(result.Slots, result)
+	if len(result.Slots) == 0 {
+		toolResult.Content[0].Text = "No time in the window suits every attendee. Try a longer window, a shorter meeting, a smaller buffer or wider working hours."
+	}
+	if len(result.UncheckedAttendees) > 0 {
+		unchecked := make([]string, 0, len(result.UncheckedAttendees))
+		for attendee, reason := range result.UncheckedAttendees {
+			unchecked = append(unchecked, fmt.Sprintf("%s (%s)", attendee, reason))
+		}
+		sort.Strings(unchecked)
+		toolResult.Content = append(toolResult.Content, Content{
+			Type: "text",
+			Text: fmt.Sprintf("Could not check the calendars of %s, so these times may not suit them.", strings.Join(unchecked, "; ")),
+		})
+	}
+	return r.eventsStaleness(toolResult, args.Attendees...), nil
+}
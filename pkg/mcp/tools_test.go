@@ -0,0 +1,345 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/calendar"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/config"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/schema"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// builtinTools are the registry's own tools in the order tools/list gives
+// them
+var builtinTools = []string{
+	"create_event",
+	"get_event",
+	"update_event",
+	"delete_event",
+	"list_events",
+	"list_instances",
+	"search_events",
+	"list_calendars",
+	"get_calendar",
+	"create_calendar",
+	"delete_calendar",
+	"get_freebusy",
+	"find_meeting_times",
+}
+
+// echoTool returns a tool that answers with its raw arguments
+func echoTool(name string, inputSchema map[string]interface{}, annotations *ToolAnnotations) ToolHandler {
+	return NewTool(Tool{Name: name, InputSchema: inputSchema, Annotations: annotations},
+		func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+			return textResult(string(args), nil), nil
+		})
+}
+
+func toolNames(tools []Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+func TestRegister(t *testing.T) {
+	readOnly := &ToolAnnotations{ReadOnlyHint: true}
+
+	tests := []struct {
+		name     string
+		handlers []ToolHandler
+		wantErr  string
+		want     []string // tools added after the built-in ones
+	}{
+		{
+			name:     "new tools in order",
+			handlers: []ToolHandler{echoTool("zeta", nil, readOnly), echoTool("alpha", nil, readOnly)},
+			want:     []string{"zeta", "alpha"},
+		},
+		{
+			name:     "built-in name",
+			handlers: []ToolHandler{echoTool("get_event", nil, readOnly)},
+			wantErr:  "tool get_event is already registered",
+		},
+		{
+			name:     "duplicate in one call",
+			handlers: []ToolHandler{echoTool("echo", nil, readOnly), echoTool("echo", nil, readOnly)},
+			wantErr:  "tool echo is already registered",
+		},
+		{
+			name:     "nothing registered when one fails",
+			handlers: []ToolHandler{echoTool("echo", nil, readOnly), echoTool("", nil, readOnly)},
+			wantErr:  "tool name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewToolRegistry(calendar.NewMemoryBackend(""), false)
+			err := r.Register(tt.handlers...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Register() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+
+			want := append(append([]string{}, builtinTools...), tt.want...)
+			if got := toolNames(r.ListTools()); !reflect.DeepEqual(got, want) {
+				t.Errorf("ListTools() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBuiltinToolAnnotations(t *testing.T) {
+	r := NewToolRegistry(calendar.NewMemoryBackend(""), false)
+	for _, tool := range r.ListTools() {
+		if tool.Annotations == nil || !tool.Annotations.OpenWorldHint {
+			t.Errorf("%s annotations = %+v, want openWorldHint since it reaches Google", tool.Name, tool.Annotations)
+		}
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		tool     string
+		readOnly bool
+		listed   bool
+	}{
+		{tool: "list_events", readOnly: true, listed: true},
+		{tool: "create_event", readOnly: true, listed: false},
+		{tool: "delete_calendar", readOnly: true, listed: false},
+		{tool: "unannotated", readOnly: true, listed: false},
+		{tool: "create_event", readOnly: false, listed: true},
+		{tool: "unannotated", readOnly: false, listed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/readOnly=%v", tt.tool, tt.readOnly), func(t *testing.T) {
+			r := NewToolRegistry(calendar.NewMemoryBackend(""), tt.readOnly)
+			if err := r.Register(echoTool("unannotated", nil, nil)); err != nil {
+				t.Fatal(err)
+			}
+
+			listed := false
+			for _, name := range toolNames(r.ListTools()) {
+				listed = listed || name == tt.tool
+			}
+			if listed != tt.listed {
+				t.Errorf("%s listed = %v, want %v", tt.tool, listed, tt.listed)
+			}
+
+			_, err := r.CallTool(context.Background(), tt.tool, json.RawMessage(`{}`))
+			rejected := err != nil && strings.Contains(err.Error(), "disabled in read-only mode")
+			if rejected == tt.listed {
+				t.Errorf("%s call rejected = %v (%v), want %v", tt.tool, rejected, err, !tt.listed)
+			}
+		})
+	}
+}
+
+func TestCallToolValidatesArguments(t *testing.T) {
+	r := NewToolRegistry(calendar.NewMemoryBackend(""), false)
+	err := r.Register(echoTool("echo", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"text":  map[string]interface{}{"type": "string"},
+			"times": map[string]interface{}{"type": "integer", "minimum": 1},
+		},
+		"required": []string{"text"},
+	}, &ToolAnnotations{ReadOnlyHint: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    string
+		wantErr bool   // the call itself fails
+		isError bool   // the tool reports an error result
+		want    string // text of the result
+	}{
+		{name: "valid", tool: "echo", args: `{"text":"hi","times":2}`, want: `{"text":"hi","times":2}`},
+		{name: "missing argument", tool: "echo", args: `{}`, isError: true, want: "Invalid arguments for echo:\n- $.text: is required"},
+		{name: "omitted arguments", tool: "echo", args: ``, isError: true, want: "Invalid arguments for echo:\n- $.text: is required"},
+		{
+			name:    "every violation",
+			tool:    "echo",
+			args:    `{"text":1,"times":0}`,
+			isError: true,
+			want:    "Invalid arguments for echo:\n- $.text: must be of type string, got number\n- $.times: must be at least 1",
+		},
+		{
+			name:    "built-in tool",
+			tool:    "get_event",
+			args:    `{"calendarId":"primary"}`,
+			isError: true,
+			want:    "Invalid arguments for get_event:\n- $.eventId: is required",
+		},
+		{name: "unknown tool", tool: "missing", args: `{}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.CallTool(context.Background(), tt.tool, json.RawMessage(tt.args))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("CallTool() = %+v, want an error", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			if result.IsError != tt.isError {
+				t.Errorf("IsError = %v, want %v", result.IsError, tt.isError)
+			}
+			if got := result.Content[0].Text; got != tt.want {
+				t.Errorf("result = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListInstancesLimit(t *testing.T) {
+	backend := calendar.NewMemoryBackend("")
+	id, err := backend.CreateEvent(context.Background(), &types.CreateEventArgs{
+		Summary:    "Standup",
+		StartTime:  "2025-01-06T09:00:00Z",
+		EndTime:    "2025-01-06T09:15:00Z",
+		Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=30"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewToolRegistry(backend, false)
+	r.SetListLimit(20)
+
+	list := func(t *testing.T, args *types.ListInstancesArgs) ([]*types.CalendarEvent, string) {
+		t.Helper()
+		data, _ := json.Marshal(args)
+		result, err := r.CallTool(context.Background(), "list_instances", data)
+		if err != nil || result.IsError {
+			t.Fatalf("CallTool() = %+v, %v", result, err)
+		}
+		structured := result.StructuredContent.(map[string]interface{})
+		next, _ := structured["nextCursor"].(string)
+		return structured["events"].([]*types.CalendarEvent), next
+	}
+
+	tests := []struct {
+		maxResults int
+		want       int
+	}{
+		{maxResults: 0, want: 20},
+		{maxResults: 5, want: 5},
+		{maxResults: 25, want: 20},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("maxResults=%d", tt.maxResults), func(t *testing.T) {
+			events, next := list(t, &types.ListInstancesArgs{EventID: id, MaxResults: tt.maxResults})
+			if len(events) != tt.want {
+				t.Errorf("list_instances returned %d instances, want %d", len(events), tt.want)
+			}
+
+			// The cursor continues the listing where it stopped
+			total := len(events)
+			for next != "" {
+				events, next = list(t, &types.ListInstancesArgs{EventID: id, MaxResults: tt.maxResults, Cursor: next})
+				total += len(events)
+			}
+			if total != 30 {
+				t.Errorf("list_instances pages returned %d instances, want 30", total)
+			}
+		})
+	}
+}
+
+func TestStructuredOutput(t *testing.T) {
+	backend := calendar.NewMemoryBackend("")
+	ctx := context.Background()
+	standup, err := backend.CreateEvent(ctx, &types.CreateEventArgs{
+		Summary:    "Standup",
+		StartTime:  "2025-01-06T09:00:00Z",
+		EndTime:    "2025-01-06T09:15:00Z",
+		Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doomed, err := backend.CreateEvent(ctx, &types.CreateEventArgs{
+		Summary:   "Cancelled",
+		StartTime: "2025-01-06T14:00:00Z",
+		EndTime:   "2025-01-06T15:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := backend.CreateCalendar(ctx, &types.CreateCalendarArgs{Summary: "Old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(backend, &config.Config{}, 0)
+	if err := s.Register(echoTool("echo", nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every built-in tool declares its structured output
+	schemas := make(map[string]map[string]interface{})
+	for _, tool := range call(t, ctx, s, "tools/list", "")["tools"].([]interface{}) {
+		tool := tool.(map[string]interface{})
+		outputSchema, _ := tool["outputSchema"].(map[string]interface{})
+		schemas[tool["name"].(string)] = outputSchema
+	}
+	for _, name := range builtinTools {
+		if schemas[name] == nil {
+			t.Errorf("%s has no outputSchema", name)
+		}
+	}
+	if _, ok := schemas["echo"]; !ok || schemas["echo"] != nil {
+		t.Errorf("echo outputSchema = %v, want none", schemas["echo"])
+	}
+
+	calls := []struct {
+		tool string
+		args string
+	}{
+		{"create_event", `{"summary":"Review","startTime":"2025-01-07T10:00:00Z","endTime":"2025-01-07T11:00:00Z"}`},
+		{"get_event", fmt.Sprintf(`{"eventId":%q}`, standup)},
+		{"update_event", fmt.Sprintf(`{"eventId":%q,"location":"Room 1"}`, standup)},
+		{"delete_event", fmt.Sprintf(`{"eventId":%q}`, doomed)},
+		{"list_events", `{"maxResults":1}`},
+		{"list_instances", fmt.Sprintf(`{"eventId":%q}`, standup)},
+		{"search_events", `{"query":"standup"}`},
+		{"list_calendars", `{}`},
+		{"get_calendar", `{}`},
+		{"create_calendar", `{"summary":"Team"}`},
+		{"delete_calendar", fmt.Sprintf(`{"calendarId":%q}`, old)},
+		{"get_freebusy", `{"timeMin":"2025-01-06T00:00:00Z","timeMax":"2025-01-07T00:00:00Z","calendarIds":["primary"]}`},
+		{"find_meeting_times", `{"attendees":["primary"],"durationMinutes":30,"timeMin":"2025-01-06T00:00:00Z","timeMax":"2025-01-08T00:00:00Z"}`},
+	}
+	for _, c := range calls {
+		t.Run(c.tool, func(t *testing.T) {
+			result := call(t, ctx, s, "tools/call", fmt.Sprintf(`{"name":%q,"arguments":%s}`, c.tool, c.args))
+			if result["isError"] == true {
+				t.Fatalf("%s failed: %v", c.tool, result["content"])
+			}
+			structured, ok := result["structuredContent"]
+			if !ok {
+				t.Fatalf("%s returned no structuredContent", c.tool)
+			}
+			if violations := schema.Validate(schemas[c.tool], structured); len(violations) > 0 {
+				t.Errorf("%s structuredContent does not match its outputSchema: %v", c.tool, violations)
+			}
+		})
+	}
+}
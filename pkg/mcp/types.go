@@ -0,0 +1,361 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/schema"
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+// Tool represents an MCP tool definition
+type Tool struct {
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	InputSchema  interface{}      `json:"inputSchema"`
+	OutputSchema interface{}      `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations describe a tool's behaviour to clients. They are hints for
+// deciding what needs confirmation, not guarantees.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    bool   `json:"readOnlyHint"`
+	DestructiveHint bool   `json:"destructiveHint"`
+	IdempotentHint  bool   `json:"idempotentHint"`
+	OpenWorldHint   bool   `json:"openWorldHint"`
+}
+
+// ToolCallRequest represents a request to call a tool
+type ToolCallRequest struct {
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ToolResult represents the result of a tool execution
+type ToolResult struct {
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
+
+	// Meta carries information about the result itself rather than its
+	// content, such as how old offline answers are
+	Meta map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// Content represents content in a tool result
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// InputSchema definitions for calendar tools, generated from the argument
+// types the handlers decode into so that the two cannot drift apart
+var (
+	CreateEventSchema    = schema.FromStruct(types.CreateEventArgs{})
+	GetEventSchema       = schema.FromStruct(types.GetEventArgs{})
+	UpdateEventSchema    = schema.FromStruct(types.UpdateEventArgs{})
+	DeleteEventSchema    = schema.FromStruct(types.DeleteEventArgs{})
+	ListEventsSchema     = schema.FromStruct(types.ListEventsArgs{})
+	ListInstancesSchema  = schema.FromStruct(types.ListInstancesArgs{})
+	SearchEventsSchema   = schema.FromStruct(types.SearchEventsArgs{})
+	ListCalendarsSchema  = schema.FromStruct(types.ListCalendarsArgs{})
+	GetCalendarSchema    = schema.FromStruct(types.GetCalendarArgs{})
+	CreateCalendarSchema = schema.FromStruct(types.CreateCalendarArgs{})
+	DeleteCalendarSchema = schema.FromStruct(types.DeleteCalendarArgs{})
+	FreeBusySchema       = schema.FromStruct(types.FreeBusyArgs{})
+	MeetingTimesSchema   = schema.FromStruct(types.FindMeetingTimesArgs{})
+)
+
+// Resource represents an MCP resource the server can read
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceTemplate represents a parameterised family of resources
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents represents the contents of a read resource
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// Prompt represents an MCP prompt template definition
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument represents an argument a prompt template accepts
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptMessage represents a message produced by a prompt template
+type PromptMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+// GetPromptResult represents a rendered prompt
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// OutputSchema definitions for calendar tools. They describe the
+// structuredContent each tool returns next to its text rendering.
+var (
+	calendarEventSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id":            map[string]interface{}{"type": "string", "description": "Event ID"},
+			"summary":       map[string]interface{}{"type": "string", "description": "Event title/summary"},
+			"description":   map[string]interface{}{"type": "string", "description": "Event description"},
+			"location":      map[string]interface{}{"type": "string", "description": "Event location"},
+			"startTime":     map[string]interface{}{"type": "string", "description": "Start time in RFC3339 format, for timed events"},
+			"endTime":       map[string]interface{}{"type": "string", "description": "End time in RFC3339 format, for timed events"},
+			"startDate":     map[string]interface{}{"type": "string", "description": "Start date (YYYY-MM-DD), for all-day events"},
+			"endDate":       map[string]interface{}{"type": "string", "description": "Exclusive end date (YYYY-MM-DD), for all-day events"},
+			"startTimeZone": map[string]interface{}{"type": "string", "description": "Time zone of the start time"},
+			"endTimeZone":   map[string]interface{}{"type": "string", "description": "Time zone of the end time"},
+			"allDay":        map[string]interface{}{"type": "boolean", "description": "Whether this is an all-day event"},
+			"creator":       map[string]interface{}{"type": "string", "description": "Email of the event's creator"},
+			"organizer":     map[string]interface{}{"type": "string", "description": "Email of the event's organizer"},
+			"status":        map[string]interface{}{"type": "string", "description": "confirmed, tentative or cancelled"},
+			"htmlLink":      map[string]interface{}{"type": "string", "description": "Link to the event in Google Calendar"},
+			"created":       map[string]interface{}{"type": "string", "description": "Creation time in RFC3339 format"},
+			"updated":       map[string]interface{}{"type": "string", "description": "Last modification time in RFC3339 format"},
+			"recurrence": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "RFC 5545 recurrence lines, for recurring events",
+			},
+			"recurringEventId":  map[string]interface{}{"type": "string", "description": "ID of the recurring event, for its instances"},
+			"originalStartTime": map[string]interface{}{"type": "string", "description": "Start time or date of the instance in its series, for instances of recurring events"},
+			"attendees": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"email":          map[string]interface{}{"type": "string"},
+						"displayName":    map[string]interface{}{"type": "string"},
+						"responseStatus": map[string]interface{}{"type": "string"},
+						"organizer":      map[string]interface{}{"type": "boolean"},
+					},
+					"required": []string{"email"},
+				},
+			},
+			"reminders": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"method":  map[string]interface{}{"type": "string"},
+						"minutes": map[string]interface{}{"type": "integer"},
+					},
+					"required": []string{"method", "minutes"},
+				},
+			},
+		},
+		"required": []string{"id", "summary"},
+	}
+
+	calendarSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id":          map[string]interface{}{"type": "string", "description": "Calendar ID"},
+			"summary":     map[string]interface{}{"type": "string", "description": "Calendar title"},
+			"description": map[string]interface{}{"type": "string", "description": "Calendar description"},
+			"primary":     map[string]interface{}{"type": "boolean", "description": "Whether this is the user's primary calendar"},
+			"accessRole":  map[string]interface{}{"type": "string", "description": "The user's access role on the calendar"},
+			"timeZone":    map[string]interface{}{"type": "string", "description": "Calendar time zone"},
+		},
+		"required": []string{"id", "summary"},
+	}
+
+	eventMatchSchema = extendSchema(calendarEventSchema, map[string]interface{}{
+		"calendarId":      map[string]interface{}{"type": "string", "description": "ID of the calendar the event was found in"},
+		"calendarSummary": map[string]interface{}{"type": "string", "description": "Title of the calendar the event was found in"},
+		"alsoInCalendars": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "IDs of other searched calendars the event is on",
+		},
+		"score":         map[string]interface{}{"type": "number", "description": "Relevance to the query; higher is better"},
+		"matchedFields": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Fields containing query terms: summary, location, attendees or description"},
+	}, "calendarId", "score")
+
+	nextCursorSchema = map[string]interface{}{
+		"type":        "string",
+		"description": "Present when more results follow; pass it back as cursor to continue the listing",
+	}
+
+	EventOutputSchema = calendarEventSchema
+
+	EventIDOutputSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"eventId": map[string]interface{}{
+				"type":        "string",
+				"description": "ID of the affected event",
+			},
+		},
+		"required": []string{"eventId"},
+	}
+
+	ListEventsOutputSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"events": map[string]interface{}{
+				"type":  "array",
+				"items": calendarEventSchema,
+			},
+			"nextCursor": nextCursorSchema,
+		},
+		"required": []string{"events"},
+	}
+
+	SearchEventsOutputSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"events": map[string]interface{}{
+				"type":  "array",
+				"items": eventMatchSchema,
+			},
+			"searchedCalendars": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "IDs of the calendars searched",
+			},
+			"failedCalendars": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Errors keyed by the ID of each calendar that could not be searched",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+		},
+		"required": []string{"events", "searchedCalendars"},
+	}
+
+	CalendarOutputSchema = calendarSchema
+
+	CalendarIDOutputSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"calendarId": map[string]interface{}{
+				"type":        "string",
+				"description": "ID of the affected calendar",
+			},
+		},
+		"required": []string{"calendarId"},
+	}
+
+	ListCalendarsOutputSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"calendars": map[string]interface{}{
+				"type":  "array",
+				"items": calendarSchema,
+			},
+			"nextCursor": nextCursorSchema,
+		},
+		"required": []string{"calendars"},
+	}
+
+	FreeBusyOutputSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"timeMin": map[string]interface{}{"type": "string", "description": "Start of the queried range"},
+			"timeMax": map[string]interface{}{"type": "string", "description": "End of the queried range"},
+			"calendars": map[string]interface{}{
+				"type":        "object",
+				"description": "Busy periods keyed by calendar ID",
+				"additionalProperties": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"busy": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"start": map[string]interface{}{"type": "string"},
+									"end":   map[string]interface{}{"type": "string"},
+								},
+								"required": []string{"start", "end"},
+							},
+						},
+						"errors": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Why the calendar could not be queried, e.g. notFound; its busy periods are then unknown",
+						},
+					},
+					"required": []string{"busy"},
+				},
+			},
+		},
+		"required": []string{"timeMin", "timeMax", "calendars"},
+	}
+
+	MeetingTimesOutputSchema = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"slots": map[string]interface{}{
+				"type":        "array",
+				"description": "Times every attendee is free, best first",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"start": map[string]interface{}{"type": "string", "description": "Start time in RFC3339 format"},
+						"end":   map[string]interface{}{"type": "string", "description": "End time in RFC3339 format"},
+						"score": map[string]interface{}{"type": "number", "description": "How good the slot is, from 0 to 1"},
+						"localTimes": map[string]interface{}{
+							"type":                 "object",
+							"description":          "The slot in each working hours' time zone, keyed by time zone",
+							"additionalProperties": map[string]interface{}{"type": "string"},
+						},
+					},
+					"required": []string{"start", "end", "score"},
+				},
+			},
+			"uncheckedAttendees": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Why the calendars of these attendees could not be checked; the slots may not suit them",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+		},
+		"required": []string{"slots"},
+	}
+)
+
+// extendSchema returns a copy of the object schema base with more
+// properties, some of them required
+func extendSchema(base map[string]interface{}, properties map[string]interface{}, required ...string) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for name, property := range base["properties"].(map[string]interface{}) {
+		merged[name] = property
+	}
+	for name, property := range properties {
+		merged[name] = property
+	}
+
+	extended := make(map[string]interface{})
+	for key, value := range base {
+		extended[key] = value
+	}
+	extended["properties"] = merged
+	extended["required"] = append(append([]string{}, base["required"].([]string)...), required...)
+	return extended
+}
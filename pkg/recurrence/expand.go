@@ -0,0 +1,298 @@
+package recurrence
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// maxPeriods bounds how many periods of a rule are examined, so that
+	// rules which never match or never end cannot loop forever
+	maxPeriods = 100000
+
+	// maxOccurrences bounds how many occurrences one expansion collects
+	maxOccurrences = 10000
+)
+
+// Between returns the start times of the occurrences of an event first
+// starting at dtstart with from <= t < to, in order, and at most limit of
+// them when limit is positive. dtstart must be in the event's time zone,
+// which floating and date-only dates are read in. The first occurrence is
+// always dtstart itself.
+func (s *Set) Between(dtstart, from, to time.Time, limit int) []time.Time {
+	loc := dtstart.Location()
+
+	// Collect enough occurrences to survive the exclusions
+	want := 0
+	if limit > 0 && len(s.ExRules) == 0 {
+		want = limit + len(s.ExDates)
+	}
+
+	times := []time.Time{dtstart}
+	for _, rule := range s.RRules {
+		times = append(times, rule.occurrences(dtstart, from, to, want)...)
+	}
+	for _, date := range s.RDates {
+		times = append(times, date.at(dtstart))
+	}
+	times = sortTimes(times)
+
+	var excluded []time.Time
+	for _, rule := range s.ExRules {
+		excluded = append(excluded, rule.occurrences(dtstart, from, to, 0)...)
+	}
+	var excludedDays []time.Time
+	for _, date := range s.ExDates {
+		if date.DateOnly {
+			excludedDays = append(excludedDays, date.in(loc))
+		} else {
+			excluded = append(excluded, date.in(loc))
+		}
+	}
+
+	var result []time.Time
+	for _, t := range times {
+		if t.Before(from) || !t.Before(to) || containsTime(excluded, t) || containsDay(excludedDays, t.In(loc)) {
+			continue
+		}
+		result = append(result, t)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result
+}
+
+// at returns the occurrence d adds to an event starting at dtstart. Dates
+// without a time take the time of day of dtstart.
+func (d Date) at(dtstart time.Time) time.Time {
+	if d.DateOnly {
+		t := d.Time
+		return time.Date(t.Year(), t.Month(), t.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+	return d.in(dtstart.Location())
+}
+
+// occurrences generates the rule's occurrences from dtstart on and returns
+// those before to, from from on. COUNT counts occurrences before from too.
+// When want is positive, generation stops once that many are collected.
+func (r *Rule) occurrences(dtstart, from, to time.Time, want int) []time.Time {
+	until := r.Until
+	if r.UntilDate {
+		// A date covers the whole day in the event's time zone
+		until = time.Date(until.Year(), until.Month(), until.Day(), 23, 59, 59, 0, dtstart.Location())
+	}
+
+	var result []time.Time
+	count := 0
+	for k := 0; k < maxPeriods; k++ {
+		start, candidates := r.period(dtstart, k)
+		if !start.Before(to) {
+			break
+		}
+		for _, t := range candidates {
+			if t.Before(dtstart) {
+				continue
+			}
+			if (!until.IsZero() && t.After(until)) || (r.Count > 0 && count == r.Count) || !t.Before(to) {
+				return result
+			}
+			count++
+			if t.Before(from) {
+				continue
+			}
+			result = append(result, t)
+			if (want > 0 && len(result) >= want) || len(result) >= maxOccurrences {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// period returns the start of the kth period of the rule and the candidate
+// occurrences in it, in order
+func (r *Rule) period(dtstart time.Time, k int) (time.Time, []time.Time) {
+	loc := dtstart.Location()
+	y, m, d := dtstart.Date()
+	hour, min, sec := dtstart.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, min, sec, 0, loc)
+	}
+	step := k * r.Interval
+
+	switch r.Freq {
+	case "DAILY":
+		day := at(y, m, d+step)
+		if r.matchesMonth(day.Month()) && r.matchesMonthDay(day) && r.matchesWeekday(day.Weekday()) {
+			return day, []time.Time{day}
+		}
+		return day, nil
+
+	case "WEEKLY":
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := at(y, m, d-offset+7*step)
+		var candidates []time.Time
+		for i := 0; i < 7; i++ {
+			day := at(weekStart.Year(), weekStart.Month(), weekStart.Day()+i)
+			if len(r.ByDay) > 0 {
+				if !r.matchesWeekday(day.Weekday()) {
+					continue
+				}
+			} else if day.Weekday() != dtstart.Weekday() {
+				continue
+			}
+			if r.matchesMonth(day.Month()) {
+				candidates = append(candidates, day)
+			}
+		}
+		return weekStart, candidates
+
+	case "MONTHLY":
+		first := at(y, m+time.Month(step), 1)
+		if !r.matchesMonth(first.Month()) {
+			return first, nil
+		}
+		return first, r.monthDays(first, d)
+
+	default: // YEARLY
+		first := at(y+step, time.January, 1)
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []int{int(m)}
+		}
+		var candidates []time.Time
+		for _, month := range months {
+			candidates = append(candidates, r.monthDays(at(first.Year(), time.Month(month), 1), d)...)
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].Before(candidates[j])
+		})
+		return first, candidates
+	}
+}
+
+// monthDays returns the days of the month starting at first that the rule's
+// BYMONTHDAY and BYDAY select, or defaultDay when it has neither
+func (r *Rule) monthDays(first time.Time, defaultDay int) []time.Time {
+	n := daysIn(first)
+	day := func(d int) time.Time {
+		return first.AddDate(0, 0, d-1)
+	}
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if defaultDay > n {
+			return nil
+		}
+		return []time.Time{day(defaultDay)}
+	}
+
+	selected := make(map[int]bool)
+	if len(r.ByMonthDay) > 0 {
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d = n + 1 + d
+			}
+			if d >= 1 && d <= n {
+				selected[d] = true
+			}
+		}
+	}
+	if len(r.ByDay) > 0 {
+		byDay := make(map[int]bool)
+		for _, weekday := range r.ByDay {
+			var matches []int
+			for d := 1; d <= n; d++ {
+				if day(d).Weekday() == weekday.Day {
+					matches = append(matches, d)
+				}
+			}
+			switch {
+			case weekday.Ordinal == 0:
+				for _, d := range matches {
+					byDay[d] = true
+				}
+			case weekday.Ordinal > 0 && weekday.Ordinal <= len(matches):
+				byDay[matches[weekday.Ordinal-1]] = true
+			case weekday.Ordinal < 0 && -weekday.Ordinal <= len(matches):
+				byDay[matches[len(matches)+weekday.Ordinal]] = true
+			}
+		}
+		if len(r.ByMonthDay) > 0 {
+			for d := range selected {
+				if !byDay[d] {
+					delete(selected, d)
+				}
+			}
+		} else {
+			selected = byDay
+		}
+	}
+
+	var days []time.Time
+	for d := 1; d <= n; d++ {
+		if selected[d] {
+			days = append(days, day(d))
+		}
+	}
+	return days
+}
+
+func (r *Rule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if time.Month(m) == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	n := daysIn(t)
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || (d < 0 && n+1+d == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(day time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, weekday := range r.ByDay {
+		if weekday.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, candidate := range times {
+		if candidate.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDay(days []time.Time, t time.Time) bool {
+	for _, day := range days {
+		if day.Year() == t.Year() && day.YearDay() == t.YearDay() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,472 @@
+// Package recurrence parses, validates, builds and expands the RFC 5545
+// recurrence lines Google Calendar stores on recurring events: RRULE,
+// EXRULE, RDATE and EXDATE.
+//
+// Rules support FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT,
+// UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST. Anything else is rejected
+// rather than silently misread.
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+)
+
+// Frequencies Google Calendar supports
+var frequencies = map[string]bool{
+	"DAILY":   true,
+	"WEEKLY":  true,
+	"MONTHLY": true,
+	"YEARLY":  true,
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Weekday is a BYDAY entry. A non-zero Ordinal selects the nth (or, when
+// negative, nth last) such day of the month.
+type Weekday struct {
+	Ordinal int
+	Day     time.Weekday
+}
+
+func (w Weekday) String() string {
+	if w.Ordinal != 0 {
+		return strconv.Itoa(w.Ordinal) + weekdayNames[w.Day]
+	}
+	return weekdayNames[w.Day]
+}
+
+// Rule is a parsed RRULE or EXRULE
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	UntilDate  bool // Until was given as a date
+	ByDay      []Weekday
+	ByMonthDay []int
+	ByMonth    []int
+	WeekStart  time.Weekday
+}
+
+// ParseRule parses the value of an RRULE or EXRULE line, such as
+// "FREQ=WEEKLY;BYDAY=MO,WE"
+func ParseRule(value string) (*Rule, error) {
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("malformed rule part %q", part)
+		}
+		name = strings.ToUpper(name)
+		if seen[name] {
+			return nil, fmt.Errorf("%s is given twice", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+			if !frequencies[rule.Freq] {
+				err = fmt.Errorf("unsupported frequency %s", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = positiveInt(val)
+		case "COUNT":
+			rule.Count, err = positiveInt(val)
+		case "UNTIL":
+			rule.Until, rule.UntilDate, err = parseUntil(val)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseInts(val, -31, 31)
+		case "BYMONTH":
+			rule.ByMonth, err = parseInts(val, 1, 12)
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(val)]
+			if !ok {
+				err = fmt.Errorf("invalid weekday %q", val)
+			}
+			rule.WeekStart = day
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Freq == "" {
+		return fmt.Errorf("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("COUNT and UNTIL cannot both be given")
+	}
+	for _, day := range r.ByDay {
+		if day.Ordinal != 0 && r.Freq != "MONTHLY" && r.Freq != "YEARLY" {
+			return fmt.Errorf("BYDAY ordinals such as %s are only allowed in MONTHLY and YEARLY rules", day)
+		}
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq == "WEEKLY" {
+		return fmt.Errorf("BYMONTHDAY is not allowed in WEEKLY rules")
+	}
+	if r.Freq == "YEARLY" && (len(r.ByDay) > 0 || len(r.ByMonthDay) > 0) && len(r.ByMonth) == 0 {
+		return fmt.Errorf("YEARLY rules with BYDAY or BYMONTHDAY also need BYMONTH")
+	}
+	return nil
+}
+
+// String formats the rule as the value of an RRULE line
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.UntilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format(dateFormat))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format(dateTimeFormat)+"Z")
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// Date is an RDATE or EXDATE value
+type Date struct {
+	Time time.Time
+
+	// DateOnly dates match every occurrence on that day
+	DateOnly bool
+
+	// Floating times have no zone of their own and are read in the
+	// event's time zone
+	Floating bool
+}
+
+// in returns the instant of d in the event time zone loc
+func (d Date) in(loc *time.Location) time.Time {
+	if !d.DateOnly && !d.Floating {
+		return d.Time
+	}
+	t := d.Time
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
+
+// Set is the parsed recurrence of an event
+type Set struct {
+	RRules  []*Rule
+	ExRules []*Rule
+	RDates  []Date
+	ExDates []Date
+}
+
+// Parse parses and validates the recurrence lines of an event
+func Parse(lines []string) (*Set, error) {
+	set := &Set{}
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed recurrence line %q", line)
+		}
+		name, params, _ := strings.Cut(name, ";")
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "RRULE":
+			var rule *Rule
+			if rule, err = ParseRule(value); err == nil {
+				set.RRules = append(set.RRules, rule)
+			}
+		case "EXRULE":
+			var rule *Rule
+			if rule, err = ParseRule(value); err == nil {
+				set.ExRules = append(set.ExRules, rule)
+			}
+		case "RDATE":
+			var dates []Date
+			if dates, err = parseDates(params, value); err == nil {
+				set.RDates = append(set.RDates, dates...)
+			}
+		case "EXDATE":
+			var dates []Date
+			if dates, err = parseDates(params, value); err == nil {
+				set.ExDates = append(set.ExDates, dates...)
+			}
+		default:
+			err = fmt.Errorf("unsupported property %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence line %q: %w", line, err)
+		}
+	}
+
+	if len(set.RRules) == 0 && len(set.RDates) == 0 && (len(set.ExRules) > 0 || len(set.ExDates) > 0) {
+		return nil, fmt.Errorf("EXRULE and EXDATE need an RRULE or RDATE to exclude from")
+	}
+	return set, nil
+}
+
+// Lines combines hand-written recurrence lines with a structured rule into
+// the validated recurrence of an event. It returns nil when there is none.
+func Lines(lines []string, rule *types.RecurrenceRule, allDay bool) ([]string, error) {
+	combined := append([]string(nil), lines...)
+	if rule != nil {
+		line, err := Build(rule, allDay)
+		if err != nil {
+			return nil, err
+		}
+		combined = append(combined, line)
+	}
+	if len(combined) == 0 {
+		return nil, nil
+	}
+	if _, err := Parse(combined); err != nil {
+		return nil, err
+	}
+	return combined, nil
+}
+
+// Build turns a structured rule into an RRULE line. A date given as until
+// covers that whole day: it becomes an UNTIL date for all-day events and the
+// last second of the day in UTC otherwise.
+func Build(spec *types.RecurrenceRule, allDay bool) (string, error) {
+	rule := &Rule{
+		Freq:       strings.ToUpper(spec.Frequency),
+		Interval:   spec.Interval,
+		Count:      spec.Count,
+		ByMonthDay: spec.ByMonthDay,
+		ByMonth:    spec.ByMonth,
+		WeekStart:  time.Monday,
+	}
+	if !frequencies[rule.Freq] {
+		return "", fmt.Errorf("invalid recurrence rule: unsupported frequency %q", spec.Frequency)
+	}
+	if rule.Interval == 0 {
+		rule.Interval = 1
+	}
+	if rule.Interval < 0 || rule.Count < 0 {
+		return "", fmt.Errorf("invalid recurrence rule: interval and count must be positive")
+	}
+	if len(spec.ByDay) > 0 {
+		days, err := parseByDay(strings.Join(spec.ByDay, ","))
+		if err != nil {
+			return "", fmt.Errorf("invalid recurrence rule: byDay: %w", err)
+		}
+		rule.ByDay = days
+	}
+	if err := checkInts(spec.ByMonthDay, -31, 31); err != nil {
+		return "", fmt.Errorf("invalid recurrence rule: byMonthDay: %w", err)
+	}
+	if err := checkInts(spec.ByMonth, 1, 12); err != nil {
+		return "", fmt.Errorf("invalid recurrence rule: byMonth: %w", err)
+	}
+
+	if spec.Until != "" {
+		if date, err := time.Parse("2006-01-02", spec.Until); err == nil {
+			if allDay {
+				rule.Until, rule.UntilDate = date, true
+			} else {
+				rule.Until = date.Add(24*time.Hour - time.Second)
+			}
+		} else if t, err := time.Parse(time.RFC3339, spec.Until); err == nil {
+			rule.Until = t.UTC()
+		} else {
+			return "", fmt.Errorf("invalid recurrence rule: until must be a date (YYYY-MM-DD) or an RFC3339 time, got %q", spec.Until)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return "", fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+	return "RRULE:" + rule.String(), nil
+}
+
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateFormat, value); err == nil {
+		return t, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		if t, err := time.Parse(dateTimeFormat, strings.TrimSuffix(value, "Z")); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid date %q, expected YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
+
+func parseByDay(value string) ([]Weekday, error) {
+	var days []Weekday
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		weekday := Weekday{Day: day}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(prefix, "+"))
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid weekday %q", item)
+			}
+			weekday.Ordinal = n
+		}
+		days = append(days, weekday)
+	}
+	return days, nil
+}
+
+func parseInts(value string, min, max int) ([]int, error) {
+	var ints []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", item)
+		}
+		ints = append(ints, n)
+	}
+	return ints, checkInts(ints, min, max)
+}
+
+// checkInts checks that ints are non-zero and between min and max
+func checkInts(ints []int, min, max int) error {
+	for _, n := range ints {
+		if n == 0 || n < min || n > max {
+			return fmt.Errorf("%d is not between %d and %d", n, min, max)
+		}
+	}
+	return nil
+}
+
+func positiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive integer", value)
+	}
+	return n, nil
+}
+
+func joinInts(ints []int) string {
+	items := make([]string, len(ints))
+	for i, n := range ints {
+		items[i] = strconv.Itoa(n)
+	}
+	return strings.Join(items, ",")
+}
+
+// parseDates parses the parameters and value of an RDATE or EXDATE line
+func parseDates(params, value string) ([]Date, error) {
+	var loc *time.Location
+	dateOnly := false
+	if params != "" {
+		for _, param := range strings.Split(params, ";") {
+			name, val, _ := strings.Cut(param, "=")
+			switch strings.ToUpper(name) {
+			case "TZID":
+				var err error
+				if loc, err = time.LoadLocation(val); err != nil {
+					return nil, fmt.Errorf("unknown time zone %q", val)
+				}
+			case "VALUE":
+				switch strings.ToUpper(val) {
+				case "DATE":
+					dateOnly = true
+				case "DATE-TIME":
+				default:
+					return nil, fmt.Errorf("unsupported value type %s", val)
+				}
+			default:
+				return nil, fmt.Errorf("unsupported parameter %s", name)
+			}
+		}
+	}
+
+	var dates []Date
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		var date Date
+		var err error
+		switch {
+		case dateOnly || len(item) == len(dateFormat):
+			date.Time, err = time.Parse(dateFormat, item)
+			date.DateOnly = true
+		case strings.HasSuffix(item, "Z"):
+			date.Time, err = time.Parse(dateTimeFormat, strings.TrimSuffix(item, "Z"))
+		case loc != nil:
+			date.Time, err = time.ParseInLocation(dateTimeFormat, item, loc)
+		default:
+			date.Time, err = time.Parse(dateTimeFormat, item)
+			date.Floating = true
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", item)
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// sortTimes sorts times and removes duplicate instants
+func sortTimes(times []time.Time) []time.Time {
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	unique := times[:0]
+	for i, t := range times {
+		if i == 0 || !t.Equal(unique[len(unique)-1]) {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,411 @@
+package recurrence
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phildougherty/mcp-google-calendar-go/pkg/types"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string // the rule formatted back
+		wantErr string
+	}{
+		{value: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{value: "freq=weekly;byday=mo,we;interval=2", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{value: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=6", want: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=6"},
+		{value: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29", want: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29"},
+		{value: "FREQ=WEEKLY;WKST=SU;UNTIL=20250131", want: "FREQ=WEEKLY;WKST=SU;UNTIL=20250131"},
+		{value: "FREQ=DAILY;UNTIL=20250131T235959Z", want: "FREQ=DAILY;UNTIL=20250131T235959Z"},
+		{value: "INTERVAL=2", wantErr: "FREQ is required"},
+		{value: "FREQ=HOURLY", wantErr: "unsupported frequency"},
+		{value: "FREQ=DAILY;COUNT=0", wantErr: "COUNT"},
+		{value: "FREQ=DAILY;COUNT=2;UNTIL=20250131", wantErr: "COUNT and UNTIL cannot both be given"},
+		{value: "FREQ=DAILY;FREQ=WEEKLY", wantErr: "FREQ is given twice"},
+		{value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: "only allowed in MONTHLY and YEARLY rules"},
+		{value: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: "BYMONTHDAY is not allowed in WEEKLY rules"},
+		{value: "FREQ=YEARLY;BYDAY=MO", wantErr: "also need BYMONTH"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: "BYMONTHDAY"},
+		{value: "FREQ=DAILY;BYHOUR=9", wantErr: "unsupported rule part BYHOUR"},
+		{value: "FREQ=DAILY;COUNT", wantErr: "malformed rule part"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := ParseRule(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseRule() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRule() error = %v", err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("ParseRule().String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    types.RecurrenceRule
+		allDay  bool
+		want    string
+		wantErr string
+	}{
+		{
+			name: "weekly",
+			spec: types.RecurrenceRule{Frequency: "weekly", ByDay: []string{"MO", "we"}},
+			want: "RRULE:FREQ=WEEKLY;BYDAY=MO,WE",
+		},
+		{
+			name: "monthly with count",
+			spec: types.RecurrenceRule{Frequency: "MONTHLY", Interval: 3, ByDay: []string{"1MO"}, Count: 4},
+			want: "RRULE:FREQ=MONTHLY;INTERVAL=3;BYDAY=1MO;COUNT=4",
+		},
+		{
+			name: "until a date",
+			spec: types.RecurrenceRule{Frequency: "DAILY", Until: "2025-01-31"},
+			want: "RRULE:FREQ=DAILY;UNTIL=20250131T235959Z",
+		},
+		{
+			name:   "all-day until a date",
+			spec:   types.RecurrenceRule{Frequency: "DAILY", Until: "2025-01-31"},
+			allDay: true,
+			want:   "RRULE:FREQ=DAILY;UNTIL=20250131",
+		},
+		{
+			name: "until a time",
+			spec: types.RecurrenceRule{Frequency: "DAILY", Until: "2025-01-31T10:00:00+01:00"},
+			want: "RRULE:FREQ=DAILY;UNTIL=20250131T090000Z",
+		},
+		{
+			name:    "unknown frequency",
+			spec:    types.RecurrenceRule{Frequency: "HOURLY"},
+			wantErr: "unsupported frequency",
+		},
+		{
+			name:    "bad until",
+			spec:    types.RecurrenceRule{Frequency: "DAILY", Until: "next week"},
+			wantErr: "until must be a date",
+		},
+		{
+			name:    "count and until",
+			spec:    types.RecurrenceRule{Frequency: "DAILY", Count: 2, Until: "2025-01-31"},
+			wantErr: "COUNT and UNTIL cannot both be given",
+		},
+		{
+			name:    "bad month",
+			spec:    types.RecurrenceRule{Frequency: "YEARLY", ByMonth: []int{13}},
+			wantErr: "byMonth",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Build(&tt.spec, tt.allDay)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Build() = %q, %v; want an error containing %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		rule    *types.RecurrenceRule
+		want    []string
+		wantErr bool
+	}{
+		{name: "none"},
+		{
+			name:  "lines only",
+			lines: []string{"RRULE:FREQ=DAILY", "EXDATE:20250107T100000Z"},
+			want:  []string{"RRULE:FREQ=DAILY", "EXDATE:20250107T100000Z"},
+		},
+		{
+			name:  "rule appended",
+			lines: []string{"EXDATE;VALUE=DATE:20250107"},
+			rule:  &types.RecurrenceRule{Frequency: "DAILY", Count: 5},
+			want:  []string{"EXDATE;VALUE=DATE:20250107", "RRULE:FREQ=DAILY;COUNT=5"},
+		},
+		{name: "exclusions only", lines: []string{"EXDATE:20250107T100000Z"}, wantErr: true},
+		{name: "unknown property", lines: []string{"DTSTART:20250106T100000Z"}, wantErr: true},
+		{name: "bad rule", rule: &types.RecurrenceRule{Frequency: "SECONDLY"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Lines(tt.lines, tt.rule, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lines() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// formatTimes formats times in loc, for comparing expansions
+func formatTimes(times []time.Time, loc *time.Location) []string {
+	var out []string
+	for _, t := range times {
+		out = append(out, t.In(loc).Format(time.RFC3339))
+	}
+	return out
+}
+
+func TestBetween(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Monday, 10:00 in New York
+	dtstart := time.Date(2025, time.January, 6, 10, 0, 0, 0, newYork)
+
+	tests := []struct {
+		name     string
+		lines    []string
+		from, to time.Time
+		limit    int
+		want     []string
+	}{
+		{
+			name:  "count",
+			lines: []string{"RRULE:FREQ=DAILY;COUNT=3"},
+			want:  []string{"2025-01-06T10:00:00-05:00", "2025-01-07T10:00:00-05:00", "2025-01-08T10:00:00-05:00"},
+		},
+		{
+			name:  "weekdays until a date",
+			lines: []string{"RRULE:FREQ=WEEKLY;BYDAY=MO,FR;UNTIL=20250117"},
+			want: []string{
+				"2025-01-06T10:00:00-05:00", "2025-01-10T10:00:00-05:00",
+				"2025-01-13T10:00:00-05:00", "2025-01-17T10:00:00-05:00",
+			},
+		},
+		{
+			name:  "in range",
+			lines: []string{"RRULE:FREQ=DAILY;COUNT=10"},
+			from:  time.Date(2025, time.January, 8, 15, 0, 0, 0, time.UTC),
+			to:    time.Date(2025, time.January, 10, 15, 0, 0, 0, time.UTC),
+			want:  []string{"2025-01-08T10:00:00-05:00", "2025-01-09T10:00:00-05:00"},
+		},
+		{
+			name:  "limited",
+			lines: []string{"RRULE:FREQ=DAILY"},
+			limit: 2,
+			want:  []string{"2025-01-06T10:00:00-05:00", "2025-01-07T10:00:00-05:00"},
+		},
+		{
+			name: "exdate",
+			lines: []string{
+				"RRULE:FREQ=DAILY;COUNT=4",
+				"EXDATE:20250107T150000Z",
+				"EXDATE;VALUE=DATE:20250108",
+			},
+			want: []string{"2025-01-06T10:00:00-05:00", "2025-01-09T10:00:00-05:00"},
+		},
+		{
+			name: "exdate in the event's time zone",
+			lines: []string{
+				"RRULE:FREQ=DAILY;COUNT=3",
+				"EXDATE;TZID=America/New_York:20250107T100000",
+				"EXDATE:20250108T100000",
+			},
+			want: []string{"2025-01-06T10:00:00-05:00"},
+		},
+		{
+			name: "rdate",
+			lines: []string{
+				"RRULE:FREQ=WEEKLY;COUNT=2",
+				"RDATE:20250108T170000Z",
+				"RDATE;VALUE=DATE:20250109",
+			},
+			want: []string{
+				"2025-01-06T10:00:00-05:00", "2025-01-08T12:00:00-05:00",
+				"2025-01-09T10:00:00-05:00", "2025-01-13T10:00:00-05:00",
+			},
+		},
+		{
+			name:  "exrule",
+			lines: []string{"RRULE:FREQ=DAILY;COUNT=7", "EXRULE:FREQ=WEEKLY;BYDAY=TU,TH"},
+			want: []string{
+				"2025-01-06T10:00:00-05:00", "2025-01-08T10:00:00-05:00",
+				"2025-01-10T10:00:00-05:00", "2025-01-11T10:00:00-05:00",
+				"2025-01-12T10:00:00-05:00",
+			},
+		},
+		{
+			name:  "exdate with limit",
+			lines: []string{"RRULE:FREQ=DAILY", "EXDATE:20250107T150000Z"},
+			limit: 2,
+			want:  []string{"2025-01-06T10:00:00-05:00", "2025-01-08T10:00:00-05:00"},
+		},
+		{
+			name:  "last friday of the month",
+			lines: []string{"RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=3"},
+			want: []string{
+				"2025-01-06T10:00:00-05:00", "2025-01-31T10:00:00-05:00",
+				"2025-02-28T10:00:00-05:00", "2025-03-28T10:00:00-04:00",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := Parse(tt.lines)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			to := tt.to
+			if to.IsZero() {
+				to = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+			}
+			got := formatTimes(set.Between(dtstart, tt.from, to, tt.limit), newYork)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Between() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetweenMaxOccurrences(t *testing.T) {
+	set, err := Parse([]string{"RRULE:FREQ=DAILY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dtstart := time.Date(2025, time.January, 6, 10, 0, 0, 0, time.UTC)
+	forever := time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	if got := len(set.Between(dtstart, time.Time{}, forever, 0)); got != maxOccurrences {
+		t.Errorf("Between() of an endless rule = %d occurrences, want %d", got, maxOccurrences)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	dtstart := time.Date(2025, time.January, 6, 10, 0, 0, 0, time.UTC)
+	at := time.Date(2025, time.January, 9, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		lines         []string
+		allDay        bool
+		before, after []string
+	}{
+		{
+			name:   "count shared out",
+			lines:  []string{"RRULE:FREQ=DAILY;COUNT=10"},
+			before: []string{"RRULE:FREQ=DAILY;UNTIL=20250109T095959Z"},
+			after:  []string{"RRULE:FREQ=DAILY;COUNT=7"},
+		},
+		{
+			name:   "count used up",
+			lines:  []string{"RRULE:FREQ=DAILY;COUNT=3"},
+			before: []string{"RRULE:FREQ=DAILY;UNTIL=20250109T095959Z"},
+		},
+		{
+			name:   "endless",
+			lines:  []string{"RRULE:FREQ=WEEKLY;BYDAY=MO,TH"},
+			before: []string{"RRULE:FREQ=WEEKLY;BYDAY=MO,TH;UNTIL=20250109T095959Z"},
+			after:  []string{"RRULE:FREQ=WEEKLY;BYDAY=MO,TH"},
+		},
+		{
+			name:   "earlier until kept",
+			lines:  []string{"RRULE:FREQ=DAILY;UNTIL=20250107T100000Z"},
+			before: []string{"RRULE:FREQ=DAILY;UNTIL=20250107T100000Z"},
+			after:  []string{"RRULE:FREQ=DAILY;UNTIL=20250107T100000Z"},
+		},
+		{
+			name:   "all day",
+			lines:  []string{"RRULE:FREQ=DAILY;COUNT=5"},
+			allDay: true,
+			before: []string{"RRULE:FREQ=DAILY;UNTIL=20250108"},
+			after:  []string{"RRULE:FREQ=DAILY;COUNT=2"},
+		},
+		{
+			name: "dates go to their side",
+			lines: []string{
+				"RRULE:FREQ=DAILY",
+				"EXDATE:20250107T100000Z,20250110T100000Z",
+				"RDATE;VALUE=DATE:20250120",
+			},
+			before: []string{"RRULE:FREQ=DAILY;UNTIL=20250109T095959Z", "EXDATE:20250107T100000Z"},
+			after:  []string{"RRULE:FREQ=DAILY", "EXDATE:20250110T100000Z", "RDATE;VALUE=DATE:20250120"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after, err := Split(tt.lines, dtstart, at, tt.allDay)
+			if err != nil {
+				t.Fatalf("Split() error = %v", err)
+			}
+			if !reflect.DeepEqual(before, tt.before) {
+				t.Errorf("before = %q, want %q", before, tt.before)
+			}
+			if !reflect.DeepEqual(after, tt.after) {
+				t.Errorf("after = %q, want %q", after, tt.after)
+			}
+		})
+	}
+
+	// Together the two series have the occurrences of the original
+	set, _ := Parse([]string{"RRULE:FREQ=DAILY;COUNT=10"})
+	before, after, _ := Split([]string{"RRULE:FREQ=DAILY;COUNT=10"}, dtstart, at, false)
+	head, _ := Parse(before)
+	tail, _ := Parse(after)
+	end := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	got := append(head.Between(dtstart, time.Time{}, end, 0), tail.Between(at, time.Time{}, end, 0)...)
+	if want := set.Between(dtstart, time.Time{}, end, 0); !reflect.DeepEqual(formatTimes(got, time.UTC), formatTimes(want, time.UTC)) {
+		t.Errorf("split series = %q, want %q", formatTimes(got, time.UTC), formatTimes(want, time.UTC))
+	}
+}
+
+func TestInstanceID(t *testing.T) {
+	start := time.Date(2025, time.January, 6, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		id     string
+		allDay bool
+		want   string
+	}{
+		{"abc", false, "abc_20250106T090000Z"},
+		{"abc", true, "abc_20250106"},
+		{"with_underscore", false, "with_underscore_20250106T090000Z"},
+	}
+	for _, tt := range tests {
+		id := InstanceID(tt.id, start, tt.allDay)
+		if id != tt.want {
+			t.Errorf("InstanceID(%s, allDay %v) = %q, want %q", tt.id, tt.allDay, id, tt.want)
+		}
+		eventID, parsed, allDay, ok := ParseInstanceID(id)
+		if !ok || eventID != tt.id || allDay != tt.allDay {
+			t.Errorf("ParseInstanceID(%q) = %q, %v, %v, want %q, %v, true", id, eventID, allDay, ok, tt.id, tt.allDay)
+		}
+		if !tt.allDay && !parsed.Equal(start) {
+			t.Errorf("ParseInstanceID(%q) start = %v, want %v", id, parsed, start)
+		}
+	}
+	if _, _, _, ok := ParseInstanceID("plain"); ok {
+		t.Error("ParseInstanceID(plain) succeeded")
+	}
+}
@@ -0,0 +1,113 @@
+package recurrence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InstanceID returns the ID Google gives the occurrence of the recurring
+// event eventID that originally starts at start
+func InstanceID(eventID string, start time.Time, allDay bool) string {
+	if allDay {
+		return eventID + "_" + start.Format(dateFormat)
+	}
+	return eventID + "_" + start.UTC().Format(dateTimeFormat) + "Z"
+}
+
+// ParseInstanceID splits an instance ID into the ID of its recurring event
+// and its original start. The start of an all-day instance is midnight UTC
+// of its date.
+func ParseInstanceID(id string) (eventID string, start time.Time, allDay bool, ok bool) {
+	i := strings.LastIndex(id, "_")
+	if i <= 0 {
+		return "", time.Time{}, false, false
+	}
+	eventID, suffix := id[:i], id[i+1:]
+	if t, err := time.Parse(dateFormat, suffix); err == nil {
+		return eventID, t, true, true
+	}
+	if strings.HasSuffix(suffix, "Z") {
+		if t, err := time.Parse(dateTimeFormat, strings.TrimSuffix(suffix, "Z")); err == nil {
+			return eventID, t, false, true
+		}
+	}
+	return "", time.Time{}, false, false
+}
+
+// Split divides the recurrence of a series first starting at dtstart at its
+// occurrence at: before is the recurrence of the series truncated to end
+// before at, and after that of a new series starting at at. COUNT is shared
+// out between the two, and RDATE and EXDATE values go to the side they fall
+// on.
+func Split(lines []string, dtstart, at time.Time, allDay bool) (before, after []string, err error) {
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, nil, fmt.Errorf("malformed recurrence line %q", line)
+		}
+		property, params, _ := strings.Cut(name, ";")
+
+		switch strings.ToUpper(property) {
+		case "RRULE", "EXRULE":
+			rule, err := ParseRule(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid recurrence line %q: %w", line, err)
+			}
+			head, tail := rule.split(dtstart, at, allDay)
+			before = append(before, name+":"+head.String())
+			if tail != nil {
+				after = append(after, name+":"+tail.String())
+			}
+
+		case "RDATE", "EXDATE":
+			dates, err := parseDates(params, value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid recurrence line %q: %w", line, err)
+			}
+			var early, late []string
+			for i, item := range strings.Split(value, ",") {
+				if dates[i].at(dtstart).Before(at) {
+					early = append(early, strings.TrimSpace(item))
+				} else {
+					late = append(late, strings.TrimSpace(item))
+				}
+			}
+			if len(early) > 0 {
+				before = append(before, name+":"+strings.Join(early, ","))
+			}
+			if len(late) > 0 {
+				after = append(after, name+":"+strings.Join(late, ","))
+			}
+
+		default:
+			return nil, nil, fmt.Errorf("invalid recurrence line %q: unsupported property %s", line, property)
+		}
+	}
+	return before, after, nil
+}
+
+// split returns the rule ending before at and the rule continuing from at,
+// which is nil if the rule has no occurrences left
+func (r *Rule) split(dtstart, at time.Time, allDay bool) (*Rule, *Rule) {
+	head, tail := *r, *r
+
+	head.Count = 0
+	if allDay {
+		y, m, d := at.Date()
+		head.Until, head.UntilDate = time.Date(y, m, d-1, 0, 0, 0, 0, time.UTC), true
+	} else {
+		head.Until, head.UntilDate = at.Add(-time.Second).UTC(), false
+	}
+	if !r.Until.IsZero() && r.Until.Before(head.Until) {
+		head.Until, head.UntilDate = r.Until, r.UntilDate
+	}
+
+	if r.Count > 0 {
+		tail.Count = r.Count - len(r.occurrences(dtstart, time.Time{}, at, 0))
+		if tail.Count <= 0 {
+			return &head, nil
+		}
+	}
+	return &head, &tail
+}
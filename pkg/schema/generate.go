@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FromStruct builds the JSON Schema of the struct v, which is typically the
+// zero value of a tool's argument type. Properties are named by their json
+// tags and described by these struct tags:
+//
+//	description:"..."   human readable description
+//	required:"true"     the property must be present
+//	enum:"a,b,c"        allowed values
+//	format:"date-time"  string format (date-time, date, email)
+//	minimum:"1"         lower bound for numbers
+//
+// On slices, enum and format apply to the items. FromStruct panics if v is
+// not a struct, since argument types are fixed at compile time.
+func FromStruct(v interface{}) map[string]interface{} {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("schema: FromStruct needs a struct, got %T", v))
+	}
+	return objectSchema(t)
+}
+
+func objectSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	addFields(t, properties, &required)
+
+	s := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = fieldSchema(field)
+		if field.Tag.Get("required") == "true" {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func fieldSchema(field reflect.StructField) map[string]interface{} {
+	s := typeSchema(field.Type)
+
+	// enum and format describe the elements of a list
+	target := s
+	if items, ok := s["items"].(map[string]interface{}); ok {
+		target = items
+	}
+	if enum := field.Tag.Get("enum"); enum != "" {
+		target["enum"] = strings.Split(enum, ",")
+	}
+	if format := field.Tag.Get("format"); format != "" {
+		target["format"] = format
+	}
+
+	if description := field.Tag.Get("description"); description != "" {
+		s["description"] = description
+	}
+	if minimum := field.Tag.Get("minimum"); minimum != "" {
+		n, err := strconv.ParseFloat(minimum, 64)
+		if err != nil {
+			panic(fmt.Sprintf("schema: invalid minimum %q on field %s", minimum, field.Name))
+		}
+		s["minimum"] = n
+	}
+	return s
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": typeSchema(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem()),
+		}
+	case reflect.Struct:
+		return objectSchema(t)
+	}
+	// interface{} and anything else accepts any JSON value
+	return map[string]interface{}{}
+}
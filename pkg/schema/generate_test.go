@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testReminder struct {
+	Method  string `json:"method" required:"true" enum:"email,popup"`
+	Minutes int    `json:"minutes" minimum:"0"`
+}
+
+type testPaging struct {
+	PageToken string `json:"pageToken,omitempty" description:"Token of the page to return"`
+}
+
+type testArgs struct {
+	testPaging
+	CalendarID string            `json:"calendarId" required:"true" description:"Calendar ID"`
+	Start      string            `json:"start" format:"date-time"`
+	Attendees  []string          `json:"attendees,omitempty" format:"email"`
+	Days       []string          `json:"days,omitempty" enum:"MO,TU"`
+	Reminders  []*testReminder   `json:"reminders,omitempty"`
+	Primary    *bool             `json:"primary,omitempty"`
+	Ratio      float64           `json:"ratio"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Extra      interface{}       `json:"extra,omitempty"`
+	Untagged   string
+	Ignored    string `json:"-"`
+	internal   string
+}
+
+func TestFromStruct(t *testing.T) {
+	got := FromStruct(&testArgs{})
+	want := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"pageToken":  map[string]interface{}{"type": "string", "description": "Token of the page to return"},
+			"calendarId": map[string]interface{}{"type": "string", "description": "Calendar ID"},
+			"start":      map[string]interface{}{"type": "string", "format": "date-time"},
+			"attendees": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "format": "email"},
+			},
+			"days": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "enum": []string{"MO", "TU"}},
+			},
+			"reminders": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"method":  map[string]interface{}{"type": "string", "enum": []string{"email", "popup"}},
+						"minutes": map[string]interface{}{"type": "integer", "minimum": float64(0)},
+					},
+					"required": []string{"method"},
+				},
+			},
+			"primary": map[string]interface{}{"type": "boolean"},
+			"ratio":   map[string]interface{}{"type": "number"},
+			"labels": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"extra":    map[string]interface{}{},
+			"Untagged": map[string]interface{}{"type": "string"},
+		},
+		"required": []string{"calendarId"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromStruct() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestFromStructValidates(t *testing.T) {
+	s := FromStruct(testArgs{})
+	tests := []struct {
+		name string
+		args string
+		want []Violation
+	}{
+		{
+			name: "valid",
+			args: `{"calendarId": "primary", "reminders": [{"method": "popup", "minutes": 10}]}`,
+		},
+		{
+			name: "missing required field",
+			args: `{"start": "2024-01-15T10:00:00Z"}`,
+			want: []Violation{{Path: "$.calendarId", Message: "is required"}},
+		},
+		{
+			name: "nested enum",
+			args: `{"calendarId": "primary", "reminders": [{"method": "sms"}]}`,
+			want: []Violation{{Path: "$.reminders[0].method", Message: `must be one of "email", "popup"`}},
+		},
+		{
+			name: "item enum",
+			args: `{"calendarId": "primary", "days": ["MO", "SU"]}`,
+			want: []Violation{{Path: "$.days[1]", Message: `must be one of "MO", "TU"`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateJSON(s, []byte(tt.args))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateJSON(%s) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromStructPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("FromStruct(string) did not panic")
+		}
+	}()
+	FromStruct("not a struct")
+}
@@ -0,0 +1,265 @@
+// Package schema checks JSON values against the subset of JSON Schema the
+// server uses to describe tool arguments.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Violation is one way in which a value fails its schema
+type Violation struct {
+	// Path locates the offending value, e.g. $.attendees[1]
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidateJSON decodes data and validates it against schema. Empty input and
+// null are treated as an empty object, since clients may omit arguments
+// entirely.
+func ValidateJSON(schema map[string]interface{}, data []byte) []Violation {
+	var value interface{} = map[string]interface{}{}
+	if len(data) > 0 && string(data) != "null" {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return []Violation{{Path: "$", Message: fmt.Sprintf("invalid JSON: %v", err)}}
+		}
+	}
+	return Validate(schema, value)
+}
+
+// Validate checks a decoded JSON value against schema and returns every
+// violation found, in a stable order. It understands type, enum, format
+// (date-time, date, email), required, properties, additionalProperties,
+// items, minimum, maximum, minLength, minItems and maxItems.
+func Validate(schema map[string]interface{}, value interface{}) []Violation {
+	var violations []Violation
+	validate(schema, value, "$", &violations)
+	return violations
+}
+
+func validate(schema map[string]interface{}, value interface{}, path string, violations *[]Violation) {
+	report := func(format string, args ...interface{}) {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types := stringList(schema["type"]); len(types) > 0 {
+		if !matchesAnyType(value, types) {
+			report("must be of type %s, got %s", strings.Join(types, " or "), typeName(value))
+			// Further checks would only repeat the type error
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"]; ok && !inEnum(value, enum) {
+		report("must be one of %s", formatEnum(enum))
+	}
+
+	switch v := value.(type) {
+	case string:
+		if format, ok := schema["format"].(string); ok {
+			if msg := checkFormat(format, v); msg != "" {
+				report("%s", msg)
+			}
+		}
+		if min, ok := number(schema["minLength"]); ok && float64(len([]rune(v))) < min {
+			report("must be at least %v characters long", min)
+		}
+
+	case float64:
+		if min, ok := number(schema["minimum"]); ok && v < min {
+			report("must be at least %v", min)
+		}
+		if max, ok := number(schema["maximum"]); ok && v > max {
+			report("must be at most %v", max)
+		}
+
+	case []interface{}:
+		if min, ok := number(schema["minItems"]); ok && float64(len(v)) < min {
+			report("must contain at least %v items", min)
+		}
+		if max, ok := number(schema["maxItems"]); ok && float64(len(v)) > max {
+			report("must contain at most %v items", max)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validate(items, item, path+"["+strconv.Itoa(i)+"]", violations)
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range stringList(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, Violation{Path: childPath(path, name), Message: "is required"})
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				validate(property, v[name], childPath(path, name), violations)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					*violations = append(*violations, Violation{Path: childPath(path, name), Message: "is not an allowed property"})
+				}
+			case map[string]interface{}:
+				validate(additional, v[name], childPath(path, name), violations)
+			}
+		}
+	}
+}
+
+func checkFormat(format, value string) string {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Sprintf("must be an RFC3339 date-time such as 2024-01-15T10:00:00Z, got %q", value)
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fmt.Sprintf("must be a date in YYYY-MM-DD format, got %q", value)
+		}
+	case "email":
+		if _, err := mail.ParseAddress(value); err != nil {
+			return fmt.Sprintf("must be an email address, got %q", value)
+		}
+	}
+	return ""
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	for _, t := range types {
+		if matchesType(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(value interface{}, t string) bool {
+	switch t {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func inEnum(value interface{}, enum interface{}) bool {
+	for _, allowed := range list(enum) {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum interface{}) string {
+	var parts []string
+	for _, allowed := range list(enum) {
+		data, _ := json.Marshal(allowed)
+		parts = append(parts, string(data))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// list normalises the slice types schemas are written with
+func list(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		return v
+	case []string:
+		out := make([]interface{}, len(v))
+		for i, s := range v {
+			out[i] = s
+		}
+		return out
+	case string:
+		return []interface{}{v}
+	}
+	return nil
+}
+
+func stringList(v interface{}) []string {
+	var out []string
+	for _, item := range list(v) {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func childPath(path, name string) string {
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return path + "[" + strconv.Quote(name) + "]"
+		}
+	}
+	return path + "." + name
+}
@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+var eventSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"eventId": map[string]interface{}{"type": "string"},
+		"orderBy": map[string]interface{}{
+			"type": "string",
+			"enum": []string{"startTime", "updated"},
+		},
+		"maxResults": map[string]interface{}{"type": "integer", "minimum": 1},
+		"start":      map[string]interface{}{"type": "string", "format": "date-time"},
+		"attendees": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string", "format": "email"},
+		},
+		"reminders": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"useDefault": map[string]interface{}{"type": "boolean"},
+			},
+			"required": []string{"useDefault"},
+		},
+	},
+	"required":             []string{"eventId"},
+	"additionalProperties": false,
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want []Violation
+	}{
+		{
+			name: "valid",
+			args: `{"eventId": "abc", "orderBy": "updated", "maxResults": 5, "start": "2024-01-15T10:00:00Z", "attendees": ["a@example.com"], "reminders": {"useDefault": true}}`,
+		},
+		{
+			name: "missing required field",
+			args: `{"orderBy": "updated"}`,
+			want: []Violation{{Path: "$.eventId", Message: "is required"}},
+		},
+		{
+			name: "omitted arguments",
+			args: ``,
+			want: []Violation{{Path: "$.eventId", Message: "is required"}},
+		},
+		{
+			name: "wrong type",
+			args: `{"eventId": 42}`,
+			want: []Violation{{Path: "$.eventId", Message: "must be of type string, got number"}},
+		},
+		{
+			name: "fraction for integer",
+			args: `{"eventId": "abc", "maxResults": 2.5}`,
+			want: []Violation{{Path: "$.maxResults", Message: "must be of type integer, got number"}},
+		},
+		{
+			name: "unknown enum value",
+			args: `{"eventId": "abc", "orderBy": "summary"}`,
+			want: []Violation{{Path: "$.orderBy", Message: `must be one of "startTime", "updated"`}},
+		},
+		{
+			name: "below minimum",
+			args: `{"eventId": "abc", "maxResults": 0}`,
+			want: []Violation{{Path: "$.maxResults", Message: "must be at least 1"}},
+		},
+		{
+			name: "bad date-time",
+			args: `{"eventId": "abc", "start": "tomorrow"}`,
+			want: []Violation{{Path: "$.start", Message: `must be an RFC3339 date-time such as 2024-01-15T10:00:00Z, got "tomorrow"`}},
+		},
+		{
+			name: "bad item",
+			args: `{"eventId": "abc", "attendees": ["a@example.com", "nobody"]}`,
+			want: []Violation{{Path: "$.attendees[1]", Message: `must be an email address, got "nobody"`}},
+		},
+		{
+			name: "nested object",
+			args: `{"eventId": "abc", "reminders": {"useDefault": "yes"}}`,
+			want: []Violation{{Path: "$.reminders.useDefault", Message: "must be of type boolean, got string"}},
+		},
+		{
+			name: "unknown property",
+			args: `{"eventId": "abc", "event id": "x"}`,
+			want: []Violation{{Path: `$["event id"]`, Message: "is not an allowed property"}},
+		},
+		{
+			name: "every violation in order",
+			args: `{"orderBy": "summary", "maxResults": "ten"}`,
+			want: []Violation{
+				{Path: "$.eventId", Message: "is required"},
+				{Path: "$.maxResults", Message: "must be of type integer, got string"},
+				{Path: "$.orderBy", Message: `must be one of "startTime", "updated"`},
+			},
+		},
+		{
+			name: "not an object",
+			args: `["abc"]`,
+			want: []Violation{{Path: "$", Message: "must be of type object, got array"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateJSON(eventSchema, []byte(tt.args))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateJSON(%s) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateJSONInvalid(t *testing.T) {
+	got := ValidateJSON(eventSchema, []byte(`{"eventId":`))
+	if len(got) != 1 || got[0].Path != "$" {
+		t.Fatalf("ValidateJSON of truncated input = %v, want one violation at $", got)
+	}
+}
@@ -0,0 +1,398 @@
+package types
+
+import "time"
+
+// EmailMessage represents an email message
+type EmailMessage struct {
+	ID       string            `json:"id"`
+	ThreadID string            `json:"threadId"`
+	Subject  string            `json:"subject"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Date     string            `json:"date"`
+	Body     string            `json:"body"`
+	Labels   []string          `json:"labels"`
+	Headers  map[string]string `json:"headers"`
+}
+
+// SendEmailArgs represents arguments for sending an email
+type SendEmailArgs struct {
+	To          []string `json:"to"`
+	Subject     string   `json:"subject"`
+	Body        string   `json:"body"`
+	HTMLBody    string   `json:"htmlBody,omitempty"`
+	CC          []string `json:"cc,omitempty"`
+	BCC         []string `json:"bcc,omitempty"`
+	ThreadID    string   `json:"threadId,omitempty"`
+	InReplyTo   string   `json:"inReplyTo,omitempty"`
+	Attachments []string `json:"attachments,omitempty"`
+}
+
+// ReadEmailArgs represents arguments for reading an email
+type ReadEmailArgs struct {
+	MessageID string `json:"messageId"`
+}
+
+// SearchEmailsArgs represents arguments for searching emails
+type SearchEmailsArgs struct {
+	Query      string `json:"query"`
+	MaxResults int    `json:"maxResults,omitempty"`
+}
+
+// ModifyEmailArgs represents arguments for modifying an email
+type ModifyEmailArgs struct {
+	MessageID       string   `json:"messageId"`
+	AddLabelIDs     []string `json:"addLabelIds,omitempty"`
+	RemoveLabelIDs  []string `json:"removeLabelIds,omitempty"`
+}
+
+// DeleteEmailArgs represents arguments for deleting an email
+type DeleteEmailArgs struct {
+	MessageID string `json:"messageId"`
+}
+
+// CreateLabelArgs represents arguments for creating a label
+type CreateLabelArgs struct {
+	Name                   string `json:"name"`
+	MessageListVisibility string `json:"messageListVisibility,omitempty"`
+	LabelListVisibility   string `json:"labelListVisibility,omitempty"`
+}
+
+// UpdateLabelArgs represents arguments for updating a label
+type UpdateLabelArgs struct {
+	ID                     string `json:"id"`
+	Name                   string `json:"name,omitempty"`
+	MessageListVisibility string `json:"messageListVisibility,omitempty"`
+	LabelListVisibility   string `json:"labelListVisibility,omitempty"`
+}
+
+// DeleteLabelArgs represents arguments for deleting a label
+type DeleteLabelArgs struct {
+	ID string `json:"id"`
+}
+
+// BatchModifyEmailsArgs represents arguments for batch modifying emails
+type BatchModifyEmailsArgs struct {
+	MessageIDs     []string `json:"messageIds"`
+	AddLabelIDs    []string `json:"addLabelIds,omitempty"`
+	RemoveLabelIDs []string `json:"removeLabelIds,omitempty"`
+	BatchSize      int      `json:"batchSize,omitempty"`
+}
+
+// BatchDeleteEmailsArgs represents arguments for batch deleting emails
+type BatchDeleteEmailsArgs struct {
+	MessageIDs []string `json:"messageIds"`
+	BatchSize  int      `json:"batchSize,omitempty"`
+}
+
+// DownloadAttachmentArgs represents arguments for downloading an attachment
+type DownloadAttachmentArgs struct {
+	MessageID    string `json:"messageId"`
+	AttachmentID string `json:"attachmentId"`
+	Filename     string `json:"filename,omitempty"`
+	SavePath     string `json:"savePath,omitempty"`
+}
+
+// GetContactsArgs represents arguments for getting contacts
+type GetContactsArgs struct {
+	MaxResults int    `json:"maxResults,omitempty"`
+	Query      string `json:"query,omitempty"`
+}
+
+// EmailAnalyticsArgs represents arguments for email analytics
+type EmailAnalyticsArgs struct {
+	Days      int    `json:"days,omitempty"`
+	Query     string `json:"query,omitempty"`
+	GroupBy   string `json:"groupBy,omitempty"`
+}
+
+// GmailLabel represents a Gmail label
+type GmailLabel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Contact represents a contact
+type Contact struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone,omitempty"`
+	Organization string `json:"organization,omitempty"`
+}
+
+// Calendar types
+
+// CalendarEvent represents a calendar event
+type CalendarEvent struct {
+	ID            string             `json:"id"`
+	Summary       string             `json:"summary"`
+	Description   string             `json:"description,omitempty"`
+	Location      string             `json:"location,omitempty"`
+	StartTime     string             `json:"startTime,omitempty"`
+	EndTime       string             `json:"endTime,omitempty"`
+	StartDate     string             `json:"startDate,omitempty"`
+	EndDate       string             `json:"endDate,omitempty"`
+	StartTimeZone string             `json:"startTimeZone,omitempty"`
+	EndTimeZone   string             `json:"endTimeZone,omitempty"`
+	AllDay        bool               `json:"allDay,omitempty"`
+	Creator       string             `json:"creator,omitempty"`
+	Organizer     string             `json:"organizer,omitempty"`
+	Status        string             `json:"status,omitempty"`
+	HTMLLink      string             `json:"htmlLink,omitempty"`
+	Created       string             `json:"created,omitempty"`
+	Updated       string             `json:"updated,omitempty"`
+	Attendees     []*EventAttendee   `json:"attendees,omitempty"`
+	Reminders     []*EventReminder   `json:"reminders,omitempty"`
+	Recurrence    []string           `json:"recurrence,omitempty"`
+
+	// Set on instances of recurring events
+	RecurringEventID  string `json:"recurringEventId,omitempty"`
+	OriginalStartTime string `json:"originalStartTime,omitempty"`
+}
+
+// CreateEventArgs represents arguments for creating an event
+type CreateEventArgs struct {
+	Summary        string           `json:"summary" required:"true" description:"Event title/summary"`
+	Description    string           `json:"description,omitempty" description:"Event description"`
+	Location       string           `json:"location,omitempty" description:"Event location"`
+	StartTime      string           `json:"startTime,omitempty" format:"date-time" description:"Start time in RFC3339 format (e.g., '2023-12-01T10:00:00Z')"`
+	EndTime        string           `json:"endTime,omitempty" format:"date-time" description:"End time in RFC3339 format (e.g., '2023-12-01T11:00:00Z')"`
+	StartDate      string           `json:"startDate,omitempty" format:"date" description:"Start date for all-day events (YYYY-MM-DD format)"`
+	EndDate        string           `json:"endDate,omitempty" format:"date" description:"End date for all-day events (YYYY-MM-DD format)"`
+	TimeZone       string           `json:"timeZone,omitempty" description:"Time zone (e.g., 'America/New_York')"`
+	AllDay         bool             `json:"allDay,omitempty" description:"Whether this is an all-day event"`
+	CalendarID     string           `json:"calendarId,omitempty" description:"Calendar ID (defaults to primary calendar)"`
+	Attendees      []string         `json:"attendees,omitempty" format:"email" description:"List of attendee email addresses"`
+	Reminders      []*EventReminder `json:"reminders,omitempty" description:"Reminders overriding the calendar's defaults"`
+	Recurrence     []string         `json:"recurrence,omitempty" description:"RFC 5545 RRULE, EXRULE, RDATE and EXDATE lines (e.g., 'RRULE:FREQ=WEEKLY;BYDAY=MO')"`
+	RecurrenceRule *RecurrenceRule  `json:"recurrenceRule,omitempty" description:"Structured repeat rule, added to recurrence as an RRULE"`
+}
+
+// UpdateEventArgs represents arguments for updating an event
+type UpdateEventArgs struct {
+	EventID        string           `json:"eventId" required:"true" description:"ID of the event to update"`
+	CalendarID     string           `json:"calendarId,omitempty" description:"Calendar ID (defaults to primary calendar)"`
+	Summary        string           `json:"summary,omitempty" description:"Event title/summary"`
+	Description    string           `json:"description,omitempty" description:"Event description"`
+	Location       string           `json:"location,omitempty" description:"Event location"`
+	StartTime      string           `json:"startTime,omitempty" format:"date-time" description:"Start time in RFC3339 format"`
+	EndTime        string           `json:"endTime,omitempty" format:"date-time" description:"End time in RFC3339 format"`
+	TimeZone       string           `json:"timeZone,omitempty" description:"Time zone"`
+	Attendees      []string         `json:"attendees,omitempty" format:"email" description:"List of attendee email addresses, replacing the current ones"`
+	Reminders      []*EventReminder `json:"reminders,omitempty" description:"Reminders overriding the calendar's defaults"`
+	Recurrence     []string         `json:"recurrence,omitempty" description:"RFC 5545 recurrence lines, replacing the current ones"`
+	RecurrenceRule *RecurrenceRule  `json:"recurrenceRule,omitempty" description:"Structured repeat rule, replacing the current recurrence together with recurrence"`
+	Scope          string           `json:"scope,omitempty" enum:"this,following,all" description:"For an instance of a recurring event: change only this occurrence, this and the following ones, or the whole series"`
+}
+
+// RecurrenceRule describes a repeating event without hand-written RFC 5545
+type RecurrenceRule struct {
+	Frequency  string   `json:"frequency" required:"true" enum:"DAILY,WEEKLY,MONTHLY,YEARLY" description:"How often the event repeats"`
+	Interval   int      `json:"interval,omitempty" minimum:"1" description:"Repeat every this many periods (default 1)"`
+	ByDay      []string `json:"byDay,omitempty" description:"Weekdays as MO to SU; monthly and yearly rules may prefix an ordinal (e.g., '1MO', '-1FR')"`
+	ByMonthDay []int    `json:"byMonthDay,omitempty" description:"Days of the month, 1 to 31 or negative to count from the end"`
+	ByMonth    []int    `json:"byMonth,omitempty" description:"Months, 1 to 12"`
+	Until      string   `json:"until,omitempty" description:"Last day (YYYY-MM-DD) or time (RFC3339) an occurrence may start"`
+	Count      int      `json:"count,omitempty" minimum:"1" description:"Number of occurrences"`
+}
+
+// ListEventsArgs represents arguments for listing events
+type ListEventsArgs struct {
+	CalendarID   string `json:"calendarId,omitempty" description:"Calendar ID (defaults to primary calendar)"`
+	TimeMin      string `json:"timeMin,omitempty" format:"date-time" description:"Lower bound for event start time (RFC3339 format)"`
+	TimeMax      string `json:"timeMax,omitempty" format:"date-time" description:"Upper bound for event start time (RFC3339 format)"`
+	MaxResults   int    `json:"maxResults,omitempty" minimum:"1" description:"Maximum number of events to return"`
+	Query        string `json:"query,omitempty" description:"Free text search terms"`
+	OrderBy      string `json:"orderBy,omitempty" enum:"startTime,updated" description:"Order of the events"`
+	SingleEvents bool   `json:"singleEvents,omitempty" description:"Expand recurring events into their instances (implied by orderBy startTime)"`
+	Cursor       string `json:"cursor,omitempty" description:"nextCursor of an earlier listing with the same arguments, to continue it"`
+}
+
+// ListInstancesArgs represents arguments for listing the instances of a
+// recurring event
+type ListInstancesArgs struct {
+	CalendarID string `json:"calendarId,omitempty" description:"Calendar ID (defaults to primary calendar)"`
+	EventID    string `json:"eventId" required:"true" description:"ID of the recurring event"`
+	TimeMin    string `json:"timeMin,omitempty" format:"date-time" description:"Lower bound for instance end time (RFC3339 format)"`
+	TimeMax    string `json:"timeMax,omitempty" format:"date-time" description:"Upper bound for instance start time (RFC3339 format)"`
+	MaxResults int    `json:"maxResults,omitempty" minimum:"1" description:"Maximum number of instances to return"`
+	Cursor     string `json:"cursor,omitempty" description:"nextCursor of an earlier listing with the same arguments, to continue it"`
+}
+
+// SearchEventsArgs represents arguments for searching events across
+// calendars
+type SearchEventsArgs struct {
+	Query       string   `json:"query" required:"true" description:"Free text search terms, matched against summary, description, location and attendees"`
+	CalendarIDs []string `json:"calendarIds,omitempty" description:"Calendars to search (defaults to every calendar in the calendar list)"`
+	TimeMin     string   `json:"timeMin,omitempty" format:"date-time" description:"Lower bound for event end time (RFC3339 format)"`
+	TimeMax     string   `json:"timeMax,omitempty" format:"date-time" description:"Upper bound for event start time (RFC3339 format)"`
+	MaxResults  int      `json:"maxResults,omitempty" minimum:"1" description:"Maximum number of events to return"`
+}
+
+// EventMatch is an event found by a search, with the calendar it was found
+// in and how well it matches
+type EventMatch struct {
+	*CalendarEvent
+	CalendarID      string   `json:"calendarId"`
+	CalendarSummary string   `json:"calendarSummary,omitempty"`
+	AlsoIn          []string `json:"alsoInCalendars,omitempty"`
+	Score           float64  `json:"score"`
+	MatchedFields   []string `json:"matchedFields,omitempty"`
+}
+
+// SearchEventsResult represents the result of a search across calendars
+type SearchEventsResult struct {
+	Events            []*EventMatch `json:"events"`
+	SearchedCalendars []string      `json:"searchedCalendars"`
+	// FailedCalendars maps the calendars that could not be searched to
+	// why
+	FailedCalendars map[string]string `json:"failedCalendars,omitempty"`
+}
+
+// DeleteEventArgs represents arguments for deleting an event
+type DeleteEventArgs struct {
+	CalendarID string `json:"calendarId,omitempty" description:"Calendar ID (defaults to primary calendar)"`
+	EventID    string `json:"eventId" required:"true" description:"ID of the event to delete"`
+	Scope      string `json:"scope,omitempty" enum:"this,following,all" description:"For an instance of a recurring event: delete only this occurrence, this and the following ones, or the whole series"`
+}
+
+// GetEventArgs represents arguments for retrieving an event
+type GetEventArgs struct {
+	CalendarID string `json:"calendarId,omitempty" description:"Calendar ID (defaults to primary calendar)"`
+	EventID    string `json:"eventId" required:"true" description:"ID of the event to retrieve"`
+}
+
+// EventAttendee represents an event attendee
+type EventAttendee struct {
+	Email          string `json:"email"`
+	DisplayName    string `json:"displayName,omitempty"`
+	ResponseStatus string `json:"responseStatus,omitempty"`
+	Organizer      bool   `json:"organizer,omitempty"`
+}
+
+// EventReminder represents an event reminder
+type EventReminder struct {
+	Method  string `json:"method" required:"true" enum:"email,popup" description:"How the reminder is delivered"`
+	Minutes int    `json:"minutes" required:"true" minimum:"0" description:"Minutes before the event start"`
+}
+
+// Calendar represents a calendar
+type Calendar struct {
+	ID          string `json:"id"`
+	Summary     string `json:"summary"`
+	Description string `json:"description,omitempty"`
+	Primary     bool   `json:"primary,omitempty"`
+	AccessRole  string `json:"accessRole,omitempty"`
+	TimeZone    string `json:"timeZone,omitempty"`
+}
+
+// CreateCalendarArgs represents arguments for creating a calendar
+type CreateCalendarArgs struct {
+	Summary     string `json:"summary" required:"true" description:"Calendar title/name"`
+	Description string `json:"description,omitempty" description:"Calendar description"`
+	TimeZone    string `json:"timeZone,omitempty" description:"Calendar time zone"`
+}
+
+// ListCalendarsArgs represents arguments for listing calendars
+type ListCalendarsArgs struct {
+	MaxResults int    `json:"maxResults,omitempty" minimum:"1" description:"Maximum number of calendars to return"`
+	Cursor     string `json:"cursor,omitempty" description:"nextCursor of an earlier listing, to continue it"`
+}
+
+// GetCalendarArgs represents arguments for retrieving a calendar
+type GetCalendarArgs struct {
+	CalendarID string `json:"calendarId,omitempty" description:"Calendar ID (defaults to primary calendar)"`
+}
+
+// DeleteCalendarArgs represents arguments for deleting a calendar
+type DeleteCalendarArgs struct {
+	CalendarID string `json:"calendarId" required:"true" description:"Calendar ID to delete"`
+}
+
+// FreeBusyArgs represents arguments for free/busy query
+type FreeBusyArgs struct {
+	TimeMin     string   `json:"timeMin" required:"true" format:"date-time" description:"Lower bound for free/busy query (RFC3339 format)"`
+	TimeMax     string   `json:"timeMax" required:"true" format:"date-time" description:"Upper bound for free/busy query (RFC3339 format)"`
+	CalendarIDs []string `json:"calendarIds" required:"true" description:"List of calendar IDs to query"`
+}
+
+// FreeBusyResponse represents free/busy response
+type FreeBusyResponse struct {
+	TimeMin   string                       `json:"timeMin"`
+	TimeMax   string                       `json:"timeMax"`
+	Calendars map[string]*FreeBusyCalendar `json:"calendars"`
+}
+
+// FreeBusyCalendar represents free/busy info for a calendar
+type FreeBusyCalendar struct {
+	Busy []*TimePeriod `json:"busy"`
+	// Errors are the reasons the calendar could not be queried, such as
+	// notFound when it does not exist or is not shared with the user
+	Errors []string `json:"errors,omitempty"`
+}
+
+// TimePeriod represents a time period
+type TimePeriod struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// FindMeetingTimesArgs represents arguments for finding times everyone is
+// free to meet
+type FindMeetingTimesArgs struct {
+	Attendees          []string        `json:"attendees" required:"true" description:"Calendar IDs or email addresses of everyone who must be free; include 'primary' for your own calendar"`
+	DurationMinutes    int             `json:"durationMinutes" required:"true" minimum:"1" description:"Length of the meeting in minutes"`
+	TimeMin            string          `json:"timeMin" required:"true" format:"date-time" description:"Start of the window to search (RFC3339 format)"`
+	TimeMax            string          `json:"timeMax" required:"true" format:"date-time" description:"End of the window to search (RFC3339 format)"`
+	WorkingHours       []*WorkingHours `json:"workingHours,omitempty" description:"Hours the meeting must fall within, one entry per time zone to respect (defaults to 09:00-17:00 Monday to Friday in the primary calendar's time zone)"`
+	BufferMinutes      int             `json:"bufferMinutes,omitempty" minimum:"0" description:"Free minutes to keep between the meeting and other events"`
+	GranularityMinutes int             `json:"granularityMinutes,omitempty" minimum:"1" description:"Minutes between candidate start times, counted from midnight in the first working hours' time zone (default 30)"`
+	MaxResults         int             `json:"maxResults,omitempty" minimum:"1" description:"Maximum number of slots to return (default 5)"`
+}
+
+// WorkingHours are the hours of the week meetings may be held at in one
+// time zone
+type WorkingHours struct {
+	TimeZone string   `json:"timeZone" required:"true" description:"IANA time zone (e.g., 'Europe/London')"`
+	Start    string   `json:"start,omitempty" description:"Start of the working day as HH:MM (default 09:00)"`
+	End      string   `json:"end,omitempty" description:"End of the working day as HH:MM, 24:00 for midnight (default 17:00)"`
+	Days     []string `json:"days,omitempty" enum:"MO,TU,WE,TH,FR,SA,SU" description:"Working days (default MO to FR)"`
+}
+
+// MeetingSlot is a time every attendee is free
+type MeetingSlot struct {
+	Start string  `json:"start"`
+	End   string  `json:"end"`
+	Score float64 `json:"score"`
+	// LocalTimes renders the slot in each working hours' time zone
+	LocalTimes map[string]string `json:"localTimes,omitempty"`
+}
+
+// MeetingTimesResult represents the slots found for a meeting, best first
+type MeetingTimesResult struct {
+	Slots []*MeetingSlot `json:"slots"`
+	// UncheckedAttendees maps the attendees whose calendars could not be
+	// queried to why; the slots may not suit them
+	UncheckedAttendees map[string]string `json:"uncheckedAttendees,omitempty"`
+}
+
+// WatchChannel represents a push notification channel registered with Google
+type WatchChannel struct {
+	ID         string    `json:"id"`
+	ResourceID string    `json:"resourceId"`
+	CalendarID string    `json:"calendarId"`
+	Token      string    `json:"-"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// WatchNotification represents a push notification received on a channel
+type WatchNotification struct {
+	ChannelID     string    `json:"channelId"`
+	Token         string    `json:"-"`
+	ResourceID    string    `json:"resourceId"`
+	ResourceURI   string    `json:"resourceUri,omitempty"`
+	ResourceState string    `json:"resourceState"`
+	MessageNumber int64     `json:"messageNumber,omitempty"`
+	Expiration    time.Time `json:"expiration"`
+}